@@ -0,0 +1,710 @@
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"maps"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/maragudk/honeycomb-cli/honeycomb"
+)
+
+// manifest is a resource definition in a YAML file read by apply and plan.
+type manifest struct {
+	Kind    string          `json:"kind"`
+	Dataset string          `json:"dataset,omitempty"`
+	Spec    json.RawMessage `json:"spec"`
+}
+
+const (
+	kindTrigger = "trigger"
+	kindSLO     = "slo"
+	kindBoard   = "board"
+)
+
+// desiredState is the resources defined in a directory of manifests.
+type desiredState struct {
+	Triggers []datasetResource[honeycomb.Trigger]
+	SLOs     []datasetResource[honeycomb.SLO]
+	Boards   []boardFile
+}
+
+// datasetResource is a resource that lives in a dataset.
+type datasetResource[T any] struct {
+	Dataset string
+	Spec    T
+}
+
+// datasets used by triggers and SLOs in the desired state, sorted.
+func (s *desiredState) datasets() []string {
+	seen := map[string]bool{}
+	for _, t := range s.Triggers {
+		seen[t.Dataset] = true
+	}
+	for _, slo := range s.SLOs {
+		seen[slo.Dataset] = true
+	}
+	var datasets []string
+	for d := range seen {
+		datasets = append(datasets, d)
+	}
+	sort.Strings(datasets)
+	return datasets
+}
+
+// readManifests from all .yaml and .yml files in the directory and its subdirectories.
+// A file can hold several manifests separated by "---".
+func readManifests(dir string) (*desiredState, error) {
+	var s desiredState
+	seen := map[string]string{}
+
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || (filepath.Ext(path) != ".yaml" && filepath.Ext(path) != ".yml") {
+			return nil
+		}
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+
+		return decodeYAMLDocuments(data, func(raw any) error {
+			var m manifest
+			if err := decodeYAMLValue(raw, &m); err != nil {
+				return fmt.Errorf("parsing %v: %w", path, err)
+			}
+
+			var name string
+			switch m.Kind {
+			case kindTrigger:
+				var t honeycomb.Trigger
+				if err := decodeManifestSpec(m, &t); err != nil {
+					return fmt.Errorf("parsing %v: %w", path, err)
+				}
+				s.Triggers = append(s.Triggers, datasetResource[honeycomb.Trigger]{Dataset: m.Dataset, Spec: t})
+				name = t.Name
+			case kindSLO:
+				var slo honeycomb.SLO
+				if err := decodeManifestSpec(m, &slo); err != nil {
+					return fmt.Errorf("parsing %v: %w", path, err)
+				}
+				s.SLOs = append(s.SLOs, datasetResource[honeycomb.SLO]{Dataset: m.Dataset, Spec: slo})
+				name = slo.Name
+			case kindBoard:
+				var bf boardFile
+				if err := decodeManifestSpec(m, &bf); err != nil {
+					return fmt.Errorf("parsing %v: %w", path, err)
+				}
+				for i, q := range bf.Queries {
+					if q.Dataset == "" {
+						bf.Queries[i].Dataset = m.Dataset
+					}
+					if bf.Queries[i].Dataset == "" {
+						return fmt.Errorf("%v: board %q query %v has no dataset", path, bf.Name, i+1)
+					}
+				}
+				s.Boards = append(s.Boards, bf)
+				name = bf.Name
+			default:
+				return fmt.Errorf("%v: unknown kind %q (must be trigger, slo, or board)", path, m.Kind)
+			}
+
+			if name == "" {
+				return fmt.Errorf("%v: %v has no name", path, m.Kind)
+			}
+			if m.Kind != kindBoard && m.Dataset == "" {
+				return fmt.Errorf("%v: %v %q has no dataset", path, m.Kind, name)
+			}
+
+			dataset := m.Dataset
+			if m.Kind == kindBoard {
+				dataset = ""
+			}
+			key := resourceKey(m.Kind, dataset, name)
+			if other, ok := seen[key]; ok {
+				return fmt.Errorf("%v: %v %q is also defined in %v", path, m.Kind, name, other)
+			}
+			seen[key] = path
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+func decodeManifestSpec(m manifest, v any) error {
+	if len(m.Spec) == 0 {
+		return fmt.Errorf("%v has no spec", m.Kind)
+	}
+	dec := json.NewDecoder(bytes.NewReader(m.Spec))
+	dec.DisallowUnknownFields()
+	return dec.Decode(v)
+}
+
+// resourceKey identifies a resource by kind, dataset, and name, since IDs aren't in manifests.
+func resourceKey(kind, dataset, name string) string {
+	if dataset == "" {
+		return kind + " " + name
+	}
+	return kind + " " + dataset + "/" + name
+}
+
+// ownerLabel is appended to the description of resources created by apply,
+// so --prune only deletes resources that apply manages for the given owner.
+func ownerLabel(owner string) string {
+	return fmt.Sprintf("[managed by honeycomb-cli: %v]", owner)
+}
+
+func withOwnerLabel(description, owner string) string {
+	label := ownerLabel(owner)
+	if strings.Contains(description, label) {
+		return description
+	}
+	if description == "" {
+		return label
+	}
+	return description + " " + label
+}
+
+type planAction string
+
+const (
+	planCreate planAction = "create"
+	planUpdate planAction = "update"
+	planDelete planAction = "delete"
+)
+
+func (a planAction) pastTense() string {
+	switch a {
+	case planCreate:
+		return "Created"
+	case planUpdate:
+		return "Updated"
+	default:
+		return "Deleted"
+	}
+}
+
+// planChange is a change to a single resource, and how to make it.
+type planChange struct {
+	Action planAction
+	Key    string
+	Diffs  []fieldDiff
+	apply  func(ctx context.Context) error
+}
+
+// fieldDiff is a changed field, identified by its path in the resource's JSON.
+type fieldDiff struct {
+	Path string
+	Old  any
+	New  any
+}
+
+// diffFields returns the fields in desired that differ in current.
+// Fields only in current are ignored, so API defaults for fields left out of a manifest aren't drift.
+func diffFields(path string, current, desired any) []fieldDiff {
+	switch d := desired.(type) {
+	case map[string]any:
+		c, ok := current.(map[string]any)
+		if !ok {
+			break
+		}
+		var diffs []fieldDiff
+		for _, k := range slices.Sorted(maps.Keys(d)) {
+			diffs = append(diffs, diffFields(joinPath(path, k), c[k], d[k])...)
+		}
+		return diffs
+	case []any:
+		c, ok := current.([]any)
+		if !ok || len(c) != len(d) {
+			break
+		}
+		var diffs []fieldDiff
+		for i := range d {
+			diffs = append(diffs, diffFields(fmt.Sprintf("%v[%v]", path, i), c[i], d[i])...)
+		}
+		return diffs
+	}
+
+	if reflect.DeepEqual(current, desired) {
+		return nil
+	}
+	return []fieldDiff{{Path: path, Old: current, New: desired}}
+}
+
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+// diffResources compares current and desired through their JSON representations.
+func diffResources(current, desired any) ([]fieldDiff, error) {
+	c, err := toJSONValue(current)
+	if err != nil {
+		return nil, err
+	}
+	d, err := toJSONValue(desired)
+	if err != nil {
+		return nil, err
+	}
+	return diffFields("", c, d), nil
+}
+
+func toJSONValue(v any) (any, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var value any
+	if err := json.Unmarshal(b, &value); err != nil {
+		return nil, err
+	}
+	return value, nil
+}
+
+// planOptions for [computePlan].
+type planOptions struct {
+	Owner string
+	Prune bool
+}
+
+// computePlan compares the desired state with the current state in Honeycomb.
+func computePlan(ctx context.Context, c *honeycomb.Client, s *desiredState, opts planOptions) ([]planChange, error) {
+	var changes []planChange
+	label := ownerLabel(opts.Owner)
+
+	datasets := s.datasets()
+	if opts.Prune {
+		// Owned resources can be in datasets that no manifest mentions anymore
+		all, err := c.ListDatasets(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("listing datasets: %w", err)
+		}
+		for _, d := range all {
+			if !slices.Contains(datasets, d.Slug) {
+				datasets = append(datasets, d.Slug)
+			}
+		}
+		sort.Strings(datasets)
+	}
+
+	for _, dataset := range datasets {
+		triggers, err := c.ListTriggers(ctx, dataset)
+		if err != nil {
+			return nil, fmt.Errorf("listing triggers in %v: %w", dataset, err)
+		}
+		cs, err := planTriggers(ctx, c, dataset, triggers, s.Triggers, opts)
+		if err != nil {
+			return nil, err
+		}
+		changes = append(changes, cs...)
+
+		slos, err := c.ListSLOs(ctx, dataset)
+		if err != nil {
+			return nil, fmt.Errorf("listing SLOs in %v: %w", dataset, err)
+		}
+		cs, err = planSLOs(c, dataset, slos, s.SLOs, opts)
+		if err != nil {
+			return nil, err
+		}
+		changes = append(changes, cs...)
+	}
+
+	boards, err := c.ListBoards(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("listing boards: %w", err)
+	}
+	byName := map[string]honeycomb.Board{}
+	for _, b := range boards {
+		byName[b.Name] = b
+	}
+	wanted := map[string]bool{}
+	for _, bf := range s.Boards {
+		wanted[bf.Name] = true
+		bf.Description = withOwnerLabel(bf.Description, opts.Owner)
+		key := resourceKey(kindBoard, "", bf.Name)
+
+		current, ok := byName[bf.Name]
+		if !ok {
+			changes = append(changes, planChange{Action: planCreate, Key: key, apply: func(ctx context.Context) error {
+				board, err := createBoardFromFile(ctx, c, bf)
+				if err != nil {
+					return err
+				}
+				_, err = c.CreateBoard(ctx, board)
+				return err
+			}})
+			continue
+		}
+
+		currentFile := boardFile{
+			Name:         current.Name,
+			Description:  current.Description,
+			Style:        current.Style,
+			ColumnLayout: current.ColumnLayout,
+		}
+		for _, q := range current.Queries {
+			spec, err := c.GetQuery(ctx, q.Dataset, q.QueryID)
+			if err != nil {
+				return nil, fmt.Errorf("getting query %v for board %q: %w", q.QueryID, current.Name, err)
+			}
+			currentFile.Queries = append(currentFile.Queries, boardFileQuery{
+				Caption:    q.Caption,
+				QueryStyle: q.QueryStyle,
+				Dataset:    q.Dataset,
+				Query:      *spec,
+			})
+		}
+
+		diffs, err := diffResources(currentFile, bf)
+		if err != nil {
+			return nil, err
+		}
+		if len(diffs) == 0 {
+			continue
+		}
+		id := current.ID
+		changes = append(changes, planChange{Action: planUpdate, Key: key, Diffs: diffs, apply: func(ctx context.Context) error {
+			board, err := createBoardFromFile(ctx, c, bf)
+			if err != nil {
+				return err
+			}
+			board.ID = id
+			_, err = c.UpdateBoard(ctx, board)
+			return err
+		}})
+	}
+	if opts.Prune {
+		for _, b := range boards {
+			if wanted[b.Name] || !strings.Contains(b.Description, label) {
+				continue
+			}
+			id := b.ID
+			changes = append(changes, planChange{Action: planDelete, Key: resourceKey(kindBoard, "", b.Name), apply: func(ctx context.Context) error {
+				return c.DeleteBoard(ctx, id)
+			}})
+		}
+	}
+
+	return changes, nil
+}
+
+func planTriggers(ctx context.Context, c *honeycomb.Client, dataset string, current []honeycomb.Trigger,
+	desired []datasetResource[honeycomb.Trigger], opts planOptions) ([]planChange, error) {
+	var changes []planChange
+
+	byName := map[string]honeycomb.Trigger{}
+	for _, t := range current {
+		byName[t.Name] = t
+	}
+	wanted := map[string]bool{}
+	for _, r := range desired {
+		if r.Dataset != dataset {
+			continue
+		}
+		t := r.Spec
+		t.Description = withOwnerLabel(t.Description, opts.Owner)
+		wanted[t.Name] = true
+		key := resourceKey(kindTrigger, dataset, t.Name)
+
+		existing, ok := byName[t.Name]
+		if !ok {
+			changes = append(changes, planChange{Action: planCreate, Key: key, apply: func(ctx context.Context) error {
+				_, err := c.CreateTrigger(ctx, dataset, t)
+				return err
+			}})
+			continue
+		}
+
+		if existing.Query == nil && existing.QueryID != "" {
+			spec, err := c.GetQuery(ctx, dataset, existing.QueryID)
+			if err != nil {
+				return nil, fmt.Errorf("getting query for trigger %q: %w", existing.Name, err)
+			}
+			existing.Query = spec
+		}
+		diffs, err := diffResources(existing, t)
+		if err != nil {
+			return nil, err
+		}
+		if len(diffs) == 0 {
+			continue
+		}
+		t.ID = existing.ID
+		changes = append(changes, planChange{Action: planUpdate, Key: key, Diffs: diffs, apply: func(ctx context.Context) error {
+			_, err := c.UpdateTrigger(ctx, dataset, t)
+			return err
+		}})
+	}
+
+	if opts.Prune {
+		for _, t := range current {
+			if wanted[t.Name] || !strings.Contains(t.Description, ownerLabel(opts.Owner)) {
+				continue
+			}
+			id := t.ID
+			changes = append(changes, planChange{Action: planDelete, Key: resourceKey(kindTrigger, dataset, t.Name), apply: func(ctx context.Context) error {
+				return c.DeleteTrigger(ctx, dataset, id)
+			}})
+		}
+	}
+	return changes, nil
+}
+
+func planSLOs(c *honeycomb.Client, dataset string, current []honeycomb.SLO,
+	desired []datasetResource[honeycomb.SLO], opts planOptions) ([]planChange, error) {
+	var changes []planChange
+
+	byName := map[string]honeycomb.SLO{}
+	for _, s := range current {
+		byName[s.Name] = s
+	}
+	wanted := map[string]bool{}
+	for _, r := range desired {
+		if r.Dataset != dataset {
+			continue
+		}
+		slo := r.Spec
+		slo.Description = withOwnerLabel(slo.Description, opts.Owner)
+		wanted[slo.Name] = true
+		key := resourceKey(kindSLO, dataset, slo.Name)
+
+		existing, ok := byName[slo.Name]
+		if !ok {
+			changes = append(changes, planChange{Action: planCreate, Key: key, apply: func(ctx context.Context) error {
+				_, err := c.CreateSLO(ctx, dataset, slo)
+				return err
+			}})
+			continue
+		}
+
+		diffs, err := diffResources(existing, slo)
+		if err != nil {
+			return nil, err
+		}
+		if len(diffs) == 0 {
+			continue
+		}
+		slo.ID = existing.ID
+		changes = append(changes, planChange{Action: planUpdate, Key: key, Diffs: diffs, apply: func(ctx context.Context) error {
+			_, err := c.UpdateSLO(ctx, dataset, slo)
+			return err
+		}})
+	}
+
+	if opts.Prune {
+		for _, s := range current {
+			if wanted[s.Name] || !strings.Contains(s.Description, ownerLabel(opts.Owner)) {
+				continue
+			}
+			id := s.ID
+			changes = append(changes, planChange{Action: planDelete, Key: resourceKey(kindSLO, dataset, s.Name), apply: func(ctx context.Context) error {
+				return c.DeleteSLO(ctx, dataset, id)
+			}})
+		}
+	}
+	return changes, nil
+}
+
+// createBoardFromFile creates the board's queries and returns the board referring to them.
+func createBoardFromFile(ctx context.Context, c *honeycomb.Client, bf boardFile) (honeycomb.Board, error) {
+	board := honeycomb.Board{
+		Name:         bf.Name,
+		Description:  bf.Description,
+		Style:        bf.Style,
+		ColumnLayout: bf.ColumnLayout,
+	}
+	for i, q := range bf.Queries {
+		query, err := c.CreateQuery(ctx, q.Dataset, q.Query)
+		if err != nil {
+			return board, fmt.Errorf("creating query %v: %w", i+1, err)
+		}
+		board.Queries = append(board.Queries, honeycomb.BoardQuery{
+			Caption:    q.Caption,
+			QueryStyle: q.QueryStyle,
+			Dataset:    q.Dataset,
+			QueryID:    query.ID,
+		})
+	}
+	return board, nil
+}
+
+// printPlan in a format similar to Terraform's, with a summary line.
+func printPlan(w io.Writer, changes []planChange) {
+	if len(changes) == 0 {
+		fmt.Fprintln(w, "No changes. Honeycomb matches the manifests.")
+		return
+	}
+
+	counts := map[planAction]int{}
+	for _, ch := range changes {
+		counts[ch.Action]++
+		switch ch.Action {
+		case planCreate:
+			fmt.Fprintf(w, "+ %v\n", ch.Key)
+		case planUpdate:
+			fmt.Fprintf(w, "~ %v\n", ch.Key)
+			for _, d := range ch.Diffs {
+				fmt.Fprintf(w, "    %v: %v => %v\n", d.Path, formatDiffValue(d.Old), formatDiffValue(d.New))
+			}
+		case planDelete:
+			fmt.Fprintf(w, "- %v\n", ch.Key)
+		}
+	}
+	fmt.Fprintf(w, "\nPlan: %v to create, %v to update, %v to delete.\n",
+		counts[planCreate], counts[planUpdate], counts[planDelete])
+}
+
+func formatDiffValue(v any) string {
+	if v == nil {
+		return "(none)"
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(b)
+}
+
+func addPlanFlags(cmd *cobra.Command) {
+	cmd.Flags().StringP("file", "f", "", "Directory of YAML manifests (required)")
+	_ = cmd.MarkFlagRequired("file")
+	cmd.Flags().Bool("prune", false, "Delete resources owned by --owner that are no longer in the manifests")
+	cmd.Flags().String("owner", "default", "Owner label added to descriptions of managed resources, used by --prune")
+}
+
+// planFromFlags reads the manifests and computes the plan.
+func planFromFlags(cmd *cobra.Command, c *honeycomb.Client) ([]planChange, error) {
+	dir, _ := cmd.Flags().GetString("file")
+	prune, _ := cmd.Flags().GetBool("prune")
+	owner, _ := cmd.Flags().GetString("owner")
+
+	s, err := readManifests(dir)
+	if err != nil {
+		return nil, err
+	}
+	return computePlan(cmd.Context(), c, s, planOptions{Owner: owner, Prune: prune})
+}
+
+const manifestHelp = `Each YAML file in the directory holds one or more manifests separated by "---":
+
+  kind: trigger
+  dataset: api
+  spec:
+    name: High error rate
+    frequency: 300
+    threshold:
+      op: ">"
+      value: 10
+    query:
+      calculations:
+        - op: COUNT
+      filters:
+        - column: status_code
+          op: ">="
+          value: 500
+    recipients:
+      - id: abc123
+
+Kinds are trigger, slo, and board. The spec has the same fields as the API.
+Resources are matched by name, per dataset for triggers and SLOs. Board queries without
+a dataset use the manifest's dataset.
+
+Managed resources get an owner label in their description. With --prune, resources with
+the label that are no longer in the manifests are deleted.`
+
+func newPlanCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "plan",
+		Short: "Show what apply would change, and exit with code 2 on drift",
+		Long: `Compare YAML manifests with triggers, SLOs, and boards in Honeycomb and show the
+changes apply would make. Exits with code 2 when there are changes, so CI can detect drift,
+and with code 1 when the plan can't be made, such as for invalid manifests or API errors.
+
+` + manifestHelp,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c := newClient(cmd)
+
+			changes, err := planFromFlags(cmd, c)
+			if err != nil {
+				return err
+			}
+
+			printPlan(cmd.OutOrStdout(), changes)
+			if len(changes) > 0 {
+				return &ExitError{Code: 2, Err: fmt.Errorf("drift detected: %v change(s) pending", len(changes))}
+			}
+			return nil
+		},
+	}
+	addPlanFlags(cmd)
+	return cmd
+}
+
+func newApplyCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "apply",
+		Short: "Create, update, and delete triggers, SLOs, and boards to match YAML manifests",
+		Long: `Compare YAML manifests with triggers, SLOs, and boards in Honeycomb, show the plan,
+and apply it after confirmation.
+
+` + manifestHelp,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c := newClient(cmd)
+			out := cmd.OutOrStdout()
+			yes, _ := cmd.Flags().GetBool("yes")
+
+			changes, err := planFromFlags(cmd, c)
+			if err != nil {
+				return err
+			}
+
+			printPlan(out, changes)
+			if len(changes) == 0 {
+				return nil
+			}
+
+			if !yes {
+				fmt.Fprintf(out, "Apply these changes? [y/N]: ")
+				answer, err := bufio.NewReader(cmd.InOrStdin()).ReadString('\n')
+				if err != nil && err != io.EOF {
+					return err
+				}
+				if a := strings.ToLower(strings.TrimSpace(answer)); a != "y" && a != "yes" {
+					return fmt.Errorf("apply cancelled")
+				}
+			}
+
+			for _, ch := range changes {
+				if err := ch.apply(cmd.Context()); err != nil {
+					return fmt.Errorf("%v %v: %w", ch.Action, ch.Key, err)
+				}
+				fmt.Fprintf(out, "%v %v\n", ch.Action.pastTense(), ch.Key)
+			}
+			fmt.Fprintf(out, "Applied %v change(s)\n", len(changes))
+			return nil
+		},
+	}
+	addPlanFlags(cmd)
+	cmd.Flags().BoolP("yes", "y", false, "Apply without asking for confirmation")
+	return cmd
+}
@@ -0,0 +1,331 @@
+package cmd_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"maragu.dev/is"
+
+	"github.com/maragudk/honeycomb-cli/cmd"
+	"github.com/maragudk/honeycomb-cli/honeycomb"
+)
+
+const testManifests = `kind: trigger
+dataset: api
+spec:
+  name: High error rate
+  threshold:
+    op: ">"
+    value: 20
+  query:
+    calculations:
+      - op: COUNT
+---
+kind: slo
+dataset: api
+spec:
+  name: Availability
+  sli:
+    alias: is_good
+  target_per_million: 999000
+  time_period_days: 30
+`
+
+func writeManifests(t *testing.T, content string) string {
+	t.Helper()
+	dir := t.TempDir()
+	is.NotError(t, os.WriteFile(filepath.Join(dir, "api.yaml"), []byte(content), 0600))
+	return dir
+}
+
+func TestPlanCommand(t *testing.T) {
+	t.Run("shows field-level changes and fails on drift", func(t *testing.T) {
+		// An existing trigger with threshold 10, no SLOs, and an unmanaged board plus an owned board that is not in the manifests
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch r.Method + " " + r.URL.Path {
+			case "GET /1/datasets":
+				_ = json.NewEncoder(w).Encode([]honeycomb.Dataset{{Name: "API", Slug: "api"}})
+			case "GET /1/triggers/api":
+				_ = json.NewEncoder(w).Encode([]honeycomb.Trigger{{
+					ID:          "t1",
+					Name:        "High error rate",
+					Description: "[managed by honeycomb-cli: default]",
+					Frequency:   900,
+					Threshold:   honeycomb.TriggerThreshold{Op: ">", Value: 10},
+					QueryID:     "q1",
+				}})
+			case "GET /1/queries/api/q1":
+				_ = json.NewEncoder(w).Encode(honeycomb.QuerySpec{Calculations: []honeycomb.Calculation{{Op: "COUNT"}}, TimeRange: 900})
+			case "GET /1/slos/api":
+				_, _ = w.Write([]byte(`[]`))
+			case "GET /1/boards":
+				_ = json.NewEncoder(w).Encode([]honeycomb.Board{
+					{ID: "b1", Name: "Clicked together"},
+					{ID: "b2", Name: "Old board", Description: "[managed by honeycomb-cli: default]"},
+				})
+			default:
+				t.Errorf("unexpected request %v %v", r.Method, r.URL.Path)
+				http.Error(w, `{"error": "not found"}`, http.StatusNotFound)
+			}
+		}))
+		defer server.Close()
+
+		var buf bytes.Buffer
+		root := cmd.NewRootCommand()
+		root.SetOut(&buf)
+		root.SetArgs([]string{"plan", "-f", writeManifests(t, testManifests), "--api-key", "test", "--api-url", server.URL})
+
+		err := root.Execute()
+		is.True(t, err != nil)
+		is.True(t, contains(err.Error(), "drift detected: 2 change(s) pending"))
+		var exitErr *cmd.ExitError
+		is.True(t, errors.As(err, &exitErr))
+		is.Equal(t, 2, exitErr.Code)
+
+		output := buf.String()
+		is.True(t, contains(output, "~ trigger api/High error rate"))
+		is.True(t, contains(output, "threshold.value: 10 => 20"))
+		is.True(t, !contains(output, "frequency"))
+		is.True(t, contains(output, "+ slo api/Availability"))
+		is.True(t, !contains(output, "Old board"))
+		is.True(t, contains(output, "Plan: 1 to create, 1 to update, 0 to delete."))
+	})
+
+	t.Run("includes owned resources to delete with prune", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch r.Method + " " + r.URL.Path {
+			case "GET /1/datasets":
+				_ = json.NewEncoder(w).Encode([]honeycomb.Dataset{{Name: "API", Slug: "api"}})
+			case "GET /1/triggers/api":
+				_ = json.NewEncoder(w).Encode([]honeycomb.Trigger{{
+					ID:          "t1",
+					Name:        "High error rate",
+					Description: "[managed by honeycomb-cli: default]",
+					Frequency:   900,
+					Threshold:   honeycomb.TriggerThreshold{Op: ">", Value: 10},
+					QueryID:     "q1",
+				}})
+			case "GET /1/queries/api/q1":
+				_ = json.NewEncoder(w).Encode(honeycomb.QuerySpec{Calculations: []honeycomb.Calculation{{Op: "COUNT"}}, TimeRange: 900})
+			case "GET /1/slos/api":
+				_, _ = w.Write([]byte(`[]`))
+			case "GET /1/boards":
+				_ = json.NewEncoder(w).Encode([]honeycomb.Board{
+					{ID: "b1", Name: "Clicked together"},
+					{ID: "b2", Name: "Old board", Description: "[managed by honeycomb-cli: default]"},
+				})
+			default:
+				t.Errorf("unexpected request %v %v", r.Method, r.URL.Path)
+				http.Error(w, `{"error": "not found"}`, http.StatusNotFound)
+			}
+		}))
+		defer server.Close()
+
+		var buf bytes.Buffer
+		root := cmd.NewRootCommand()
+		root.SetOut(&buf)
+		root.SetArgs([]string{"plan", "-f", writeManifests(t, testManifests), "--prune", "--api-key", "test", "--api-url", server.URL})
+
+		_ = root.Execute()
+
+		output := buf.String()
+		is.True(t, contains(output, "- board Old board"))
+		is.True(t, !contains(output, "Clicked together"))
+	})
+
+	t.Run("deletes owned resources in datasets without manifests with prune", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch r.Method + " " + r.URL.Path {
+			case "GET /1/datasets":
+				_ = json.NewEncoder(w).Encode([]honeycomb.Dataset{{Name: "API", Slug: "api"}})
+			case "GET /1/triggers/api":
+				_ = json.NewEncoder(w).Encode([]honeycomb.Trigger{
+					{ID: "t1", Name: "High error rate", Description: "[managed by honeycomb-cli: default]"},
+					{ID: "t2", Name: "Clicked together"},
+				})
+			case "GET /1/slos/api":
+				_ = json.NewEncoder(w).Encode([]honeycomb.SLO{{ID: "s1", Name: "Availability", Description: "[managed by honeycomb-cli: default]"}})
+			case "GET /1/boards":
+				_, _ = w.Write([]byte(`[]`))
+			default:
+				t.Errorf("unexpected request %v %v", r.Method, r.URL.Path)
+				http.Error(w, `{"error": "not found"}`, http.StatusNotFound)
+			}
+		}))
+		defer server.Close()
+
+		var buf bytes.Buffer
+		root := cmd.NewRootCommand()
+		root.SetOut(&buf)
+		root.SetErr(&bytes.Buffer{})
+		root.SetArgs([]string{"plan", "-f", writeManifests(t, "kind: board\nspec:\n  name: Empty\n"), "--prune", "--api-key", "test", "--api-url", server.URL})
+
+		_ = root.Execute()
+
+		output := buf.String()
+		is.True(t, contains(output, "- trigger api/High error rate"))
+		is.True(t, contains(output, "- slo api/Availability"))
+		is.True(t, !contains(output, "Clicked together"))
+	})
+
+	t.Run("succeeds without drift", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch r.Method + " " + r.URL.Path {
+			case "GET /1/datasets":
+				_ = json.NewEncoder(w).Encode([]honeycomb.Dataset{{Name: "API", Slug: "api"}})
+			case "GET /1/triggers/api":
+				_ = json.NewEncoder(w).Encode([]honeycomb.Trigger{{
+					ID:          "t1",
+					Name:        "High error rate",
+					Description: "[managed by honeycomb-cli: default]",
+					Frequency:   900,
+					Threshold:   honeycomb.TriggerThreshold{Op: ">", Value: 10},
+					QueryID:     "q1",
+				}})
+			case "GET /1/queries/api/q1":
+				_ = json.NewEncoder(w).Encode(honeycomb.QuerySpec{Calculations: []honeycomb.Calculation{{Op: "COUNT"}}, TimeRange: 900})
+			case "GET /1/slos/api":
+				_, _ = w.Write([]byte(`[]`))
+			case "GET /1/boards":
+				_ = json.NewEncoder(w).Encode([]honeycomb.Board{
+					{ID: "b1", Name: "Clicked together"},
+					{ID: "b2", Name: "Old board", Description: "[managed by honeycomb-cli: default]"},
+				})
+			default:
+				t.Errorf("unexpected request %v %v", r.Method, r.URL.Path)
+				http.Error(w, `{"error": "not found"}`, http.StatusNotFound)
+			}
+		}))
+		defer server.Close()
+
+		manifest := strings.Replace(testManifests[:strings.Index(testManifests, "---")], "value: 20", "value: 10", 1)
+
+		var buf bytes.Buffer
+		root := cmd.NewRootCommand()
+		root.SetOut(&buf)
+		root.SetArgs([]string{"plan", "-f", writeManifests(t, manifest), "--api-key", "test", "--api-url", server.URL})
+
+		err := root.Execute()
+		is.NotError(t, err)
+		is.True(t, contains(buf.String(), "No changes."))
+	})
+
+	t.Run("errors on an unknown kind", func(t *testing.T) {
+		root := cmd.NewRootCommand()
+		root.SetOut(&bytes.Buffer{})
+		root.SetArgs([]string{"plan", "-f", writeManifests(t, "kind: dashboard\nspec:\n  name: x\n"), "--api-key", "test", "--api-url", "http://localhost"})
+
+		err := root.Execute()
+		is.True(t, err != nil)
+		is.True(t, contains(err.Error(), `unknown kind "dashboard"`))
+		var exitErr *cmd.ExitError
+		is.True(t, !errors.As(err, &exitErr))
+	})
+}
+
+func TestApplyCommand(t *testing.T) {
+	t.Run("applies the plan after confirmation", func(t *testing.T) {
+		var deleted bool
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch r.Method + " " + r.URL.Path {
+			case "GET /1/datasets":
+				_ = json.NewEncoder(w).Encode([]honeycomb.Dataset{{Name: "API", Slug: "api"}})
+			case "GET /1/triggers/api":
+				_ = json.NewEncoder(w).Encode([]honeycomb.Trigger{{
+					ID:          "t1",
+					Name:        "High error rate",
+					Description: "[managed by honeycomb-cli: default]",
+					Frequency:   900,
+					Threshold:   honeycomb.TriggerThreshold{Op: ">", Value: 10},
+					QueryID:     "q1",
+				}})
+			case "GET /1/queries/api/q1":
+				_ = json.NewEncoder(w).Encode(honeycomb.QuerySpec{Calculations: []honeycomb.Calculation{{Op: "COUNT"}}, TimeRange: 900})
+			case "GET /1/slos/api":
+				_, _ = w.Write([]byte(`[]`))
+			case "GET /1/boards":
+				_ = json.NewEncoder(w).Encode([]honeycomb.Board{
+					{ID: "b1", Name: "Clicked together"},
+					{ID: "b2", Name: "Old board", Description: "[managed by honeycomb-cli: default]"},
+				})
+			case "PUT /1/triggers/api/t1":
+				var trigger honeycomb.Trigger
+				_ = json.NewDecoder(r.Body).Decode(&trigger)
+				is.Equal(t, 20.0, trigger.Threshold.Value)
+				_ = json.NewEncoder(w).Encode(trigger)
+			case "POST /1/slos/api":
+				_ = json.NewEncoder(w).Encode(honeycomb.SLO{ID: "s1", Name: "Availability"})
+			case "DELETE /1/boards/b2":
+				deleted = true
+				w.WriteHeader(http.StatusNoContent)
+			default:
+				t.Errorf("unexpected request %v %v", r.Method, r.URL.Path)
+				http.Error(w, `{"error": "not found"}`, http.StatusNotFound)
+			}
+		}))
+		defer server.Close()
+
+		var buf bytes.Buffer
+		root := cmd.NewRootCommand()
+		root.SetOut(&buf)
+		root.SetIn(strings.NewReader("y\n"))
+		root.SetArgs([]string{"apply", "-f", writeManifests(t, testManifests), "--prune", "--api-key", "test", "--api-url", server.URL})
+
+		err := root.Execute()
+		is.NotError(t, err)
+
+		output := buf.String()
+		is.True(t, contains(output, "Updated trigger api/High error rate"))
+		is.True(t, contains(output, "Created slo api/Availability"))
+		is.True(t, contains(output, "Deleted board Old board"))
+		is.True(t, contains(output, "Applied 3 change(s)"))
+		is.True(t, deleted)
+	})
+
+	t.Run("does nothing when not confirmed", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch r.Method + " " + r.URL.Path {
+			case "GET /1/datasets":
+				_ = json.NewEncoder(w).Encode([]honeycomb.Dataset{{Name: "API", Slug: "api"}})
+			case "GET /1/triggers/api":
+				_ = json.NewEncoder(w).Encode([]honeycomb.Trigger{{
+					ID:          "t1",
+					Name:        "High error rate",
+					Description: "[managed by honeycomb-cli: default]",
+					Frequency:   900,
+					Threshold:   honeycomb.TriggerThreshold{Op: ">", Value: 10},
+					QueryID:     "q1",
+				}})
+			case "GET /1/queries/api/q1":
+				_ = json.NewEncoder(w).Encode(honeycomb.QuerySpec{Calculations: []honeycomb.Calculation{{Op: "COUNT"}}, TimeRange: 900})
+			case "GET /1/slos/api":
+				_, _ = w.Write([]byte(`[]`))
+			case "GET /1/boards":
+				_ = json.NewEncoder(w).Encode([]honeycomb.Board{
+					{ID: "b1", Name: "Clicked together"},
+					{ID: "b2", Name: "Old board", Description: "[managed by honeycomb-cli: default]"},
+				})
+			default:
+				t.Errorf("unexpected request %v %v", r.Method, r.URL.Path)
+				http.Error(w, `{"error": "not found"}`, http.StatusNotFound)
+			}
+		}))
+		defer server.Close()
+
+		root := cmd.NewRootCommand()
+		root.SetOut(&bytes.Buffer{})
+		root.SetIn(strings.NewReader("n\n"))
+		root.SetArgs([]string{"apply", "-f", writeManifests(t, testManifests), "--api-key", "test", "--api-url", server.URL})
+
+		err := root.Execute()
+		is.True(t, err != nil)
+		is.True(t, contains(err.Error(), "apply cancelled"))
+	})
+}
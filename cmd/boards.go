@@ -0,0 +1,310 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"github.com/maragudk/honeycomb-cli/honeycomb"
+)
+
+// boardFile is the YAML format for defining a board with its queries.
+type boardFile struct {
+	Name         string           `json:"name"`
+	Description  string           `json:"description,omitempty"`
+	Style        string           `json:"style,omitempty"`
+	ColumnLayout string           `json:"column_layout,omitempty"`
+	Queries      []boardFileQuery `json:"queries"`
+}
+
+type boardFileQuery struct {
+	Caption    string              `json:"caption,omitempty"`
+	QueryStyle string              `json:"query_style,omitempty"`
+	Dataset    string              `json:"dataset,omitempty"`
+	Query      honeycomb.QuerySpec `json:"query"`
+}
+
+func newBoardsCommand() *cobra.Command {
+	boardsCmd := &cobra.Command{
+		Use:   "boards",
+		Short: "Manage boards (dashboards)",
+	}
+
+	boardsCmd.AddCommand(newBoardsListCommand())
+	boardsCmd.AddCommand(newBoardsGetCommand())
+	boardsCmd.AddCommand(newBoardsCreateCommand())
+	boardsCmd.AddCommand(newBoardsCloneCommand())
+	boardsCmd.AddCommand(newBoardsDeleteCommand())
+	boardsCmd.AddCommand(newBoardsGraphImagesCommand())
+
+	return boardsCmd
+}
+
+func newBoardsListCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List all boards",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c := newClient(cmd)
+
+			boards, err := c.ListBoards(cmd.Context())
+			if err != nil {
+				return err
+			}
+
+			asJSON, _ := cmd.Flags().GetBool("json")
+			if asJSON {
+				return json.NewEncoder(cmd.OutOrStdout()).Encode(boards)
+			}
+
+			w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 2, ' ', 0)
+			fmt.Fprintln(w, "ID\tNAME\tPANELS\tDESCRIPTION")
+			for _, b := range boards {
+				fmt.Fprintf(w, "%v\t%v\t%v\t%v\n", b.ID, b.Name, len(b.Queries), b.Description)
+			}
+			return w.Flush()
+		},
+	}
+	cmd.Flags().Bool("json", false, "Output as JSON")
+	return cmd
+}
+
+func newBoardsGetCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "get <id>",
+		Aliases: []string{"show"},
+		Short:   "Show a board and the query behind each panel",
+		Args:    cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c := newClient(cmd)
+
+			board, err := c.GetBoard(cmd.Context(), args[0])
+			if err != nil {
+				return err
+			}
+
+			asJSON, _ := cmd.Flags().GetBool("json")
+			if asJSON {
+				if err := json.NewEncoder(cmd.OutOrStdout()).Encode(board); err != nil {
+					return err
+				}
+			} else {
+				fmt.Fprintf(cmd.OutOrStdout(), "ID:          %v\n", board.ID)
+				fmt.Fprintf(cmd.OutOrStdout(), "Name:        %v\n", board.Name)
+				fmt.Fprintf(cmd.OutOrStdout(), "Description: %v\n", board.Description)
+				fmt.Fprintf(cmd.OutOrStdout(), "Panels:      %v\n", len(board.Queries))
+
+				for i, q := range board.Queries {
+					fmt.Fprintf(cmd.OutOrStdout(), "\n%v. %v\n", i+1, q.Caption)
+					fmt.Fprintf(cmd.OutOrStdout(), "   Dataset: %v\n", q.Dataset)
+					fmt.Fprintf(cmd.OutOrStdout(), "   Style:   %v\n", q.QueryStyle)
+
+					spec, err := c.GetQuery(cmd.Context(), q.Dataset, q.QueryID)
+					if err != nil {
+						return fmt.Errorf("getting query %v for panel %v: %w", q.QueryID, i+1, err)
+					}
+					specJSON, err := json.Marshal(spec)
+					if err != nil {
+						return err
+					}
+					fmt.Fprintf(cmd.OutOrStdout(), "   Query:   %v\n", string(specJSON))
+				}
+			}
+
+			return showLinks(cmd, c, func(l *honeycomb.UILinks) ([]uiLink, error) {
+				return []uiLink{{Label: "Board", URL: l.Board(board.ID)}}, nil
+			})
+		},
+	}
+	cmd.Flags().Bool("json", false, "Output as JSON")
+	addLinkFlags(cmd)
+	return cmd
+}
+
+func newBoardsCreateCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "create",
+		Short: "Create a board from a YAML file",
+		Long: `Create a board from a YAML file. Each query is created in its dataset first,
+then the board is created with the resulting query IDs.
+
+Example file:
+  name: Service overview
+  description: Standard dashboard for a service
+  queries:
+    - caption: Requests by status
+      query_style: graph
+      query:
+        calculations:
+          - op: COUNT
+        breakdowns:
+          - status_code
+    - caption: Latency
+      dataset: requests
+      query:
+        calculations:
+          - op: P99
+            column: duration_ms
+
+Queries without a dataset use the one given with --dataset.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c := newClient(cmd)
+			path, _ := cmd.Flags().GetString("file")
+			dataset, _ := cmd.Flags().GetString("dataset")
+			name, _ := cmd.Flags().GetString("name")
+
+			data, err := os.ReadFile(path)
+			if err != nil {
+				return err
+			}
+
+			var bf boardFile
+			if err := decodeYAML(data, &bf); err != nil {
+				return fmt.Errorf("parsing %v: %w", path, err)
+			}
+			if name != "" {
+				bf.Name = name
+			}
+			if bf.Name == "" {
+				return fmt.Errorf("board name is required (set name in the file or use --name)")
+			}
+
+			board := honeycomb.Board{
+				Name:         bf.Name,
+				Description:  bf.Description,
+				Style:        bf.Style,
+				ColumnLayout: bf.ColumnLayout,
+			}
+
+			for i, q := range bf.Queries {
+				queryDataset := q.Dataset
+				if queryDataset == "" {
+					queryDataset = dataset
+				}
+				if queryDataset == "" {
+					return fmt.Errorf("query %v has no dataset (set dataset in the file or use --dataset)", i+1)
+				}
+
+				query, err := c.CreateQuery(cmd.Context(), queryDataset, q.Query)
+				if err != nil {
+					return fmt.Errorf("creating query %v: %w", i+1, err)
+				}
+
+				board.Queries = append(board.Queries, honeycomb.BoardQuery{
+					Caption:    q.Caption,
+					QueryStyle: q.QueryStyle,
+					Dataset:    queryDataset,
+					QueryID:    query.ID,
+				})
+			}
+
+			created, err := c.CreateBoard(cmd.Context(), board)
+			if err != nil {
+				return err
+			}
+
+			fmt.Fprintf(cmd.OutOrStdout(), "Created board %q (%v)\n", created.Name, created.ID)
+
+			return showLinks(cmd, c, func(l *honeycomb.UILinks) ([]uiLink, error) {
+				return []uiLink{{Label: "Board", URL: l.Board(created.ID)}}, nil
+			})
+		},
+	}
+	cmd.Flags().StringP("file", "f", "", "Path to the board YAML file")
+	_ = cmd.MarkFlagRequired("file")
+	cmd.Flags().String("dataset", "", "Dataset for queries that don't specify one")
+	cmd.Flags().String("name", "", "Board name (overrides the name in the file)")
+	addLinkFlags(cmd)
+	return cmd
+}
+
+func newBoardsCloneCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "clone <id>",
+		Short: "Clone a board, pointing its queries at another dataset",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c := newClient(cmd)
+			from, _ := cmd.Flags().GetString("from")
+			to, _ := cmd.Flags().GetString("to")
+			name, _ := cmd.Flags().GetString("name")
+
+			board, err := c.GetBoard(cmd.Context(), args[0])
+			if err != nil {
+				return err
+			}
+
+			clone := honeycomb.Board{
+				Name:         board.Name + " (" + to + ")",
+				Description:  board.Description,
+				Style:        board.Style,
+				ColumnLayout: board.ColumnLayout,
+			}
+			if name != "" {
+				clone.Name = name
+			}
+
+			for i, q := range board.Queries {
+				if from != "" && q.Dataset != from {
+					clone.Queries = append(clone.Queries, q)
+					continue
+				}
+
+				spec, err := c.GetQuery(cmd.Context(), q.Dataset, q.QueryID)
+				if err != nil {
+					return fmt.Errorf("getting query %v for panel %v: %w", q.QueryID, i+1, err)
+				}
+
+				query, err := c.CreateQuery(cmd.Context(), to, *spec)
+				if err != nil {
+					return fmt.Errorf("creating query for panel %v: %w", i+1, err)
+				}
+
+				clone.Queries = append(clone.Queries, honeycomb.BoardQuery{
+					Caption:    q.Caption,
+					QueryStyle: q.QueryStyle,
+					Dataset:    to,
+					QueryID:    query.ID,
+				})
+			}
+
+			created, err := c.CreateBoard(cmd.Context(), clone)
+			if err != nil {
+				return err
+			}
+
+			fmt.Fprintf(cmd.OutOrStdout(), "Created board %q (%v)\n", created.Name, created.ID)
+
+			return showLinks(cmd, c, func(l *honeycomb.UILinks) ([]uiLink, error) {
+				return []uiLink{{Label: "Board", URL: l.Board(created.ID)}}, nil
+			})
+		},
+	}
+	cmd.Flags().String("to", "", "Dataset slug to point the cloned queries at (required)")
+	_ = cmd.MarkFlagRequired("to")
+	cmd.Flags().String("from", "", "Only repoint queries from this dataset slug")
+	cmd.Flags().String("name", "", "Name of the new board (default is the original name with the dataset appended)")
+	addLinkFlags(cmd)
+	return cmd
+}
+
+func newBoardsDeleteCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "delete <id>",
+		Short: "Delete a board",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c := newClient(cmd)
+
+			if err := c.DeleteBoard(cmd.Context(), args[0]); err != nil {
+				return err
+			}
+
+			fmt.Fprintf(cmd.OutOrStdout(), "Deleted board %v\n", args[0])
+			return nil
+		},
+	}
+}
@@ -0,0 +1,191 @@
+package cmd_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"maragu.dev/is"
+
+	"github.com/maragudk/honeycomb-cli/cmd"
+	"github.com/maragudk/honeycomb-cli/honeycomb"
+)
+
+func TestBoardsListCommand(t *testing.T) {
+	t.Run("lists boards in a table", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			is.Equal(t, "/1/boards", r.URL.Path)
+			_ = json.NewEncoder(w).Encode([]honeycomb.Board{
+				{ID: "b1", Name: "API overview"},
+			})
+		}))
+		defer server.Close()
+
+		var buf bytes.Buffer
+		root := cmd.NewRootCommand()
+		root.SetOut(&buf)
+		root.SetArgs([]string{"boards", "list", "--api-key", "test", "--api-url", server.URL})
+
+		err := root.Execute()
+		is.NotError(t, err)
+
+		output := buf.String()
+		is.True(t, contains(output, "b1"))
+		is.True(t, contains(output, "API overview"))
+	})
+}
+
+func TestBoardsGetCommand(t *testing.T) {
+	t.Run("shows board panels with their query specs", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch r.URL.Path {
+			case "/1/boards/b1":
+				_ = json.NewEncoder(w).Encode(honeycomb.Board{
+					ID:   "b1",
+					Name: "API overview",
+					Queries: []honeycomb.BoardQuery{
+						{Caption: "Latency", Dataset: "requests", QueryID: "q1"},
+					},
+				})
+			case "/1/queries/requests/q1":
+				_ = json.NewEncoder(w).Encode(honeycomb.QuerySpec{
+					Calculations: []honeycomb.Calculation{{Op: "P99", Column: "duration_ms"}},
+				})
+			default:
+				t.Errorf("unexpected path %v", r.URL.Path)
+				http.Error(w, `{"error": "not found"}`, http.StatusNotFound)
+			}
+		}))
+		defer server.Close()
+
+		var buf bytes.Buffer
+		root := cmd.NewRootCommand()
+		root.SetOut(&buf)
+		root.SetArgs([]string{"boards", "show", "b1", "--api-key", "test", "--api-url", server.URL})
+
+		err := root.Execute()
+		is.NotError(t, err)
+
+		output := buf.String()
+		is.True(t, contains(output, "Latency"))
+		is.True(t, contains(output, "duration_ms"))
+	})
+}
+
+func TestBoardsCreateCommand(t *testing.T) {
+	t.Run("creates queries and a board from a YAML file", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "board.yaml")
+		err := os.WriteFile(path, []byte(`name: Service overview
+queries:
+  - caption: Requests
+    query:
+      calculations:
+        - op: COUNT
+  - caption: Errors
+    dataset: errors
+    query:
+      calculations:
+        - op: COUNT
+`), 0600)
+		is.NotError(t, err)
+
+		var created honeycomb.Board
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch {
+			case r.Method == http.MethodPost && r.URL.Path == "/1/queries/payments":
+				_ = json.NewEncoder(w).Encode(honeycomb.QueryResponse{ID: "q1"})
+			case r.Method == http.MethodPost && r.URL.Path == "/1/queries/errors":
+				_ = json.NewEncoder(w).Encode(honeycomb.QueryResponse{ID: "q2"})
+			case r.Method == http.MethodPost && r.URL.Path == "/1/boards":
+				_ = json.NewDecoder(r.Body).Decode(&created)
+				created.ID = "b1"
+				_ = json.NewEncoder(w).Encode(created)
+			default:
+				t.Errorf("unexpected request %v %v", r.Method, r.URL.Path)
+				http.Error(w, `{"error": "not found"}`, http.StatusNotFound)
+			}
+		}))
+		defer server.Close()
+
+		var buf bytes.Buffer
+		root := cmd.NewRootCommand()
+		root.SetOut(&buf)
+		root.SetArgs([]string{"boards", "create", "-f", path, "--dataset", "payments",
+			"--api-key", "test", "--api-url", server.URL})
+
+		err = root.Execute()
+		is.NotError(t, err)
+
+		is.True(t, contains(buf.String(), "Service overview"))
+		is.Equal(t, 2, len(created.Queries))
+		is.Equal(t, "payments", created.Queries[0].Dataset)
+		is.Equal(t, "q1", created.Queries[0].QueryID)
+		is.Equal(t, "errors", created.Queries[1].Dataset)
+		is.Equal(t, "q2", created.Queries[1].QueryID)
+	})
+
+	t.Run("rejects unknown fields in the file", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "board.yaml")
+		err := os.WriteFile(path, []byte("name: Typo\nqueires: []\n"), 0600)
+		is.NotError(t, err)
+
+		root := cmd.NewRootCommand()
+		root.SetOut(&bytes.Buffer{})
+		root.SetArgs([]string{"boards", "create", "-f", path, "--api-key", "test", "--api-url", "http://localhost:0"})
+
+		err = root.Execute()
+		is.True(t, err != nil)
+	})
+}
+
+func TestBoardsCloneCommand(t *testing.T) {
+	t.Run("clones a board to another dataset", func(t *testing.T) {
+		var created honeycomb.Board
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch {
+			case r.Method == http.MethodGet && r.URL.Path == "/1/boards/b1":
+				_ = json.NewEncoder(w).Encode(honeycomb.Board{
+					ID:   "b1",
+					Name: "Checkout",
+					Queries: []honeycomb.BoardQuery{
+						{Caption: "Latency", Dataset: "checkout", QueryID: "q1"},
+					},
+				})
+			case r.Method == http.MethodGet && r.URL.Path == "/1/queries/checkout/q1":
+				_ = json.NewEncoder(w).Encode(honeycomb.QuerySpec{
+					Calculations: []honeycomb.Calculation{{Op: "P99", Column: "duration_ms"}},
+				})
+			case r.Method == http.MethodPost && r.URL.Path == "/1/queries/payments":
+				var spec honeycomb.QuerySpec
+				_ = json.NewDecoder(r.Body).Decode(&spec)
+				is.Equal(t, "duration_ms", spec.Calculations[0].Column)
+				_ = json.NewEncoder(w).Encode(honeycomb.QueryResponse{ID: "q2"})
+			case r.Method == http.MethodPost && r.URL.Path == "/1/boards":
+				_ = json.NewDecoder(r.Body).Decode(&created)
+				created.ID = "b2"
+				_ = json.NewEncoder(w).Encode(created)
+			default:
+				t.Errorf("unexpected request %v %v", r.Method, r.URL.Path)
+				http.Error(w, `{"error": "not found"}`, http.StatusNotFound)
+			}
+		}))
+		defer server.Close()
+
+		var buf bytes.Buffer
+		root := cmd.NewRootCommand()
+		root.SetOut(&buf)
+		root.SetArgs([]string{"boards", "clone", "b1", "--to", "payments", "--api-key", "test", "--api-url", server.URL})
+
+		err := root.Execute()
+		is.NotError(t, err)
+
+		is.Equal(t, "Checkout (payments)", created.Name)
+		is.Equal(t, "payments", created.Queries[0].Dataset)
+		is.Equal(t, "q2", created.Queries[0].QueryID)
+		is.True(t, contains(buf.String(), "b2"))
+	})
+}
@@ -0,0 +1,72 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"github.com/spf13/cobra"
+
+	"github.com/maragudk/honeycomb-cli/honeycomb"
+)
+
+func newCacheCommand() *cobra.Command {
+	cacheCmd := &cobra.Command{
+		Use:   "cache",
+		Short: "Manage the query result cache",
+		Long: `Manage the on-disk cache of query results, used with --cache or HONEYCOMB_CACHE=true.
+Identical queries in the same dataset reuse cached results for --cache-ttl, instead of running again.`,
+	}
+
+	cacheCmd.AddCommand(newCacheClearCommand())
+
+	return cacheCmd
+}
+
+func newCacheClearCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "clear",
+		Short: "Remove all cached query results",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			dir, err := queryCacheDir()
+			if err != nil {
+				return err
+			}
+			if err := honeycomb.NewQueryCache(dir, 0).Clear(); err != nil {
+				return err
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "Cleared query cache in %v\n", dir)
+			return nil
+		},
+	}
+}
+
+// queryCacheDir is where query results are cached, in the user's cache directory.
+func queryCacheDir() (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", fmt.Errorf("finding cache directory: %w", err)
+	}
+	return filepath.Join(dir, "honeycomb-cli", "queries"), nil
+}
+
+// queryCache from the command's flags and the HONEYCOMB_CACHE environment variable, or nil if caching is off.
+// --no-cache always turns it off.
+func queryCache(cmd *cobra.Command) *honeycomb.QueryCache {
+	enabled, _ := cmd.Flags().GetBool("cache")
+	if !enabled {
+		enabled, _ = strconv.ParseBool(os.Getenv("HONEYCOMB_CACHE"))
+	}
+	if noCache, _ := cmd.Flags().GetBool("no-cache"); noCache || !enabled {
+		return nil
+	}
+
+	dir, err := queryCacheDir()
+	if err != nil {
+		return nil
+	}
+	ttl, _ := cmd.Flags().GetDuration("cache-ttl")
+	return honeycomb.NewQueryCache(dir, ttl)
+}
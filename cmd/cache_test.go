@@ -0,0 +1,155 @@
+package cmd_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+
+	"maragu.dev/is"
+
+	"github.com/maragudk/honeycomb-cli/cmd"
+	"github.com/maragudk/honeycomb-cli/honeycomb"
+)
+
+func runQueryCommand(t *testing.T, server *httptest.Server, flags ...string) string {
+	t.Helper()
+	var buf bytes.Buffer
+	root := cmd.NewRootCommand()
+	root.SetOut(&buf)
+	root.SetErr(&bytes.Buffer{})
+	root.SetArgs(append([]string{"query", "--dataset", "requests", "--api-key", "test", "--api-url", server.URL}, flags...))
+	is.NotError(t, root.Execute())
+	return buf.String()
+}
+
+func TestQueryCommand_Cache(t *testing.T) {
+	t.Run("reuses cached results with --cache", func(t *testing.T) {
+		dir := t.TempDir()
+		t.Setenv("XDG_CACHE_HOME", dir)
+		t.Setenv("HOME", dir)
+		t.Setenv("HONEYCOMB_CACHE", "")
+
+		var created atomic.Int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch {
+			case r.Method == http.MethodPost && r.URL.Path == "/1/queries/requests":
+				created.Add(1)
+				_ = json.NewEncoder(w).Encode(honeycomb.QueryResponse{ID: "q1"})
+			case r.Method == http.MethodPost && r.URL.Path == "/1/query_results/requests":
+				result := honeycomb.QueryResult{ID: "r1", Complete: true}
+				result.Data.Results = []map[string]any{{"COUNT": float64(42)}}
+				_ = json.NewEncoder(w).Encode(result)
+			default:
+				http.NotFound(w, r)
+			}
+		}))
+		defer server.Close()
+
+		first := runQueryCommand(t, server, "--cache")
+		second := runQueryCommand(t, server, "--cache")
+
+		is.Equal(t, int32(1), created.Load())
+		is.Equal(t, first, second)
+		is.True(t, contains(second, "42"))
+	})
+
+	t.Run("doesn't cache without --cache", func(t *testing.T) {
+		dir := t.TempDir()
+		t.Setenv("XDG_CACHE_HOME", dir)
+		t.Setenv("HOME", dir)
+		t.Setenv("HONEYCOMB_CACHE", "")
+
+		var created atomic.Int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch {
+			case r.Method == http.MethodPost && r.URL.Path == "/1/queries/requests":
+				created.Add(1)
+				_ = json.NewEncoder(w).Encode(honeycomb.QueryResponse{ID: "q1"})
+			case r.Method == http.MethodPost && r.URL.Path == "/1/query_results/requests":
+				result := honeycomb.QueryResult{ID: "r1", Complete: true}
+				result.Data.Results = []map[string]any{{"COUNT": float64(42)}}
+				_ = json.NewEncoder(w).Encode(result)
+			default:
+				http.NotFound(w, r)
+			}
+		}))
+		defer server.Close()
+
+		runQueryCommand(t, server)
+		runQueryCommand(t, server)
+
+		is.Equal(t, int32(2), created.Load())
+	})
+
+	t.Run("caches with HONEYCOMB_CACHE, unless --no-cache is given", func(t *testing.T) {
+		dir := t.TempDir()
+		t.Setenv("XDG_CACHE_HOME", dir)
+		t.Setenv("HOME", dir)
+		t.Setenv("HONEYCOMB_CACHE", "")
+
+		var created atomic.Int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch {
+			case r.Method == http.MethodPost && r.URL.Path == "/1/queries/requests":
+				created.Add(1)
+				_ = json.NewEncoder(w).Encode(honeycomb.QueryResponse{ID: "q1"})
+			case r.Method == http.MethodPost && r.URL.Path == "/1/query_results/requests":
+				result := honeycomb.QueryResult{ID: "r1", Complete: true}
+				result.Data.Results = []map[string]any{{"COUNT": float64(42)}}
+				_ = json.NewEncoder(w).Encode(result)
+			default:
+				http.NotFound(w, r)
+			}
+		}))
+		defer server.Close()
+		t.Setenv("HONEYCOMB_CACHE", "true")
+
+		runQueryCommand(t, server)
+		runQueryCommand(t, server)
+		is.Equal(t, int32(1), created.Load())
+
+		runQueryCommand(t, server, "--no-cache")
+		is.Equal(t, int32(2), created.Load())
+	})
+}
+
+func TestCacheClearCommand(t *testing.T) {
+	t.Run("clears cached results", func(t *testing.T) {
+		dir := t.TempDir()
+		t.Setenv("XDG_CACHE_HOME", dir)
+		t.Setenv("HOME", dir)
+		t.Setenv("HONEYCOMB_CACHE", "")
+
+		var created atomic.Int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch {
+			case r.Method == http.MethodPost && r.URL.Path == "/1/queries/requests":
+				created.Add(1)
+				_ = json.NewEncoder(w).Encode(honeycomb.QueryResponse{ID: "q1"})
+			case r.Method == http.MethodPost && r.URL.Path == "/1/query_results/requests":
+				result := honeycomb.QueryResult{ID: "r1", Complete: true}
+				result.Data.Results = []map[string]any{{"COUNT": float64(42)}}
+				_ = json.NewEncoder(w).Encode(result)
+			default:
+				http.NotFound(w, r)
+			}
+		}))
+		defer server.Close()
+
+		runQueryCommand(t, server, "--cache")
+
+		var buf bytes.Buffer
+		root := cmd.NewRootCommand()
+		root.SetOut(&buf)
+		root.SetArgs([]string{"cache", "clear"})
+		is.NotError(t, root.Execute())
+		is.Equal(t, "Cleared query cache in "+filepath.Join(dir, "honeycomb-cli", "queries")+"\n", buf.String())
+
+		runQueryCommand(t, server, "--cache")
+		is.Equal(t, int32(2), created.Load())
+	})
+}
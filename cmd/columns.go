@@ -3,9 +3,13 @@ package cmd
 import (
 	"encoding/json"
 	"fmt"
+	"os"
+	"sort"
 	"text/tabwriter"
 
 	"github.com/spf13/cobra"
+
+	"github.com/maragudk/honeycomb-cli/honeycomb"
 )
 
 func newColumnsCommand() *cobra.Command {
@@ -18,6 +22,13 @@ func newColumnsCommand() *cobra.Command {
 	_ = columnsCmd.MarkPersistentFlagRequired("dataset")
 
 	columnsCmd.AddCommand(newColumnsListCommand())
+	columnsCmd.AddCommand(newColumnsGetCommand())
+	columnsCmd.AddCommand(newColumnsUpdateCommand())
+	columnsCmd.AddCommand(newColumnsHideCommand(true))
+	columnsCmd.AddCommand(newColumnsHideCommand(false))
+	columnsCmd.AddCommand(newColumnsDeleteCommand())
+	columnsCmd.AddCommand(newColumnsDescribeCommand())
+	columnsCmd.AddCommand(newColumnsAuditCommand())
 
 	return columnsCmd
 }
@@ -55,3 +66,260 @@ func newColumnsListCommand() *cobra.Command {
 	cmd.Flags().Bool("json", false, "Output as JSON")
 	return cmd
 }
+
+func newColumnsGetCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "get <key-name>",
+		Short: "Get a column by key name",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c := newClient(cmd)
+			dataset, _ := cmd.Flags().GetString("dataset")
+
+			col, err := c.GetColumnByKeyName(cmd.Context(), dataset, args[0])
+			if err != nil {
+				return err
+			}
+
+			asJSON, _ := cmd.Flags().GetBool("json")
+			if asJSON {
+				return json.NewEncoder(cmd.OutOrStdout()).Encode(col)
+			}
+
+			hidden := "no"
+			if col.Hidden {
+				hidden = "yes"
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "ID:           %v\n", col.ID)
+			fmt.Fprintf(cmd.OutOrStdout(), "Key name:     %v\n", col.KeyName)
+			fmt.Fprintf(cmd.OutOrStdout(), "Type:         %v\n", col.Type)
+			fmt.Fprintf(cmd.OutOrStdout(), "Description:  %v\n", col.Description)
+			fmt.Fprintf(cmd.OutOrStdout(), "Hidden:       %v\n", hidden)
+			fmt.Fprintf(cmd.OutOrStdout(), "Last written: %v\n", col.LastWritten)
+			return nil
+		},
+	}
+	cmd.Flags().Bool("json", false, "Output as JSON")
+	return cmd
+}
+
+func newColumnsUpdateCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "update <key-name>",
+		Short: "Update a column's description, type, or visibility",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c := newClient(cmd)
+			dataset, _ := cmd.Flags().GetString("dataset")
+
+			var update honeycomb.UpdateColumnRequest
+			if cmd.Flags().Changed("description") {
+				description, _ := cmd.Flags().GetString("description")
+				update.Description = &description
+			}
+			if cmd.Flags().Changed("type") {
+				columnType, _ := cmd.Flags().GetString("type")
+				if err := validateColumnType(columnType); err != nil {
+					return err
+				}
+				update.Type = &columnType
+			}
+			if cmd.Flags().Changed("hidden") {
+				hidden, _ := cmd.Flags().GetBool("hidden")
+				update.Hidden = &hidden
+			}
+			if update.Description == nil && update.Type == nil && update.Hidden == nil {
+				return fmt.Errorf("nothing to update (use --description, --type, or --hidden)")
+			}
+
+			col, err := c.GetColumnByKeyName(cmd.Context(), dataset, args[0])
+			if err != nil {
+				return err
+			}
+
+			if _, err := c.UpdateColumn(cmd.Context(), dataset, col.ID, update); err != nil {
+				return err
+			}
+
+			fmt.Fprintf(cmd.OutOrStdout(), "Updated column %q\n", col.KeyName)
+			return nil
+		},
+	}
+	cmd.Flags().String("description", "", "Column description")
+	cmd.Flags().String("type", "", "Column type (string, float, integer, boolean)")
+	cmd.Flags().Bool("hidden", false, "Hide the column from autocomplete and raw data field lists")
+	return cmd
+}
+
+func newColumnsHideCommand(hide bool) *cobra.Command {
+	use, short, state := "hide", "Hide a column", "hidden"
+	if !hide {
+		use, short, state = "unhide", "Unhide a column", "visible"
+	}
+
+	return &cobra.Command{
+		Use:   use + " <key-name>",
+		Short: short,
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c := newClient(cmd)
+			dataset, _ := cmd.Flags().GetString("dataset")
+
+			col, err := c.GetColumnByKeyName(cmd.Context(), dataset, args[0])
+			if err != nil {
+				return err
+			}
+
+			if _, err := c.UpdateColumn(cmd.Context(), dataset, col.ID, honeycomb.UpdateColumnRequest{Hidden: &hide}); err != nil {
+				return err
+			}
+
+			fmt.Fprintf(cmd.OutOrStdout(), "Column %q is now %v\n", col.KeyName, state)
+			return nil
+		},
+	}
+}
+
+func newColumnsDeleteCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "delete <key-name>",
+		Short: "Delete a column",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c := newClient(cmd)
+			dataset, _ := cmd.Flags().GetString("dataset")
+
+			col, err := c.GetColumnByKeyName(cmd.Context(), dataset, args[0])
+			if err != nil {
+				return err
+			}
+
+			if err := c.DeleteColumn(cmd.Context(), dataset, col.ID); err != nil {
+				return err
+			}
+
+			fmt.Fprintf(cmd.OutOrStdout(), "Deleted column %q\n", col.KeyName)
+			return nil
+		},
+	}
+}
+
+// dataDictionary is the YAML format for managing column metadata in bulk.
+type dataDictionary struct {
+	Columns map[string]dataDictionaryEntry `json:"columns"`
+}
+
+type dataDictionaryEntry struct {
+	Description *string `json:"description,omitempty"`
+	Type        *string `json:"type,omitempty"`
+	Hidden      *bool   `json:"hidden,omitempty"`
+}
+
+func newColumnsDescribeCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "describe",
+		Short: "Apply column metadata from a YAML data dictionary",
+		Long: `Apply column descriptions, types, and visibility from a YAML data dictionary.
+Only columns whose metadata differs from the dictionary are updated.
+
+Example file:
+  columns:
+    duration_ms:
+      description: Time taken to serve the request, in milliseconds
+      type: float
+    user_id:
+      description: ID of the authenticated user
+    debug.payload:
+      hidden: true`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c := newClient(cmd)
+			dataset, _ := cmd.Flags().GetString("dataset")
+			path, _ := cmd.Flags().GetString("file")
+			dryRun, _ := cmd.Flags().GetBool("dry-run")
+
+			data, err := os.ReadFile(path)
+			if err != nil {
+				return err
+			}
+
+			var dict dataDictionary
+			if err := decodeYAML(data, &dict); err != nil {
+				return fmt.Errorf("parsing %v: %w", path, err)
+			}
+
+			columns, err := c.ListColumns(cmd.Context(), dataset)
+			if err != nil {
+				return err
+			}
+			byName := map[string]honeycomb.Column{}
+			for _, col := range columns {
+				byName[col.KeyName] = col
+			}
+
+			var names []string
+			for name := range dict.Columns {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+
+			var updated, unchanged int
+			for _, name := range names {
+				entry := dict.Columns[name]
+				col, ok := byName[name]
+				if !ok {
+					fmt.Fprintf(cmd.ErrOrStderr(), "Warning: column %q not found in dataset %v\n", name, dataset)
+					continue
+				}
+
+				var update honeycomb.UpdateColumnRequest
+				if entry.Description != nil && *entry.Description != col.Description {
+					update.Description = entry.Description
+				}
+				if entry.Type != nil && *entry.Type != col.Type {
+					if err := validateColumnType(*entry.Type); err != nil {
+						return fmt.Errorf("column %q: %w", name, err)
+					}
+					update.Type = entry.Type
+				}
+				if entry.Hidden != nil && *entry.Hidden != col.Hidden {
+					update.Hidden = entry.Hidden
+				}
+				if update.Description == nil && update.Type == nil && update.Hidden == nil {
+					unchanged++
+					continue
+				}
+
+				updated++
+				if dryRun {
+					fmt.Fprintf(cmd.OutOrStdout(), "Would update column %q\n", name)
+					continue
+				}
+				if _, err := c.UpdateColumn(cmd.Context(), dataset, col.ID, update); err != nil {
+					return fmt.Errorf("updating column %q: %w", name, err)
+				}
+				fmt.Fprintf(cmd.OutOrStdout(), "Updated column %q\n", name)
+			}
+
+			if dryRun {
+				fmt.Fprintf(cmd.OutOrStdout(), "%v would be updated, %v unchanged\n", updated, unchanged)
+				return nil
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "%v updated, %v unchanged\n", updated, unchanged)
+			return nil
+		},
+	}
+	cmd.Flags().StringP("file", "f", "", "Path to the data dictionary YAML file")
+	_ = cmd.MarkFlagRequired("file")
+	cmd.Flags().Bool("dry-run", false, "Show what would change without updating anything")
+	return cmd
+}
+
+// validateColumnType checks that the type is one Honeycomb supports for columns.
+func validateColumnType(columnType string) error {
+	switch columnType {
+	case "string", "float", "integer", "boolean":
+		return nil
+	default:
+		return fmt.Errorf("unknown column type %q (expected string, float, integer, or boolean)", columnType)
+	}
+}
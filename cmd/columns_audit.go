@@ -0,0 +1,206 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"math"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/maragudk/honeycomb-cli/honeycomb"
+)
+
+// columnsAudit is the result of auditing the columns of one or more datasets.
+type columnsAudit struct {
+	Datasets      []datasetColumnsAudit `json:"datasets"`
+	TypeConflicts []columnTypeConflict  `json:"type_conflicts"`
+}
+
+// datasetColumnsAudit lists the column hygiene problems found in a single dataset.
+type datasetColumnsAudit struct {
+	Dataset        string     `json:"dataset"`
+	Columns        int        `json:"columns"`
+	Score          int        `json:"score"`
+	Stale          []string   `json:"stale"`
+	NearDuplicates [][]string `json:"near_duplicates"`
+	Undocumented   []string   `json:"undocumented"`
+}
+
+// columnTypeConflict is a column name that has different types in different datasets.
+type columnTypeConflict struct {
+	Column string            `json:"column"`
+	Types  map[string]string `json:"types"`
+}
+
+func newColumnsAuditCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "audit",
+		Short: "Report column hygiene problems",
+		Long: `Report column hygiene problems in a dataset, or in all datasets with --dataset __all__:
+
+  - stale columns that haven't been written recently
+  - near-duplicate names, such as user_id, userId, and user.id
+  - undocumented columns that are still being written to
+  - columns with different types in different datasets
+
+Each dataset gets a score from 0 to 100, the share of its columns without problems.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c := newClient(cmd)
+			dataset, _ := cmd.Flags().GetString("dataset")
+			staleDays, _ := cmd.Flags().GetInt("stale-days")
+			activeDays, _ := cmd.Flags().GetInt("active-days")
+
+			slugs := []string{dataset}
+			if dataset == "__all__" {
+				datasets, err := c.ListDatasets(cmd.Context())
+				if err != nil {
+					return err
+				}
+				slugs = nil
+				for _, d := range datasets {
+					slugs = append(slugs, d.Slug)
+				}
+			}
+
+			columnsByDataset := map[string][]honeycomb.Column{}
+			for _, slug := range slugs {
+				columns, err := c.ListColumns(cmd.Context(), slug)
+				if err != nil {
+					return fmt.Errorf("listing columns for %v: %w", slug, err)
+				}
+				columnsByDataset[slug] = columns
+			}
+
+			audit := auditColumns(columnsByDataset, time.Now(), time.Duration(staleDays)*24*time.Hour, time.Duration(activeDays)*24*time.Hour)
+
+			asJSON, _ := cmd.Flags().GetBool("json")
+			if asJSON {
+				return json.NewEncoder(cmd.OutOrStdout()).Encode(audit)
+			}
+
+			out := cmd.OutOrStdout()
+			for i, d := range audit.Datasets {
+				if i > 0 {
+					fmt.Fprintln(out)
+				}
+				fmt.Fprintf(out, "%v: score %v/100 (%v columns)\n", d.Dataset, d.Score, d.Columns)
+				if len(d.Stale) > 0 {
+					fmt.Fprintf(out, "  Stale (not written in %v days): %v\n", staleDays, strings.Join(d.Stale, ", "))
+				}
+				for _, group := range d.NearDuplicates {
+					fmt.Fprintf(out, "  Near-duplicate names: %v\n", strings.Join(group, ", "))
+				}
+				if len(d.Undocumented) > 0 {
+					fmt.Fprintf(out, "  Undocumented (written in the last %v days): %v\n", activeDays, strings.Join(d.Undocumented, ", "))
+				}
+			}
+
+			if len(audit.TypeConflicts) > 0 {
+				fmt.Fprintln(out, "\nType conflicts across datasets:")
+				for _, conflict := range audit.TypeConflicts {
+					var types []string
+					for _, slug := range slices.Sorted(maps.Keys(conflict.Types)) {
+						types = append(types, slug+"="+conflict.Types[slug])
+					}
+					fmt.Fprintf(out, "  %v: %v\n", conflict.Column, strings.Join(types, ", "))
+				}
+			}
+			return nil
+		},
+	}
+	cmd.Flags().Int("stale-days", 30, "Report columns not written in this many days as stale")
+	cmd.Flags().Int("active-days", 1, "Report undocumented columns written in this many days")
+	cmd.Flags().Bool("json", false, "Output as JSON")
+	return cmd
+}
+
+// auditColumns finds hygiene problems in the given columns, keyed by dataset slug.
+func auditColumns(columnsByDataset map[string][]honeycomb.Column, now time.Time, staleAfter, activeWithin time.Duration) columnsAudit {
+	audit := columnsAudit{
+		Datasets:      []datasetColumnsAudit{},
+		TypeConflicts: []columnTypeConflict{},
+	}
+
+	typesByColumn := map[string]map[string]string{}
+
+	for _, slug := range slices.Sorted(maps.Keys(columnsByDataset)) {
+		columns := columnsByDataset[slug]
+		d := datasetColumnsAudit{
+			Dataset:        slug,
+			Columns:        len(columns),
+			Stale:          []string{},
+			NearDuplicates: [][]string{},
+			Undocumented:   []string{},
+		}
+		problems := map[string]bool{}
+		groups := map[string][]string{}
+
+		for _, col := range columns {
+			if col.Type != "" {
+				if typesByColumn[col.KeyName] == nil {
+					typesByColumn[col.KeyName] = map[string]string{}
+				}
+				typesByColumn[col.KeyName][slug] = col.Type
+			}
+
+			key := normalizeColumnName(col.KeyName)
+			groups[key] = append(groups[key], col.KeyName)
+
+			lastWritten, err := time.Parse(time.RFC3339, col.LastWritten)
+			if err != nil {
+				continue
+			}
+			age := now.Sub(lastWritten)
+			if age > staleAfter {
+				d.Stale = append(d.Stale, col.KeyName)
+				problems[col.KeyName] = true
+			}
+			if age <= activeWithin && col.Description == "" && !col.Hidden {
+				d.Undocumented = append(d.Undocumented, col.KeyName)
+				problems[col.KeyName] = true
+			}
+		}
+
+		for _, key := range slices.Sorted(maps.Keys(groups)) {
+			if names := groups[key]; len(names) > 1 {
+				sort.Strings(names)
+				d.NearDuplicates = append(d.NearDuplicates, names)
+				for _, name := range names {
+					problems[name] = true
+				}
+			}
+		}
+
+		sort.Strings(d.Stale)
+		sort.Strings(d.Undocumented)
+
+		d.Score = 100
+		if len(columns) > 0 {
+			d.Score = int(math.Round(100 * float64(len(columns)-len(problems)) / float64(len(columns))))
+		}
+		audit.Datasets = append(audit.Datasets, d)
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(typesByColumn)) {
+		types := typesByColumn[name]
+		seen := map[string]bool{}
+		for _, t := range types {
+			seen[t] = true
+		}
+		if len(seen) > 1 {
+			audit.TypeConflicts = append(audit.TypeConflicts, columnTypeConflict{Column: name, Types: types})
+		}
+	}
+
+	return audit
+}
+
+// normalizeColumnName so that names differing only in case and separators compare equal.
+func normalizeColumnName(name string) string {
+	return strings.NewReplacer("_", "", ".", "", "-", "", " ", "").Replace(strings.ToLower(name))
+}
@@ -0,0 +1,86 @@
+package cmd_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"maragu.dev/is"
+
+	"github.com/maragudk/honeycomb-cli/cmd"
+	"github.com/maragudk/honeycomb-cli/honeycomb"
+)
+
+func TestColumnsAuditCommand(t *testing.T) {
+	recent := time.Now().Add(-time.Hour).Format(time.RFC3339)
+	old := time.Now().Add(-60 * 24 * time.Hour).Format(time.RFC3339)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/1/datasets":
+			_ = json.NewEncoder(w).Encode([]honeycomb.Dataset{{Slug: "requests"}, {Slug: "jobs"}})
+		case "/1/columns/requests":
+			_ = json.NewEncoder(w).Encode([]honeycomb.Column{
+				{KeyName: "user_id", Type: "string", Description: "User", LastWritten: recent},
+				{KeyName: "userId", Type: "string", Description: "User", LastWritten: recent},
+				{KeyName: "legacy_flag", Type: "boolean", Description: "Old", LastWritten: old},
+				{KeyName: "region", Type: "string", LastWritten: recent},
+			})
+		case "/1/columns/jobs":
+			_ = json.NewEncoder(w).Encode([]honeycomb.Column{
+				{KeyName: "user_id", Type: "integer", Description: "User", LastWritten: recent},
+			})
+		default:
+			t.Errorf("unexpected path %v", r.URL.Path)
+			http.Error(w, `{"error": "not found"}`, http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	t.Run("reports stale, near-duplicate, and undocumented columns with a score", func(t *testing.T) {
+		var buf bytes.Buffer
+		root := cmd.NewRootCommand()
+		root.SetOut(&buf)
+		root.SetArgs([]string{"columns", "audit", "--dataset", "requests", "--api-key", "test", "--api-url", server.URL})
+
+		err := root.Execute()
+		is.NotError(t, err)
+
+		output := buf.String()
+		is.True(t, contains(output, "requests: score 0/100 (4 columns)"))
+		is.True(t, contains(output, "Stale (not written in 30 days): legacy_flag"))
+		is.True(t, contains(output, "Near-duplicate names: userId, user_id"))
+		is.True(t, contains(output, "region"))
+	})
+
+	t.Run("reports type conflicts across all datasets as JSON", func(t *testing.T) {
+		var buf bytes.Buffer
+		root := cmd.NewRootCommand()
+		root.SetOut(&buf)
+		root.SetArgs([]string{"columns", "audit", "--dataset", "__all__", "--json", "--api-key", "test", "--api-url", server.URL})
+
+		err := root.Execute()
+		is.NotError(t, err)
+
+		var audit struct {
+			Datasets []struct {
+				Dataset string
+				Score   int
+			}
+			TypeConflicts []struct {
+				Column string
+				Types  map[string]string
+			} `json:"type_conflicts"`
+		}
+		is.NotError(t, json.Unmarshal(buf.Bytes(), &audit))
+		is.Equal(t, 2, len(audit.Datasets))
+		is.Equal(t, "jobs", audit.Datasets[0].Dataset)
+		is.Equal(t, 100, audit.Datasets[0].Score)
+		is.Equal(t, 1, len(audit.TypeConflicts))
+		is.Equal(t, "user_id", audit.TypeConflicts[0].Column)
+		is.Equal(t, "integer", audit.TypeConflicts[0].Types["jobs"])
+	})
+}
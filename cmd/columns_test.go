@@ -5,6 +5,8 @@ import (
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"maragu.dev/is"
@@ -60,3 +62,147 @@ func TestColumnsListCommand(t *testing.T) {
 		is.Equal(t, "duration_ms", columns[0].KeyName)
 	})
 }
+
+func TestColumnsUpdateCommand(t *testing.T) {
+	t.Run("looks up the column by key name and updates it", func(t *testing.T) {
+		var update map[string]any
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch {
+			case r.Method == http.MethodGet && r.URL.Path == "/1/columns/requests":
+				is.Equal(t, "duration_ms", r.URL.Query().Get("key_name"))
+				_ = json.NewEncoder(w).Encode(honeycomb.Column{ID: "1", KeyName: "duration_ms"})
+			case r.Method == http.MethodPut && r.URL.Path == "/1/columns/requests/1":
+				_ = json.NewDecoder(r.Body).Decode(&update)
+				_ = json.NewEncoder(w).Encode(honeycomb.Column{ID: "1", KeyName: "duration_ms"})
+			default:
+				t.Errorf("unexpected request %v %v", r.Method, r.URL.Path)
+				http.Error(w, `{"error": "not found"}`, http.StatusNotFound)
+			}
+		}))
+		defer server.Close()
+
+		var buf bytes.Buffer
+		root := cmd.NewRootCommand()
+		root.SetOut(&buf)
+		root.SetArgs([]string{"columns", "update", "duration_ms", "--dataset", "requests", "--description", "Request time",
+			"--api-key", "test", "--api-url", server.URL})
+
+		err := root.Execute()
+		is.NotError(t, err)
+
+		is.Equal(t, "Request time", update["description"].(string))
+		_, hasHidden := update["hidden"]
+		is.True(t, !hasHidden)
+	})
+
+	t.Run("rejects an unknown type", func(t *testing.T) {
+		root := cmd.NewRootCommand()
+		root.SetOut(&bytes.Buffer{})
+		root.SetArgs([]string{"columns", "update", "duration_ms", "--dataset", "requests", "--type", "double",
+			"--api-key", "test", "--api-url", "http://localhost:0"})
+
+		err := root.Execute()
+		is.True(t, err != nil)
+	})
+}
+
+func TestColumnsHideCommand(t *testing.T) {
+	t.Run("unhides a column", func(t *testing.T) {
+		var update map[string]any
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch r.Method {
+			case http.MethodGet:
+				_ = json.NewEncoder(w).Encode(honeycomb.Column{ID: "1", KeyName: "debug", Hidden: true})
+			case http.MethodPut:
+				_ = json.NewDecoder(r.Body).Decode(&update)
+				_ = json.NewEncoder(w).Encode(honeycomb.Column{ID: "1", KeyName: "debug"})
+			}
+		}))
+		defer server.Close()
+
+		var buf bytes.Buffer
+		root := cmd.NewRootCommand()
+		root.SetOut(&buf)
+		root.SetArgs([]string{"columns", "unhide", "debug", "--dataset", "requests", "--api-key", "test", "--api-url", server.URL})
+
+		err := root.Execute()
+		is.NotError(t, err)
+
+		is.Equal(t, false, update["hidden"].(bool))
+		is.True(t, contains(buf.String(), "visible"))
+	})
+}
+
+func TestColumnsDescribeCommand(t *testing.T) {
+	t.Run("updates only columns that differ from the dictionary", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "dictionary.yaml")
+		err := os.WriteFile(path, []byte(`columns:
+  duration_ms:
+    description: Request time
+  status_code:
+    description: HTTP status code
+  missing:
+    description: Not in the dataset
+`), 0600)
+		is.NotError(t, err)
+
+		var updatedPaths []string
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch r.Method {
+			case http.MethodGet:
+				_ = json.NewEncoder(w).Encode([]honeycomb.Column{
+					{ID: "1", KeyName: "duration_ms"},
+					{ID: "2", KeyName: "status_code", Description: "HTTP status code"},
+				})
+			case http.MethodPut:
+				updatedPaths = append(updatedPaths, r.URL.Path)
+				_ = json.NewEncoder(w).Encode(honeycomb.Column{})
+			}
+		}))
+		defer server.Close()
+
+		var buf bytes.Buffer
+		root := cmd.NewRootCommand()
+		root.SetOut(&buf)
+		root.SetErr(&bytes.Buffer{})
+		root.SetArgs([]string{"columns", "describe", "-f", path, "--dataset", "requests", "--api-key", "test", "--api-url", server.URL})
+
+		err = root.Execute()
+		is.NotError(t, err)
+
+		is.EqualSlice(t, []string{"/1/columns/requests/1"}, updatedPaths)
+		is.True(t, contains(buf.String(), "1 updated, 1 unchanged"))
+	})
+
+	t.Run("only shows what would be updated with --dry-run", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "dictionary.yaml")
+		err := os.WriteFile(path, []byte(`columns:
+  duration_ms:
+    description: Request time
+  status_code:
+    description: HTTP status code
+`), 0600)
+		is.NotError(t, err)
+
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			is.Equal(t, http.MethodGet, r.Method)
+			_ = json.NewEncoder(w).Encode([]honeycomb.Column{
+				{ID: "1", KeyName: "duration_ms"},
+				{ID: "2", KeyName: "status_code", Description: "HTTP status code"},
+			})
+		}))
+		defer server.Close()
+
+		var buf bytes.Buffer
+		root := cmd.NewRootCommand()
+		root.SetOut(&buf)
+		root.SetErr(&bytes.Buffer{})
+		root.SetArgs([]string{"columns", "describe", "-f", path, "--dataset", "requests", "--dry-run", "--api-key", "test", "--api-url", server.URL})
+
+		err = root.Execute()
+		is.NotError(t, err)
+
+		is.True(t, contains(buf.String(), `Would update column "duration_ms"`))
+		is.True(t, contains(buf.String(), "1 would be updated, 1 unchanged"))
+	})
+}
@@ -0,0 +1,133 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"github.com/maragudk/honeycomb-cli/honeycomb"
+)
+
+// datasetDefinitionFields are the dataset definitions that can be set with flags, in display order.
+var datasetDefinitionFields = []struct {
+	key   string
+	flag  string
+	field func(*honeycomb.DatasetDefinitions) **honeycomb.DatasetDefinition
+}{
+	{"trace_id", "trace-id", func(d *honeycomb.DatasetDefinitions) **honeycomb.DatasetDefinition { return &d.TraceID }},
+	{"span_id", "span-id", func(d *honeycomb.DatasetDefinitions) **honeycomb.DatasetDefinition { return &d.SpanID }},
+	{"parent_id", "parent-id", func(d *honeycomb.DatasetDefinitions) **honeycomb.DatasetDefinition { return &d.ParentID }},
+	{"name", "span-name", func(d *honeycomb.DatasetDefinitions) **honeycomb.DatasetDefinition { return &d.Name }},
+	{"service_name", "service-name", func(d *honeycomb.DatasetDefinitions) **honeycomb.DatasetDefinition { return &d.ServiceName }},
+	{"duration_ms", "duration-ms", func(d *honeycomb.DatasetDefinitions) **honeycomb.DatasetDefinition { return &d.DurationMS }},
+	{"span_kind", "span-kind", func(d *honeycomb.DatasetDefinitions) **honeycomb.DatasetDefinition { return &d.SpanKind }},
+	{"annotation_type", "annotation-type", func(d *honeycomb.DatasetDefinitions) **honeycomb.DatasetDefinition { return &d.AnnotationType }},
+	{"link_trace_id", "link-trace-id", func(d *honeycomb.DatasetDefinitions) **honeycomb.DatasetDefinition { return &d.LinkTraceID }},
+	{"link_span_id", "link-span-id", func(d *honeycomb.DatasetDefinitions) **honeycomb.DatasetDefinition { return &d.LinkSpanID }},
+	{"error", "error", func(d *honeycomb.DatasetDefinitions) **honeycomb.DatasetDefinition { return &d.Error }},
+	{"status", "status", func(d *honeycomb.DatasetDefinitions) **honeycomb.DatasetDefinition { return &d.Status }},
+	{"route", "route", func(d *honeycomb.DatasetDefinitions) **honeycomb.DatasetDefinition { return &d.Route }},
+	{"user", "user", func(d *honeycomb.DatasetDefinitions) **honeycomb.DatasetDefinition { return &d.User }},
+	{"body", "body", func(d *honeycomb.DatasetDefinitions) **honeycomb.DatasetDefinition { return &d.Body }},
+	{"log_severity", "log-severity", func(d *honeycomb.DatasetDefinitions) **honeycomb.DatasetDefinition { return &d.LogSeverity }},
+}
+
+// addDatasetDefinitionFlags adds a flag per dataset definition to the command.
+func addDatasetDefinitionFlags(cmd *cobra.Command) {
+	for _, f := range datasetDefinitionFields {
+		cmd.Flags().String(f.flag, "", fmt.Sprintf("Column to use as %v (empty string to unset)", f.key))
+	}
+}
+
+// datasetDefinitionsFromFlags returns the definitions given with flags, and whether any were given.
+func datasetDefinitionsFromFlags(cmd *cobra.Command) (honeycomb.DatasetDefinitions, bool) {
+	var definitions honeycomb.DatasetDefinitions
+	var changed bool
+	for _, f := range datasetDefinitionFields {
+		if !cmd.Flags().Changed(f.flag) {
+			continue
+		}
+		column, _ := cmd.Flags().GetString(f.flag)
+		*f.field(&definitions) = &honeycomb.DatasetDefinition{Name: column}
+		changed = true
+	}
+	return definitions, changed
+}
+
+func newDatasetDefinitionsCommand() *cobra.Command {
+	definitionsCmd := &cobra.Command{
+		Use:   "definitions",
+		Short: "Manage which columns Honeycomb uses for trace IDs, durations, service names, etc.",
+	}
+
+	definitionsCmd.AddCommand(newDatasetDefinitionsGetCommand())
+	definitionsCmd.AddCommand(newDatasetDefinitionsSetCommand())
+
+	return definitionsCmd
+}
+
+func newDatasetDefinitionsGetCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "get <slug>",
+		Short: "Show the dataset definitions",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c := newClient(cmd)
+
+			definitions, err := c.GetDatasetDefinitions(cmd.Context(), args[0])
+			if err != nil {
+				return err
+			}
+
+			asJSON, _ := cmd.Flags().GetBool("json")
+			if asJSON {
+				return json.NewEncoder(cmd.OutOrStdout()).Encode(definitions)
+			}
+
+			w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 2, ' ', 0)
+			fmt.Fprintln(w, "FIELD\tCOLUMN\tTYPE")
+			for _, f := range datasetDefinitionFields {
+				d := *f.field(definitions)
+				if d == nil || d.Name == "" {
+					continue
+				}
+				fmt.Fprintf(w, "%v\t%v\t%v\n", f.key, d.Name, d.ColumnType)
+			}
+			return w.Flush()
+		},
+	}
+	cmd.Flags().Bool("json", false, "Output as JSON")
+	return cmd
+}
+
+func newDatasetDefinitionsSetCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "set <slug>",
+		Short: "Set dataset definitions",
+		Long: `Set dataset definitions. Only the definitions given as flags are changed.
+
+Example:
+  honeycomb-cli datasets definitions set requests --trace-id trace.trace_id \
+    --parent-id trace.parent_id --span-id trace.span_id --duration-ms duration_ms`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c := newClient(cmd)
+
+			definitions, changed := datasetDefinitionsFromFlags(cmd)
+			if !changed {
+				return fmt.Errorf("nothing to set (use flags such as --trace-id or --duration-ms)")
+			}
+
+			if _, err := c.UpdateDatasetDefinitions(cmd.Context(), args[0], definitions); err != nil {
+				return err
+			}
+
+			fmt.Fprintf(cmd.OutOrStdout(), "Updated definitions for dataset %q\n", args[0])
+			return nil
+		},
+	}
+	addDatasetDefinitionFlags(cmd)
+	return cmd
+}
@@ -0,0 +1,60 @@
+package cmd_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"maragu.dev/is"
+
+	"github.com/maragudk/honeycomb-cli/cmd"
+)
+
+func TestDatasetDefinitionsGetCommand(t *testing.T) {
+	t.Run("shows defined fields in a table", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			is.Equal(t, "/1/dataset_definitions/requests", r.URL.Path)
+			_, _ = w.Write([]byte(`{"trace_id": {"name": "trace.trace_id", "column_type": "column"}, "route": {"name": ""}}`))
+		}))
+		defer server.Close()
+
+		var buf bytes.Buffer
+		root := cmd.NewRootCommand()
+		root.SetOut(&buf)
+		root.SetArgs([]string{"datasets", "definitions", "get", "requests", "--api-key", "test", "--api-url", server.URL})
+
+		err := root.Execute()
+		is.NotError(t, err)
+
+		output := buf.String()
+		is.True(t, contains(output, "trace.trace_id"))
+		is.True(t, !contains(output, "route"))
+	})
+}
+
+func TestDatasetDefinitionsSetCommand(t *testing.T) {
+	t.Run("sets only the given definitions", func(t *testing.T) {
+		var body map[string]map[string]string
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			is.Equal(t, http.MethodPatch, r.Method)
+			_ = json.NewDecoder(r.Body).Decode(&body)
+			_, _ = w.Write([]byte(`{}`))
+		}))
+		defer server.Close()
+
+		var buf bytes.Buffer
+		root := cmd.NewRootCommand()
+		root.SetOut(&buf)
+		root.SetArgs([]string{"datasets", "definitions", "set", "requests", "--span-name", "name", "--service-name", "service.name",
+			"--api-key", "test", "--api-url", server.URL})
+
+		err := root.Execute()
+		is.NotError(t, err)
+
+		is.Equal(t, 2, len(body))
+		is.Equal(t, "name", body["name"]["name"])
+		is.Equal(t, "service.name", body["service_name"]["name"])
+	})
+}
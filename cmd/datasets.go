@@ -1,9 +1,19 @@
 package cmd
 
 import (
+	"archive/tar"
+	"bufio"
+	"compress/gzip"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
 	"text/tabwriter"
+	"time"
 
 	"github.com/spf13/cobra"
 
@@ -19,7 +29,9 @@ func newDatasetsCommand() *cobra.Command {
 	datasetsCmd.AddCommand(newDatasetsListCommand())
 	datasetsCmd.AddCommand(newDatasetsGetCommand())
 	datasetsCmd.AddCommand(newDatasetsCreateCommand())
+	datasetsCmd.AddCommand(newDatasetsUpdateCommand())
 	datasetsCmd.AddCommand(newDatasetsDeleteCommand())
+	datasetsCmd.AddCommand(newDatasetDefinitionsCommand())
 
 	return datasetsCmd
 }
@@ -71,10 +83,16 @@ func newDatasetsGetCommand() *cobra.Command {
 				return json.NewEncoder(cmd.OutOrStdout()).Encode(dataset)
 			}
 
-			fmt.Fprintf(cmd.OutOrStdout(), "Name:         %v\n", dataset.Name)
-			fmt.Fprintf(cmd.OutOrStdout(), "Slug:         %v\n", dataset.Slug)
-			fmt.Fprintf(cmd.OutOrStdout(), "Description:  %v\n", dataset.Description)
-			fmt.Fprintf(cmd.OutOrStdout(), "Last written: %v\n", dataset.LastWrittenAt)
+			deleteProtected := "no"
+			if dataset.Settings.DeleteProtected {
+				deleteProtected = "yes"
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "Name:              %v\n", dataset.Name)
+			fmt.Fprintf(cmd.OutOrStdout(), "Slug:              %v\n", dataset.Slug)
+			fmt.Fprintf(cmd.OutOrStdout(), "Description:       %v\n", dataset.Description)
+			fmt.Fprintf(cmd.OutOrStdout(), "Last written:      %v\n", dataset.LastWrittenAt)
+			fmt.Fprintf(cmd.OutOrStdout(), "Expand JSON depth: %v\n", dataset.ExpandJSONDepth)
+			fmt.Fprintf(cmd.OutOrStdout(), "Delete protected:  %v\n", deleteProtected)
 			return nil
 		},
 	}
@@ -86,44 +104,273 @@ func newDatasetsCreateCommand() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "create",
 		Short: "Create a dataset",
+		Long: `Create a dataset, optionally with its settings and dataset definitions,
+so it's ready for tracing right away.
+
+Example:
+  honeycomb-cli datasets create --name checkout --expand-json-depth 2 \
+    --trace-id trace.trace_id --parent-id trace.parent_id --span-id trace.span_id \
+    --duration-ms duration_ms --service-name service.name`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			c := newClient(cmd)
 
 			name, _ := cmd.Flags().GetString("name")
 			description, _ := cmd.Flags().GetString("description")
+			expandJSONDepth, _ := cmd.Flags().GetInt("expand-json-depth")
 
 			dataset, err := c.CreateDataset(cmd.Context(), honeycomb.CreateDatasetRequest{
-				Name:        name,
-				Description: description,
+				Name:            name,
+				Description:     description,
+				ExpandJSONDepth: expandJSONDepth,
 			})
 			if err != nil {
 				return err
 			}
 
 			fmt.Fprintf(cmd.OutOrStdout(), "Created dataset %q (%v)\n", dataset.Name, dataset.Slug)
+
+			if cmd.Flags().Changed("delete-protected") {
+				deleteProtected, _ := cmd.Flags().GetBool("delete-protected")
+				_, err := c.UpdateDataset(cmd.Context(), dataset.Slug, honeycomb.UpdateDatasetRequest{
+					Settings: &honeycomb.UpdateDatasetSettingsRequest{DeleteProtected: &deleteProtected},
+				})
+				if err != nil {
+					return fmt.Errorf("setting delete protection: %w", err)
+				}
+			}
+
+			if definitions, changed := datasetDefinitionsFromFlags(cmd); changed {
+				if _, err := c.UpdateDatasetDefinitions(cmd.Context(), dataset.Slug, definitions); err != nil {
+					return fmt.Errorf("setting dataset definitions: %w", err)
+				}
+				fmt.Fprintf(cmd.OutOrStdout(), "Updated definitions for dataset %q\n", dataset.Slug)
+			}
 			return nil
 		},
 	}
 	cmd.Flags().String("name", "", "Dataset name")
 	_ = cmd.MarkFlagRequired("name")
 	cmd.Flags().String("description", "", "Dataset description")
+	cmd.Flags().Int("expand-json-depth", 0, "How many levels of nested JSON to unpack into columns (0-10)")
+	cmd.Flags().Bool("delete-protected", true, "Protect the dataset from deletion")
+	addDatasetDefinitionFlags(cmd)
+	return cmd
+}
+
+func newDatasetsUpdateCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "update <slug>",
+		Short: "Update a dataset's description and settings",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c := newClient(cmd)
+
+			var update honeycomb.UpdateDatasetRequest
+			if cmd.Flags().Changed("description") {
+				description, _ := cmd.Flags().GetString("description")
+				update.Description = &description
+			}
+			if cmd.Flags().Changed("expand-json-depth") {
+				expandJSONDepth, _ := cmd.Flags().GetInt("expand-json-depth")
+				update.ExpandJSONDepth = &expandJSONDepth
+			}
+			if cmd.Flags().Changed("delete-protected") {
+				deleteProtected, _ := cmd.Flags().GetBool("delete-protected")
+				update.Settings = &honeycomb.UpdateDatasetSettingsRequest{DeleteProtected: &deleteProtected}
+			}
+			if update.Description == nil && update.ExpandJSONDepth == nil && update.Settings == nil {
+				return fmt.Errorf("nothing to update (use --description, --expand-json-depth, or --delete-protected)")
+			}
+
+			dataset, err := c.UpdateDataset(cmd.Context(), args[0], update)
+			if err != nil {
+				return err
+			}
+
+			fmt.Fprintf(cmd.OutOrStdout(), "Updated dataset %q (%v)\n", dataset.Name, dataset.Slug)
+			return nil
+		},
+	}
+	cmd.Flags().String("description", "", "Dataset description")
+	cmd.Flags().Int("expand-json-depth", 0, "How many levels of nested JSON to unpack into columns (0-10)")
+	cmd.Flags().Bool("delete-protected", true, "Protect the dataset from deletion (use --delete-protected=false to allow deletion)")
 	return cmd
 }
 
 func newDatasetsDeleteCommand() *cobra.Command {
-	return &cobra.Command{
+	cmd := &cobra.Command{
 		Use:   "delete <slug>",
 		Short: "Delete a dataset",
-		Args:  cobra.ExactArgs(1),
+		Long: `Delete a dataset. Shows what will be lost and asks you to retype the slug to confirm.
+
+Before deleting, the dataset's configuration (settings, columns, derived columns, triggers,
+and SLOs) is exported to a local archive, so it can be inspected or recreated later.
+The events in the dataset cannot be recovered.`,
+		Args: cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			c := newClient(cmd)
+			slug := args[0]
+			yes, _ := cmd.Flags().GetBool("yes")
+			dryRun, _ := cmd.Flags().GetBool("dry-run")
+			noBackup, _ := cmd.Flags().GetBool("no-backup")
+			backupDir, _ := cmd.Flags().GetString("backup-dir")
+
+			inv, err := collectDatasetInventory(cmd.Context(), c, slug)
+			if err != nil {
+				return err
+			}
+
+			out := cmd.OutOrStdout()
+			fmt.Fprintf(out, "Deleting dataset %q will remove:\n", slug)
+			fmt.Fprintf(out, "  %v columns\n", len(inv.Columns))
+			fmt.Fprintf(out, "  %v derived columns\n", len(inv.DerivedColumns))
+			fmt.Fprintf(out, "  %v triggers\n", len(inv.Triggers))
+			fmt.Fprintf(out, "  %v SLOs\n", len(inv.SLOs))
+			fmt.Fprintf(out, "  %v markers\n", len(inv.Markers))
+			fmt.Fprintf(out, "and break %v board(s) with queries on it:\n", len(inv.Boards))
+			for _, b := range inv.Boards {
+				fmt.Fprintf(out, "  %v (%v)\n", b.Name, b.ID)
+			}
 
-			if err := c.DeleteDataset(cmd.Context(), args[0]); err != nil {
+			if inv.Dataset.Settings.DeleteProtected {
+				return fmt.Errorf("dataset %q is delete protected (use datasets update %v --delete-protected=false first)", slug, slug)
+			}
+
+			if dryRun {
+				fmt.Fprintln(out, "Dry run, nothing deleted.")
+				return nil
+			}
+
+			if !yes {
+				fmt.Fprintf(out, "Type the dataset slug to confirm: ")
+				answer, err := bufio.NewReader(cmd.InOrStdin()).ReadString('\n')
+				if err != nil && !errors.Is(err, io.EOF) {
+					return err
+				}
+				if strings.TrimSpace(answer) != slug {
+					return fmt.Errorf("confirmation did not match, dataset %q not deleted", slug)
+				}
+			}
+
+			if !noBackup {
+				path, err := writeDatasetBackup(backupDir, inv, time.Now())
+				if err != nil {
+					return fmt.Errorf("backing up dataset configuration: %w", err)
+				}
+				fmt.Fprintf(out, "Backed up configuration to %v\n", path)
+			}
+
+			if err := c.DeleteDataset(cmd.Context(), slug); err != nil {
 				return err
 			}
 
-			fmt.Fprintf(cmd.OutOrStdout(), "Deleted dataset %q\n", args[0])
+			fmt.Fprintf(out, "Deleted dataset %q\n", slug)
 			return nil
 		},
 	}
+	cmd.Flags().BoolP("yes", "y", false, "Skip the confirmation prompt")
+	cmd.Flags().Bool("dry-run", false, "Show what would be deleted without deleting anything")
+	cmd.Flags().Bool("no-backup", false, "Skip exporting the dataset configuration before deleting")
+	cmd.Flags().String("backup-dir", ".", "Directory to write the configuration archive to")
+	return cmd
+}
+
+// datasetInventory is everything configured on a dataset.
+type datasetInventory struct {
+	Dataset        *honeycomb.Dataset
+	Columns        []honeycomb.Column
+	DerivedColumns []honeycomb.DerivedColumn
+	Triggers       []honeycomb.Trigger
+	SLOs           []honeycomb.SLO
+	Markers        []honeycomb.Marker
+	// Boards with at least one query on the dataset.
+	Boards []honeycomb.Board
+}
+
+// collectDatasetInventory fetches the dataset and everything configured on it.
+func collectDatasetInventory(ctx context.Context, c *honeycomb.Client, slug string) (*datasetInventory, error) {
+	var inv datasetInventory
+	var err error
+
+	if inv.Dataset, err = c.GetDataset(ctx, slug); err != nil {
+		return nil, err
+	}
+	if inv.Columns, err = c.ListColumns(ctx, slug); err != nil {
+		return nil, fmt.Errorf("listing columns: %w", err)
+	}
+	if inv.DerivedColumns, err = c.ListDerivedColumns(ctx, slug); err != nil {
+		return nil, fmt.Errorf("listing derived columns: %w", err)
+	}
+	if inv.Triggers, err = c.ListTriggers(ctx, slug); err != nil {
+		return nil, fmt.Errorf("listing triggers: %w", err)
+	}
+	if inv.SLOs, err = c.ListSLOs(ctx, slug); err != nil {
+		return nil, fmt.Errorf("listing SLOs: %w", err)
+	}
+	if inv.Markers, err = c.ListMarkers(ctx, slug); err != nil {
+		return nil, fmt.Errorf("listing markers: %w", err)
+	}
+
+	boards, err := c.ListBoards(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("listing boards: %w", err)
+	}
+	for _, b := range boards {
+		for _, q := range b.Queries {
+			if q.Dataset == slug {
+				inv.Boards = append(inv.Boards, b)
+				break
+			}
+		}
+	}
+
+	return &inv, nil
+}
+
+// writeDatasetBackup writes the dataset configuration as JSON files in a gzipped tar archive
+// in dir, and returns the path of the archive.
+func writeDatasetBackup(dir string, inv *datasetInventory, now time.Time) (string, error) {
+	path := filepath.Join(dir, fmt.Sprintf("%v-%v.tar.gz", inv.Dataset.Slug, now.UTC().Format("20060102T150405Z")))
+
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0600)
+	if err != nil {
+		return "", err
+	}
+	defer func() {
+		_ = f.Close()
+	}()
+
+	gz := gzip.NewWriter(f)
+	tw := tar.NewWriter(gz)
+
+	files := []struct {
+		name string
+		v    any
+	}{
+		{"dataset.json", inv.Dataset},
+		{"columns.json", inv.Columns},
+		{"derived_columns.json", inv.DerivedColumns},
+		{"triggers.json", inv.Triggers},
+		{"slos.json", inv.SLOs},
+	}
+	for _, file := range files {
+		data, err := json.MarshalIndent(file.v, "", "  ")
+		if err != nil {
+			return "", err
+		}
+		if err := tw.WriteHeader(&tar.Header{Name: file.name, Mode: 0600, Size: int64(len(data)), ModTime: now}); err != nil {
+			return "", err
+		}
+		if _, err := tw.Write(data); err != nil {
+			return "", err
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		return "", err
+	}
+	if err := gz.Close(); err != nil {
+		return "", err
+	}
+	return path, f.Close()
 }
@@ -5,6 +5,8 @@ import (
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"path/filepath"
+	"strings"
 	"testing"
 
 	"maragu.dev/is"
@@ -110,21 +112,222 @@ func TestDatasetsCreateCommand(t *testing.T) {
 }
 
 func TestDatasetsDeleteCommand(t *testing.T) {
-	t.Run("deletes a dataset", func(t *testing.T) {
+	t.Run("deletes a dataset after confirmation and writes a backup", func(t *testing.T) {
+		var deleted bool
 		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			is.Equal(t, http.MethodDelete, r.Method)
-			w.WriteHeader(http.StatusNoContent)
+			switch r.Method + " " + r.URL.Path {
+			case "GET /1/datasets/old-dataset":
+				_ = json.NewEncoder(w).Encode(honeycomb.Dataset{Name: "old-dataset", Slug: "old-dataset"})
+			case "GET /1/columns/old-dataset":
+				_ = json.NewEncoder(w).Encode([]honeycomb.Column{{KeyName: "a"}, {KeyName: "b"}})
+			case "GET /1/triggers/old-dataset":
+				_ = json.NewEncoder(w).Encode([]honeycomb.Trigger{{ID: "t1", Name: "Errors"}})
+			case "GET /1/boards":
+				_ = json.NewEncoder(w).Encode([]honeycomb.Board{
+					{ID: "b1", Name: "Overview", Queries: []honeycomb.BoardQuery{{Dataset: "old-dataset", QueryID: "q1"}}},
+					{ID: "b2", Name: "Unrelated", Queries: []honeycomb.BoardQuery{{Dataset: "other", QueryID: "q2"}}},
+				})
+			case "DELETE /1/datasets/old-dataset":
+				deleted = true
+				w.WriteHeader(http.StatusNoContent)
+			default:
+				_, _ = w.Write([]byte("[]"))
+			}
 		}))
 		defer server.Close()
 
+		dir := t.TempDir()
+
 		var buf bytes.Buffer
 		root := cmd.NewRootCommand()
 		root.SetOut(&buf)
-		root.SetArgs([]string{"datasets", "delete", "old-dataset", "--api-key", "test", "--api-url", server.URL})
+		root.SetIn(strings.NewReader("old-dataset\n"))
+		root.SetArgs([]string{"datasets", "delete", "old-dataset", "--backup-dir", dir, "--api-key", "test", "--api-url", server.URL})
 
 		err := root.Execute()
 		is.NotError(t, err)
+		is.True(t, deleted)
+
+		output := buf.String()
+		is.True(t, contains(output, "2 columns"))
+		is.True(t, contains(output, "1 triggers"))
+		is.True(t, contains(output, "Overview (b1)"))
+		is.True(t, !contains(output, "Unrelated"))
+
+		matches, err := filepath.Glob(filepath.Join(dir, "old-dataset-*.tar.gz"))
+		is.NotError(t, err)
+		is.Equal(t, 1, len(matches))
+	})
+
+	t.Run("does not delete when the confirmation does not match", func(t *testing.T) {
+		var deleted bool
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch r.Method + " " + r.URL.Path {
+			case "GET /1/datasets/old-dataset":
+				_ = json.NewEncoder(w).Encode(honeycomb.Dataset{Name: "old-dataset", Slug: "old-dataset"})
+			case "DELETE /1/datasets/old-dataset":
+				deleted = true
+				w.WriteHeader(http.StatusNoContent)
+			default:
+				_, _ = w.Write([]byte("[]"))
+			}
+		}))
+		defer server.Close()
+
+		root := cmd.NewRootCommand()
+		root.SetOut(&bytes.Buffer{})
+		root.SetIn(strings.NewReader("old-datset\n"))
+		root.SetArgs([]string{"datasets", "delete", "old-dataset", "--no-backup", "--api-key", "test", "--api-url", server.URL})
+
+		err := root.Execute()
+		is.True(t, err != nil)
+		is.True(t, !deleted)
+	})
+
+	t.Run("skips the prompt with --yes", func(t *testing.T) {
+		var deleted bool
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch r.Method + " " + r.URL.Path {
+			case "GET /1/datasets/old-dataset":
+				_ = json.NewEncoder(w).Encode(honeycomb.Dataset{Name: "old-dataset", Slug: "old-dataset"})
+			case "DELETE /1/datasets/old-dataset":
+				deleted = true
+				w.WriteHeader(http.StatusNoContent)
+			default:
+				_, _ = w.Write([]byte("[]"))
+			}
+		}))
+		defer server.Close()
+
+		var buf bytes.Buffer
+		root := cmd.NewRootCommand()
+		root.SetOut(&buf)
+		root.SetArgs([]string{"datasets", "delete", "old-dataset", "--yes", "--no-backup", "--api-key", "test", "--api-url", server.URL})
 
+		err := root.Execute()
+		is.NotError(t, err)
+		is.True(t, deleted)
 		is.True(t, contains(buf.String(), "old-dataset"))
 	})
+
+	t.Run("does not delete on a dry run", func(t *testing.T) {
+		var deleted bool
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch r.Method + " " + r.URL.Path {
+			case "GET /1/datasets/old-dataset":
+				_ = json.NewEncoder(w).Encode(honeycomb.Dataset{Name: "old-dataset", Slug: "old-dataset"})
+			case "DELETE /1/datasets/old-dataset":
+				deleted = true
+				w.WriteHeader(http.StatusNoContent)
+			default:
+				_, _ = w.Write([]byte("[]"))
+			}
+		}))
+		defer server.Close()
+
+		var buf bytes.Buffer
+		root := cmd.NewRootCommand()
+		root.SetOut(&buf)
+		root.SetArgs([]string{"datasets", "delete", "old-dataset", "--dry-run", "--api-key", "test", "--api-url", server.URL})
+
+		err := root.Execute()
+		is.NotError(t, err)
+		is.True(t, !deleted)
+		is.True(t, contains(buf.String(), "Dry run"))
+	})
+
+	t.Run("refuses to delete a delete protected dataset", func(t *testing.T) {
+		var deleted bool
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch r.Method + " " + r.URL.Path {
+			case "GET /1/datasets/old-dataset":
+				_ = json.NewEncoder(w).Encode(honeycomb.Dataset{Name: "old-dataset", Slug: "old-dataset", Settings: honeycomb.DatasetSettings{DeleteProtected: true}})
+			case "DELETE /1/datasets/old-dataset":
+				deleted = true
+				w.WriteHeader(http.StatusNoContent)
+			default:
+				_, _ = w.Write([]byte("[]"))
+			}
+		}))
+		defer server.Close()
+
+		root := cmd.NewRootCommand()
+		root.SetOut(&bytes.Buffer{})
+		root.SetArgs([]string{"datasets", "delete", "old-dataset", "--yes", "--no-backup", "--api-key", "test", "--api-url", server.URL})
+
+		err := root.Execute()
+		is.True(t, err != nil)
+		is.True(t, contains(err.Error(), "delete protected"))
+		is.True(t, !deleted)
+	})
+}
+
+func TestDatasetsCreateCommandWithSettings(t *testing.T) {
+	t.Run("creates a dataset with settings and definitions", func(t *testing.T) {
+		var create honeycomb.CreateDatasetRequest
+		var definitions map[string]any
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch {
+			case r.Method == http.MethodPost && r.URL.Path == "/1/datasets":
+				_ = json.NewDecoder(r.Body).Decode(&create)
+				_ = json.NewEncoder(w).Encode(honeycomb.Dataset{Name: create.Name, Slug: "checkout"})
+			case r.Method == http.MethodPatch && r.URL.Path == "/1/dataset_definitions/checkout":
+				_ = json.NewDecoder(r.Body).Decode(&definitions)
+				_, _ = w.Write([]byte(`{}`))
+			default:
+				t.Errorf("unexpected request %v %v", r.Method, r.URL.Path)
+				http.Error(w, `{"error": "not found"}`, http.StatusNotFound)
+			}
+		}))
+		defer server.Close()
+
+		var buf bytes.Buffer
+		root := cmd.NewRootCommand()
+		root.SetOut(&buf)
+		root.SetArgs([]string{"datasets", "create", "--name", "checkout", "--expand-json-depth", "3",
+			"--trace-id", "trace.trace_id", "--duration-ms", "duration_ms",
+			"--api-key", "test", "--api-url", server.URL})
+
+		err := root.Execute()
+		is.NotError(t, err)
+
+		is.Equal(t, 3, create.ExpandJSONDepth)
+		is.Equal(t, 2, len(definitions))
+		is.Equal(t, "trace.trace_id", definitions["trace_id"].(map[string]any)["name"].(string))
+	})
+}
+
+func TestDatasetsUpdateCommand(t *testing.T) {
+	t.Run("turns off delete protection", func(t *testing.T) {
+		var update map[string]any
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			is.Equal(t, http.MethodPut, r.Method)
+			is.Equal(t, "/1/datasets/requests", r.URL.Path)
+			_ = json.NewDecoder(r.Body).Decode(&update)
+			_ = json.NewEncoder(w).Encode(honeycomb.Dataset{Name: "requests", Slug: "requests"})
+		}))
+		defer server.Close()
+
+		var buf bytes.Buffer
+		root := cmd.NewRootCommand()
+		root.SetOut(&buf)
+		root.SetArgs([]string{"datasets", "update", "requests", "--delete-protected=false",
+			"--api-key", "test", "--api-url", server.URL})
+
+		err := root.Execute()
+		is.NotError(t, err)
+
+		is.Equal(t, 1, len(update))
+		is.Equal(t, false, update["settings"].(map[string]any)["delete_protected"].(bool))
+		is.True(t, contains(buf.String(), "Updated dataset"))
+	})
+
+	t.Run("errors when nothing is given to update", func(t *testing.T) {
+		root := cmd.NewRootCommand()
+		root.SetOut(&bytes.Buffer{})
+		root.SetArgs([]string{"datasets", "update", "requests", "--api-key", "test", "--api-url", "http://localhost:0"})
+
+		err := root.Execute()
+		is.True(t, err != nil)
+	})
 }
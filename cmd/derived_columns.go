@@ -0,0 +1,276 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"github.com/maragudk/honeycomb-cli/honeycomb"
+)
+
+func newDerivedColumnsCommand() *cobra.Command {
+	derivedColumnsCmd := &cobra.Command{
+		Use:   "derived-columns",
+		Short: "Manage derived columns in a dataset",
+	}
+
+	derivedColumnsCmd.PersistentFlags().String("dataset", "", "Dataset slug (required, use __all__ for environment-wide)")
+	_ = derivedColumnsCmd.MarkPersistentFlagRequired("dataset")
+
+	derivedColumnsCmd.AddCommand(newDerivedColumnsListCommand())
+	derivedColumnsCmd.AddCommand(newDerivedColumnsGetCommand())
+	derivedColumnsCmd.AddCommand(newDerivedColumnsCreateCommand())
+	derivedColumnsCmd.AddCommand(newDerivedColumnsUpdateCommand())
+	derivedColumnsCmd.AddCommand(newDerivedColumnsDeleteCommand())
+	derivedColumnsCmd.AddCommand(newDerivedColumnsValidateCommand())
+
+	return derivedColumnsCmd
+}
+
+func newDerivedColumnsListCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List derived columns",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c := newClient(cmd)
+			dataset, _ := cmd.Flags().GetString("dataset")
+
+			columns, err := c.ListDerivedColumns(cmd.Context(), dataset)
+			if err != nil {
+				return err
+			}
+
+			asJSON, _ := cmd.Flags().GetBool("json")
+			if asJSON {
+				return json.NewEncoder(cmd.OutOrStdout()).Encode(columns)
+			}
+
+			w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 2, ' ', 0)
+			fmt.Fprintln(w, "ID\tALIAS\tEXPRESSION\tDESCRIPTION")
+			for _, dc := range columns {
+				fmt.Fprintf(w, "%v\t%v\t%v\t%v\n", dc.ID, dc.Alias, dc.Expression, dc.Description)
+			}
+			return w.Flush()
+		},
+	}
+	cmd.Flags().Bool("json", false, "Output as JSON")
+	return cmd
+}
+
+func newDerivedColumnsGetCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "get <id>",
+		Short: "Get a derived column by ID",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c := newClient(cmd)
+			dataset, _ := cmd.Flags().GetString("dataset")
+
+			dc, err := c.GetDerivedColumn(cmd.Context(), dataset, args[0])
+			if err != nil {
+				return err
+			}
+
+			asJSON, _ := cmd.Flags().GetBool("json")
+			if asJSON {
+				return json.NewEncoder(cmd.OutOrStdout()).Encode(dc)
+			}
+
+			fmt.Fprintf(cmd.OutOrStdout(), "ID:          %v\n", dc.ID)
+			fmt.Fprintf(cmd.OutOrStdout(), "Alias:       %v\n", dc.Alias)
+			fmt.Fprintf(cmd.OutOrStdout(), "Expression:  %v\n", dc.Expression)
+			fmt.Fprintf(cmd.OutOrStdout(), "Description: %v\n", dc.Description)
+			return nil
+		},
+	}
+	cmd.Flags().Bool("json", false, "Output as JSON")
+	return cmd
+}
+
+func newDerivedColumnsCreateCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "create",
+		Short: "Create a derived column",
+		Long: `Create a derived column. The expression is validated locally before it is sent,
+including checking that every referenced column exists in the dataset.
+
+Example:
+  honeycomb-cli derived-columns create --dataset requests --alias is_error \
+    --expression 'GTE($status_code, 500)'`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c := newClient(cmd)
+			dataset, _ := cmd.Flags().GetString("dataset")
+			alias, _ := cmd.Flags().GetString("alias")
+			expression, _ := cmd.Flags().GetString("expression")
+			description, _ := cmd.Flags().GetString("description")
+			skipValidation, _ := cmd.Flags().GetBool("skip-validation")
+
+			if !skipValidation {
+				if err := validateDerivedColumnExpression(cmd.Context(), c, dataset, expression); err != nil {
+					return err
+				}
+			}
+
+			dc, err := c.CreateDerivedColumn(cmd.Context(), dataset, honeycomb.DerivedColumn{
+				Alias:       alias,
+				Expression:  expression,
+				Description: description,
+			})
+			if err != nil {
+				return err
+			}
+
+			fmt.Fprintf(cmd.OutOrStdout(), "Created derived column %q (%v)\n", dc.Alias, dc.ID)
+			return nil
+		},
+	}
+	cmd.Flags().String("alias", "", "Derived column name")
+	_ = cmd.MarkFlagRequired("alias")
+	cmd.Flags().String("expression", "", "Derived column expression")
+	_ = cmd.MarkFlagRequired("expression")
+	cmd.Flags().String("description", "", "Derived column description")
+	cmd.Flags().Bool("skip-validation", false, "Send the expression without validating it locally")
+	return cmd
+}
+
+func newDerivedColumnsUpdateCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "update <id>",
+		Short: "Update a derived column",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c := newClient(cmd)
+			dataset, _ := cmd.Flags().GetString("dataset")
+			skipValidation, _ := cmd.Flags().GetBool("skip-validation")
+
+			dc, err := c.GetDerivedColumn(cmd.Context(), dataset, args[0])
+			if err != nil {
+				return err
+			}
+
+			if cmd.Flags().Changed("alias") {
+				dc.Alias, _ = cmd.Flags().GetString("alias")
+			}
+			if cmd.Flags().Changed("description") {
+				dc.Description, _ = cmd.Flags().GetString("description")
+			}
+			if cmd.Flags().Changed("expression") {
+				dc.Expression, _ = cmd.Flags().GetString("expression")
+				if !skipValidation {
+					if err := validateDerivedColumnExpression(cmd.Context(), c, dataset, dc.Expression); err != nil {
+						return err
+					}
+				}
+			}
+
+			dc, err = c.UpdateDerivedColumn(cmd.Context(), dataset, *dc)
+			if err != nil {
+				return err
+			}
+
+			fmt.Fprintf(cmd.OutOrStdout(), "Updated derived column %q (%v)\n", dc.Alias, dc.ID)
+			return nil
+		},
+	}
+	cmd.Flags().String("alias", "", "Derived column name")
+	cmd.Flags().String("expression", "", "Derived column expression")
+	cmd.Flags().String("description", "", "Derived column description")
+	cmd.Flags().Bool("skip-validation", false, "Send the expression without validating it locally")
+	return cmd
+}
+
+func newDerivedColumnsDeleteCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "delete <id>",
+		Short: "Delete a derived column",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c := newClient(cmd)
+			dataset, _ := cmd.Flags().GetString("dataset")
+
+			if err := c.DeleteDerivedColumn(cmd.Context(), dataset, args[0]); err != nil {
+				return err
+			}
+
+			fmt.Fprintf(cmd.OutOrStdout(), "Deleted derived column %v\n", args[0])
+			return nil
+		},
+	}
+}
+
+func newDerivedColumnsValidateCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "validate <expression>",
+		Short: "Validate a derived column expression without creating it",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c := newClient(cmd)
+			dataset, _ := cmd.Flags().GetString("dataset")
+
+			if err := validateDerivedColumnExpression(cmd.Context(), c, dataset, args[0]); err != nil {
+				return err
+			}
+
+			fmt.Fprintln(cmd.OutOrStdout(), "Expression is valid.")
+			return nil
+		},
+	}
+}
+
+// validateDerivedColumnExpression checks the expression syntax and that every referenced column
+// exists in the dataset, either as a regular column or as another derived column in the dataset or environment.
+func validateDerivedColumnExpression(ctx context.Context, c *honeycomb.Client, dataset, expression string) error {
+	referenced, err := honeycomb.ValidateExpression(expression)
+	if err != nil {
+		var exprErr *honeycomb.ExpressionError
+		if errors.As(err, &exprErr) {
+			return fmt.Errorf("invalid expression:\n  %v\n  %v^ %v", expression, strings.Repeat(" ", exprErr.Pos), exprErr.Msg)
+		}
+		return err
+	}
+
+	// Environment-wide derived columns can reference columns in any dataset.
+	if dataset == "__all__" || len(referenced) == 0 {
+		return nil
+	}
+
+	known := map[string]bool{}
+	columns, err := c.ListColumns(ctx, dataset)
+	if err != nil {
+		return fmt.Errorf("listing columns: %w", err)
+	}
+	for _, col := range columns {
+		known[col.KeyName] = true
+	}
+	derived, err := c.ListDerivedColumns(ctx, dataset)
+	if err != nil {
+		return fmt.Errorf("listing derived columns: %w", err)
+	}
+	for _, dc := range derived {
+		known[dc.Alias] = true
+	}
+	// Environment-wide derived columns can be used in every dataset
+	environmentDerived, err := c.ListDerivedColumns(ctx, "__all__")
+	if err != nil {
+		return fmt.Errorf("listing environment-wide derived columns: %w", err)
+	}
+	for _, dc := range environmentDerived {
+		known[dc.Alias] = true
+	}
+
+	var unknown []string
+	for _, name := range referenced {
+		if !known[name] {
+			unknown = append(unknown, name)
+		}
+	}
+	if len(unknown) > 0 {
+		return fmt.Errorf("expression references unknown column(s) in dataset %v: %v", dataset, strings.Join(unknown, ", "))
+	}
+	return nil
+}
@@ -0,0 +1,167 @@
+package cmd_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"maragu.dev/is"
+
+	"github.com/maragudk/honeycomb-cli/cmd"
+	"github.com/maragudk/honeycomb-cli/honeycomb"
+)
+
+func TestDerivedColumnsListCommand(t *testing.T) {
+	t.Run("lists derived columns in a table", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			is.Equal(t, "/1/derived_columns/requests", r.URL.Path)
+			_ = json.NewEncoder(w).Encode([]honeycomb.DerivedColumn{
+				{ID: "dc1", Alias: "is_error", Expression: "GTE($status_code, 500)"},
+			})
+		}))
+		defer server.Close()
+
+		var buf bytes.Buffer
+		root := cmd.NewRootCommand()
+		root.SetOut(&buf)
+		root.SetArgs([]string{"derived-columns", "list", "--dataset", "requests", "--api-key", "test", "--api-url", server.URL})
+
+		err := root.Execute()
+		is.NotError(t, err)
+
+		output := buf.String()
+		is.True(t, contains(output, "is_error"))
+		is.True(t, contains(output, "GTE($status_code, 500)"))
+	})
+}
+
+func TestDerivedColumnsCreateCommand(t *testing.T) {
+	t.Run("creates a derived column referencing known columns", func(t *testing.T) {
+		var created honeycomb.DerivedColumn
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch r.Method + " " + r.URL.Path {
+			case "GET /1/columns/requests":
+				_ = json.NewEncoder(w).Encode([]honeycomb.Column{{KeyName: "duration_ms"}})
+			case "GET /1/derived_columns/requests":
+				_ = json.NewEncoder(w).Encode([]honeycomb.DerivedColumn{{ID: "dc1", Alias: "is_error"}})
+			case "GET /1/derived_columns/__all__":
+				_, _ = w.Write([]byte(`[]`))
+			case "POST /1/derived_columns/requests":
+				_ = json.NewDecoder(r.Body).Decode(&created)
+				_ = json.NewEncoder(w).Encode(honeycomb.DerivedColumn{ID: "dc2", Alias: created.Alias, Expression: created.Expression})
+			default:
+				t.Errorf("unexpected request %v %v", r.Method, r.URL.Path)
+				http.Error(w, `{"error": "not found"}`, http.StatusNotFound)
+			}
+		}))
+		defer server.Close()
+
+		var buf bytes.Buffer
+		root := cmd.NewRootCommand()
+		root.SetOut(&buf)
+		root.SetArgs([]string{"derived-columns", "create", "--dataset", "requests", "--alias", "slow_error",
+			"--expression", "AND($is_error, GT($duration_ms, 1000))", "--api-key", "test", "--api-url", server.URL})
+
+		err := root.Execute()
+		is.NotError(t, err)
+
+		is.Equal(t, "slow_error", created.Alias)
+		is.True(t, contains(buf.String(), "dc2"))
+	})
+
+	t.Run("rejects a syntax error before sending", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			t.Errorf("unexpected request %v %v", r.Method, r.URL.Path)
+			http.Error(w, `{"error": "not found"}`, http.StatusNotFound)
+		}))
+		defer server.Close()
+
+		root := cmd.NewRootCommand()
+		root.SetOut(&bytes.Buffer{})
+		root.SetArgs([]string{"derived-columns", "create", "--dataset", "requests", "--alias", "broken",
+			"--expression", "GT($duration_ms 1000)", "--api-key", "test", "--api-url", server.URL})
+
+		err := root.Execute()
+		is.True(t, err != nil)
+		is.True(t, contains(err.Error(), "invalid expression"))
+	})
+
+	t.Run("rejects unknown column references before sending", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch r.Method + " " + r.URL.Path {
+			case "GET /1/columns/requests":
+				_ = json.NewEncoder(w).Encode([]honeycomb.Column{{KeyName: "duration_ms"}})
+			case "GET /1/derived_columns/requests", "GET /1/derived_columns/__all__":
+				_, _ = w.Write([]byte(`[]`))
+			default:
+				t.Errorf("unexpected request %v %v", r.Method, r.URL.Path)
+				http.Error(w, `{"error": "not found"}`, http.StatusNotFound)
+			}
+		}))
+		defer server.Close()
+
+		root := cmd.NewRootCommand()
+		root.SetOut(&bytes.Buffer{})
+		root.SetArgs([]string{"derived-columns", "create", "--dataset", "requests", "--alias", "broken",
+			"--expression", "GT($latency, 1000)", "--api-key", "test", "--api-url", server.URL})
+
+		err := root.Execute()
+		is.True(t, err != nil)
+		is.True(t, contains(err.Error(), "latency"))
+	})
+}
+
+func TestDerivedColumnsValidateCommand(t *testing.T) {
+	t.Run("reports a valid expression", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch r.URL.Path {
+			case "/1/columns/requests":
+				_ = json.NewEncoder(w).Encode([]honeycomb.Column{{KeyName: "duration_ms"}})
+			case "/1/derived_columns/requests", "/1/derived_columns/__all__":
+				_, _ = w.Write([]byte(`[]`))
+			default:
+				t.Errorf("unexpected path %v", r.URL.Path)
+				http.Error(w, `{"error": "not found"}`, http.StatusNotFound)
+			}
+		}))
+		defer server.Close()
+
+		var buf bytes.Buffer
+		root := cmd.NewRootCommand()
+		root.SetOut(&buf)
+		root.SetArgs([]string{"derived-columns", "validate", `BUCKET($duration_ms, 100)`, "--dataset", "requests",
+			"--api-key", "test", "--api-url", server.URL})
+
+		err := root.Execute()
+		is.NotError(t, err)
+		is.True(t, contains(buf.String(), "valid"))
+	})
+	t.Run("accepts references to environment-wide derived columns", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch r.URL.Path {
+			case "/1/columns/requests":
+				_ = json.NewEncoder(w).Encode([]honeycomb.Column{{KeyName: "duration_ms"}})
+			case "/1/derived_columns/requests":
+				_, _ = w.Write([]byte(`[]`))
+			case "/1/derived_columns/__all__":
+				_ = json.NewEncoder(w).Encode([]honeycomb.DerivedColumn{{ID: "dc1", Alias: "is_error"}})
+			default:
+				t.Errorf("unexpected path %v", r.URL.Path)
+				http.Error(w, `{"error": "not found"}`, http.StatusNotFound)
+			}
+		}))
+		defer server.Close()
+
+		var buf bytes.Buffer
+		root := cmd.NewRootCommand()
+		root.SetOut(&buf)
+		root.SetArgs([]string{"derived-columns", "validate", `AND($is_error, GT($duration_ms, 1000))`, "--dataset", "requests",
+			"--api-key", "test", "--api-url", server.URL})
+
+		err := root.Execute()
+		is.NotError(t, err)
+		is.True(t, contains(buf.String(), "valid"))
+	})
+}
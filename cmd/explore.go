@@ -0,0 +1,706 @@
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+
+	"github.com/maragudk/honeycomb-cli/honeycomb"
+)
+
+// ANSI escape codes for mouse click and wheel reporting.
+const (
+	enableMouse  = "\x1b[?1000h\x1b[?1006h"
+	disableMouse = "\x1b[?1006l\x1b[?1000l"
+)
+
+type keyKind int
+
+const (
+	keyUnknown keyKind = iota
+	keyRune
+	keyEnter
+	keyEscape
+	keyBackspace
+	keyTab
+	keyUp
+	keyDown
+	keyQuit
+	keyClick
+)
+
+// key is a key press or mouse click read from the terminal. Row is the 1-based screen row of a click.
+type key struct {
+	kind keyKind
+	r    rune
+	row  int
+}
+
+// readKey from terminal input in raw mode, including arrow keys and SGR mouse events.
+func readKey(r *bufio.Reader) (key, error) {
+	c, _, err := r.ReadRune()
+	if err != nil {
+		return key{}, err
+	}
+
+	switch c {
+	case '\r', '\n':
+		return key{kind: keyEnter}, nil
+	case '\t':
+		return key{kind: keyTab}, nil
+	case 127, '\b':
+		return key{kind: keyBackspace}, nil
+	case 3, 4:
+		return key{kind: keyQuit}, nil
+	case 0x1b:
+		if r.Buffered() == 0 {
+			return key{kind: keyEscape}, nil
+		}
+		next, err := r.ReadByte()
+		if err != nil {
+			return key{}, err
+		}
+		if next != '[' && next != 'O' {
+			_ = r.UnreadByte()
+			return key{kind: keyEscape}, nil
+		}
+		return readEscapeSequence(r)
+	}
+
+	if c < ' ' {
+		return key{kind: keyUnknown}, nil
+	}
+	return key{kind: keyRune, r: c}, nil
+}
+
+// readEscapeSequence after the "ESC [" or "ESC O" prefix.
+func readEscapeSequence(r *bufio.Reader) (key, error) {
+	var params []byte
+	for {
+		b, err := r.ReadByte()
+		if err != nil {
+			return key{}, err
+		}
+		if b < 0x40 || b > 0x7e {
+			params = append(params, b)
+			continue
+		}
+
+		switch {
+		case b == 'A':
+			return key{kind: keyUp}, nil
+		case b == 'B':
+			return key{kind: keyDown}, nil
+		case (b == 'M' || b == 'm') && len(params) > 0 && params[0] == '<':
+			// SGR mouse event like "<0;12;5M", with the button, column, and row
+			fields := strings.Split(string(params[1:]), ";")
+			if len(fields) != 3 || b == 'm' {
+				return key{kind: keyUnknown}, nil
+			}
+			button, _ := strconv.Atoi(fields[0])
+			row, _ := strconv.Atoi(fields[2])
+			switch button {
+			case 0:
+				return key{kind: keyClick, row: row}, nil
+			case 64:
+				return key{kind: keyUp}, nil
+			case 65:
+				return key{kind: keyDown}, nil
+			}
+		}
+		return key{kind: keyUnknown}, nil
+	}
+}
+
+type exploreScreen int
+
+const (
+	screenDatasets exploreScreen = iota
+	screenQuery
+	screenResults
+)
+
+// explorer is the state of the explore terminal UI.
+type explorer struct {
+	ctx    context.Context
+	c      *honeycomb.Client
+	out    io.Writer
+	h      highlighter
+	width  int
+	height int
+
+	screen   exploreScreen
+	datasets []honeycomb.Dataset
+	dataset  string
+	columns  []honeycomb.Column
+	spec     honeycomb.QuerySpec
+	history  []honeycomb.QuerySpec
+	results  []map[string]any
+
+	// input is the search on the datasets screen and the command line on the query screen.
+	input    string
+	selected int
+	status   string
+	quit     bool
+
+	// firstRowLine, firstRowIndex, and lastRowIndex map screen rows to rendered list rows, for mouse clicks.
+	firstRowLine  int
+	firstRowIndex int
+	lastRowIndex  int
+}
+
+func newExploreCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "explore",
+		Short: "Explore datasets and build queries in an interactive terminal UI",
+		Long: `Explore datasets and build queries in a full-screen terminal UI.
+
+Pick a dataset, then build a query on the command line at the bottom of the screen,
+with Tab to complete column names from the list of matching columns:
+
+  calc OP[:column]           add a calculation (e.g. calc COUNT, calc P99:duration_ms)
+  by column                  add a breakdown
+  where column op [value]    add a filter (e.g. where status_code >= 500)
+  time duration              set the time range (e.g. time 30m, time 1d)
+  run                        run the query (or press Enter on an empty line)
+  undo, reset                undo the last change, or start over
+  datasets, quit             go back to the datasets, or quit
+
+In the results, select a row with the arrow keys and press Enter, or click it,
+to drill down by adding its breakdown values as filters. Press Esc to go back.
+
+Example:
+  honeycomb-cli explore --dataset requests`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			dataset, _ := cmd.Flags().GetString("dataset")
+
+			out := cmd.OutOrStdout()
+			e := &explorer{
+				ctx:    cmd.Context(),
+				c:      newClient(cmd),
+				out:    out,
+				h:      newHighlighter(out),
+				width:  80,
+				height: 24,
+				spec:   honeycomb.QuerySpec{TimeRange: 7200},
+			}
+
+			if f, ok := out.(*os.File); ok && isTerminal(out) {
+				if width, height, err := term.GetSize(int(f.Fd())); err == nil {
+					e.width, e.height = width, height
+				}
+			}
+
+			// Input that isn't a terminal, like a pipe, is read as keystrokes as they are
+			if f, ok := cmd.InOrStdin().(*os.File); ok && term.IsTerminal(int(f.Fd())) {
+				state, err := term.MakeRaw(int(f.Fd()))
+				if err != nil {
+					return err
+				}
+				defer func() { _ = term.Restore(int(f.Fd()), state) }()
+			}
+
+			fmt.Fprint(out, enterAlternateScreen+enableMouse)
+			defer fmt.Fprint(out, disableMouse+exitAlternateScreen)
+
+			if err := e.loadDatasets(); err != nil {
+				return err
+			}
+			if dataset != "" {
+				e.openDataset(dataset)
+			}
+
+			return e.run(bufio.NewReader(cmd.InOrStdin()))
+		},
+	}
+	cmd.Flags().String("dataset", "", "Dataset to start exploring, instead of picking one")
+	return cmd
+}
+
+// run the UI until the user quits or the input ends.
+func (e *explorer) run(in *bufio.Reader) error {
+	for !e.quit {
+		e.draw()
+		k, err := readKey(in)
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		e.handleKey(k)
+	}
+	return nil
+}
+
+func (e *explorer) draw() {
+	lines := strings.Split(strings.TrimSuffix(e.render(), "\n"), "\n")
+	if len(lines) > e.height {
+		lines = lines[:e.height]
+	}
+	fmt.Fprint(e.out, clearScreen+strings.Join(lines, "\r\n"))
+}
+
+func (e *explorer) loadDatasets() error {
+	datasets, err := e.c.ListDatasets(e.ctx)
+	if err != nil {
+		return err
+	}
+	slices.SortFunc(datasets, func(a, b honeycomb.Dataset) int { return strings.Compare(a.Slug, b.Slug) })
+	e.datasets = datasets
+	return nil
+}
+
+func (e *explorer) openDataset(slug string) {
+	columns, err := e.c.ListColumns(e.ctx, slug)
+	if err != nil {
+		e.status = fmt.Sprintf("Error listing columns in %v: %v", slug, err)
+		return
+	}
+	slices.SortFunc(columns, func(a, b honeycomb.Column) int { return strings.Compare(a.KeyName, b.KeyName) })
+
+	e.dataset, e.columns = slug, columns
+	e.spec = honeycomb.QuerySpec{TimeRange: 7200}
+	e.history, e.results = nil, nil
+	e.screen, e.input, e.selected, e.status = screenQuery, "", 0, ""
+}
+
+func (e *explorer) handleKey(k key) {
+	if k.kind == keyQuit {
+		e.quit = true
+		return
+	}
+
+	switch e.screen {
+	case screenDatasets:
+		e.handleDatasetsKey(k)
+	case screenQuery:
+		e.handleQueryKey(k)
+	case screenResults:
+		e.handleResultsKey(k)
+	}
+}
+
+func (e *explorer) handleDatasetsKey(k key) {
+	matches := e.matchingDatasets()
+	switch k.kind {
+	case keyRune:
+		e.input += string(k.r)
+		e.selected = 0
+	case keyBackspace:
+		e.input = dropLastRune(e.input)
+		e.selected = 0
+	case keyEscape:
+		e.input, e.selected = "", 0
+	case keyUp, keyDown:
+		e.selected = moveSelection(e.selected, len(matches), k.kind)
+	case keyClick:
+		if i, ok := e.clickedRow(k.row, len(matches)); ok {
+			e.openDataset(matches[i].Slug)
+		}
+	case keyEnter:
+		if len(matches) > 0 {
+			e.openDataset(matches[e.selected].Slug)
+		}
+	}
+}
+
+func (e *explorer) handleQueryKey(k key) {
+	matches := e.matchingColumns()
+	switch k.kind {
+	case keyRune:
+		e.input += string(k.r)
+		e.selected = 0
+	case keyBackspace:
+		e.input = dropLastRune(e.input)
+		e.selected = 0
+	case keyEscape:
+		if e.input != "" {
+			e.input, e.selected = "", 0
+		} else if e.results != nil {
+			e.screen, e.selected = screenResults, 0
+		}
+	case keyUp, keyDown:
+		e.selected = moveSelection(e.selected, len(matches), k.kind)
+	case keyTab:
+		if len(matches) > 0 {
+			e.input = completeColumn(e.input, matches[e.selected].KeyName)
+			e.selected = 0
+		}
+	case keyClick:
+		if i, ok := e.clickedRow(k.row, len(matches)); ok {
+			e.input = completeColumn(e.input, matches[i].KeyName)
+			e.selected = 0
+		}
+	case keyEnter:
+		input := strings.TrimSpace(e.input)
+		e.input, e.selected = "", 0
+		e.runCommand(input)
+	}
+}
+
+func (e *explorer) handleResultsKey(k key) {
+	switch k.kind {
+	case keyUp, keyDown:
+		e.selected = moveSelection(e.selected, len(e.results), k.kind)
+	case keyEscape:
+		e.screen, e.selected = screenQuery, 0
+	case keyClick:
+		if i, ok := e.clickedRow(k.row, len(e.results)); ok {
+			e.selected = i
+			e.drillDown()
+		}
+	case keyEnter:
+		e.drillDown()
+	case keyRune:
+		// Start typing a command on the query screen
+		e.screen, e.selected, e.input = screenQuery, 0, string(k.r)
+	}
+}
+
+// runCommand from the query screen's command line.
+func (e *explorer) runCommand(input string) {
+	e.status = ""
+	command, arg, _ := strings.Cut(input, " ")
+	arg = strings.TrimSpace(arg)
+
+	switch command {
+	case "", "run":
+		e.runQuery()
+
+	case "calc":
+		calc, err := ParseCalculation(arg)
+		if err != nil {
+			e.status = err.Error()
+			return
+		}
+		e.change(func(spec *honeycomb.QuerySpec) { spec.Calculations = append(spec.Calculations, calc) })
+
+	case "by":
+		if arg == "" {
+			e.status = "by needs a column"
+			return
+		}
+		e.change(func(spec *honeycomb.QuerySpec) { spec.Breakdowns = append(spec.Breakdowns, arg) })
+
+	case "where":
+		filter, err := ParseFilter(arg)
+		if err != nil {
+			e.status = err.Error()
+			return
+		}
+		e.change(func(spec *honeycomb.QuerySpec) { spec.Filters = append(spec.Filters, filter) })
+
+	case "time":
+		d, err := parseLookback(arg)
+		if err != nil {
+			e.status = err.Error()
+			return
+		}
+		e.change(func(spec *honeycomb.QuerySpec) { spec.TimeRange = int(d.Seconds()) })
+
+	case "undo":
+		if len(e.history) == 0 {
+			e.status = "Nothing to undo"
+			return
+		}
+		e.spec = e.history[len(e.history)-1]
+		e.history = e.history[:len(e.history)-1]
+
+	case "reset":
+		e.change(func(spec *honeycomb.QuerySpec) { *spec = honeycomb.QuerySpec{TimeRange: 7200} })
+
+	case "datasets":
+		e.screen, e.input, e.selected = screenDatasets, "", 0
+
+	case "quit", "q", "exit":
+		e.quit = true
+
+	default:
+		e.status = fmt.Sprintf("Unknown command %q", command)
+	}
+}
+
+// change the query spec, saving the previous one for undo.
+func (e *explorer) change(fn func(spec *honeycomb.QuerySpec)) {
+	e.history = append(e.history, cloneQuerySpec(e.spec))
+	fn(&e.spec)
+}
+
+func cloneQuerySpec(spec honeycomb.QuerySpec) honeycomb.QuerySpec {
+	spec.Calculations = slices.Clone(spec.Calculations)
+	spec.Breakdowns = slices.Clone(spec.Breakdowns)
+	spec.Filters = slices.Clone(spec.Filters)
+	spec.Orders = slices.Clone(spec.Orders)
+	return spec
+}
+
+func (e *explorer) runQuery() {
+	spec := e.spec
+	if len(spec.Calculations) == 0 {
+		spec.Calculations = []honeycomb.Calculation{{Op: "COUNT"}}
+	}
+
+	e.status = "Running query..."
+	e.draw()
+
+	result, err := e.c.RunQuery(e.ctx, e.dataset, spec)
+	if err != nil {
+		e.status = fmt.Sprintf("Error running query: %v", err)
+		return
+	}
+	e.status = ""
+	e.results = result.Data.Results
+	if e.results == nil {
+		e.results = []map[string]any{}
+	}
+	e.screen, e.selected = screenResults, 0
+}
+
+// drillDown into the selected result row, by replacing the breakdowns with filters on the row's values,
+// and re-running the query.
+func (e *explorer) drillDown() {
+	if len(e.results) == 0 {
+		return
+	}
+	if len(e.spec.Breakdowns) == 0 {
+		e.status = "Add a breakdown to drill down into the results"
+		return
+	}
+
+	row := e.results[e.selected]
+	e.change(func(spec *honeycomb.QuerySpec) {
+		for _, b := range spec.Breakdowns {
+			if v, ok := row[b]; ok && v != nil {
+				spec.Filters = append(spec.Filters, honeycomb.Filter{Column: b, Op: "=", Value: v})
+			} else {
+				spec.Filters = append(spec.Filters, honeycomb.Filter{Column: b, Op: "does-not-exist"})
+			}
+		}
+		spec.Breakdowns = nil
+	})
+	e.runQuery()
+}
+
+func (e *explorer) matchingDatasets() []honeycomb.Dataset {
+	search := strings.ToLower(e.input)
+	var matches []honeycomb.Dataset
+	for _, d := range e.datasets {
+		if strings.Contains(strings.ToLower(d.Slug), search) || strings.Contains(strings.ToLower(d.Name), search) {
+			matches = append(matches, d)
+		}
+	}
+	return matches
+}
+
+// matchingColumns for the word being typed on the command line, after any calculation operator like "P99:".
+func (e *explorer) matchingColumns() []honeycomb.Column {
+	search := strings.ToLower(completionPrefix(e.input))
+	var prefixed, contained []honeycomb.Column
+	for _, col := range e.columns {
+		name := strings.ToLower(col.KeyName)
+		switch {
+		case strings.HasPrefix(name, search):
+			prefixed = append(prefixed, col)
+		case strings.Contains(name, search):
+			contained = append(contained, col)
+		}
+	}
+	return append(prefixed, contained...)
+}
+
+// completionPrefix is the part of the last word of the input that's a column name.
+func completionPrefix(input string) string {
+	word := input[strings.LastIndex(input, " ")+1:]
+	return word[strings.LastIndex(word, ":")+1:]
+}
+
+// completeColumn replaces the column name being typed with the given column.
+func completeColumn(input, column string) string {
+	return strings.TrimSuffix(input, completionPrefix(input)) + column + " "
+}
+
+func (e *explorer) render() string {
+	var b strings.Builder
+	line := func(format string, args ...any) {
+		b.WriteString(truncate(fmt.Sprintf(format, args...), e.width) + "\n")
+	}
+
+	switch e.screen {
+	case screenDatasets:
+		line("Datasets (type to search, ↑/↓ and Enter to open, Ctrl-C to quit)")
+		line("Search: %v", e.input)
+		line("")
+		matches := e.matchingDatasets()
+		if len(matches) == 0 {
+			line("No matching datasets.")
+		}
+		e.renderList(&b, len(matches), e.height-strings.Count(b.String(), "\n")-2, func(i int) string {
+			d := matches[i]
+			if d.Description != "" {
+				return fmt.Sprintf("%-30v %v", d.Slug, d.Description)
+			}
+			return d.Slug
+		})
+
+	case screenQuery:
+		line("%v (Tab completes columns, Enter runs commands, Esc goes back, Ctrl-C quits)", e.dataset)
+		line("")
+		b.WriteString(describeQuery(e.spec, e.width))
+		line("")
+		line("Commands: calc, by, where, time, run, undo, reset, datasets, quit (see --help)")
+		line("")
+		matches := e.matchingColumns()
+		line("Columns matching %q (%v of %v):", completionPrefix(e.input), len(matches), len(e.columns))
+		e.renderList(&b, len(matches), e.height-strings.Count(b.String(), "\n")-3, func(i int) string {
+			col := matches[i]
+			return strings.TrimRight(fmt.Sprintf("%-40v %-8v %v", col.KeyName, col.Type, col.Description), " ")
+		})
+		line("")
+		line("> %v", e.input)
+
+	case screenResults:
+		line("%v results (↑/↓ and Enter or click to drill down, Esc to edit the query, Ctrl-C quits)", e.dataset)
+		line("")
+		b.WriteString(describeQuery(e.spec, e.width))
+		line("")
+		if len(e.results) == 0 {
+			line("No results.")
+			break
+		}
+		headers := queryHeaders(e.results)
+		rows := [][]string{headers}
+		for _, row := range e.results {
+			rows = append(rows, queryCells(row, headers))
+		}
+		table := strings.Split(strings.TrimSuffix(formatTable(rows), "\n"), "\n")
+		line("  %v", table[0])
+		e.renderList(&b, len(e.results), e.height-strings.Count(b.String(), "\n")-2, func(i int) string {
+			return table[i+1]
+		})
+	}
+
+	if e.status != "" {
+		b.WriteString("\n" + truncate(e.status, e.width) + "\n")
+	}
+	return b.String()
+}
+
+// renderList writes the items that fit in the given number of lines, scrolled to keep the selected item visible,
+// and remembers where they are for mouse clicks.
+func (e *explorer) renderList(b *strings.Builder, n, lines int, item func(i int) string) {
+	lines = max(lines, 1)
+	start := max(0, min(e.selected-lines/2, n-lines))
+	end := min(n, start+lines)
+
+	e.firstRowLine = strings.Count(b.String(), "\n")
+	e.firstRowIndex = start
+	e.lastRowIndex = end - 1
+	for i := start; i < end; i++ {
+		text := truncate(item(i), e.width-2)
+		if i == e.selected {
+			b.WriteString(e.h.highlight("> "+text) + "\n")
+			continue
+		}
+		b.WriteString("  " + text + "\n")
+	}
+}
+
+// clickedRow returns the list row at the 1-based screen row of a mouse click, if that row was rendered.
+func (e *explorer) clickedRow(screenRow, n int) (int, bool) {
+	i := screenRow - 1 - e.firstRowLine + e.firstRowIndex
+	return i, screenRow-1 >= e.firstRowLine && i <= e.lastRowIndex && i < n
+}
+
+// describeQuery as indented lines.
+func describeQuery(spec honeycomb.QuerySpec, width int) string {
+	var calculations, filters []string
+	for _, c := range spec.Calculations {
+		calculations = append(calculations, c.Name())
+	}
+	if len(calculations) == 0 {
+		calculations = []string{"COUNT"}
+	}
+	for _, f := range spec.Filters {
+		filters = append(filters, strings.TrimSpace(fmt.Sprintf("%v %v %v", f.Column, f.Op, valueOrEmpty(f.Value))))
+	}
+
+	var b strings.Builder
+	for _, row := range [][2]string{
+		{"Calculate", strings.Join(calculations, ", ")},
+		{"Where", strings.Join(filters, " AND ")},
+		{"Group by", strings.Join(spec.Breakdowns, ", ")},
+		{"Time range", formatDuration(time.Duration(spec.TimeRange) * time.Second)},
+	} {
+		b.WriteString(truncate(fmt.Sprintf("  %-12v %v", row[0], row[1]), width) + "\n")
+	}
+	return b.String()
+}
+
+func valueOrEmpty(v any) string {
+	if v == nil {
+		return ""
+	}
+	return fmt.Sprint(v)
+}
+
+// formatTable with columns padded to the same width, two spaces apart.
+func formatTable(rows [][]string) string {
+	var widths []int
+	for _, cells := range rows {
+		for i, cell := range cells {
+			if i == len(widths) {
+				widths = append(widths, 0)
+			}
+			widths[i] = max(widths[i], utf8.RuneCountInString(cell))
+		}
+	}
+
+	var b strings.Builder
+	for _, cells := range rows {
+		for i, cell := range cells {
+			b.WriteString(cell)
+			if i < len(cells)-1 {
+				b.WriteString(strings.Repeat(" ", widths[i]-utf8.RuneCountInString(cell)+2))
+			}
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// truncate s to at most width runes.
+func truncate(s string, width int) string {
+	if utf8.RuneCountInString(s) <= width {
+		return s
+	}
+	runes := []rune(s)
+	if width < 1 {
+		return ""
+	}
+	return string(runes[:width-1]) + "…"
+}
+
+func dropLastRune(s string) string {
+	_, size := utf8.DecodeLastRuneInString(s)
+	return s[:len(s)-size]
+}
+
+// moveSelection up or down in a list of n items, staying within the list.
+func moveSelection(selected, n int, direction keyKind) int {
+	if direction == keyUp {
+		return max(0, selected-1)
+	}
+	return max(0, min(n-1, selected+1))
+}
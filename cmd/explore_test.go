@@ -0,0 +1,238 @@
+package cmd_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"maragu.dev/is"
+
+	"github.com/maragudk/honeycomb-cli/cmd"
+	"github.com/maragudk/honeycomb-cli/honeycomb"
+)
+
+func TestExploreCommand(t *testing.T) {
+	t.Run("builds a query with column completion, runs it, and drills down into a result", func(t *testing.T) {
+		var specs []honeycomb.QuerySpec
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch {
+			case r.Method == http.MethodGet && r.URL.Path == "/1/datasets":
+				_ = json.NewEncoder(w).Encode([]honeycomb.Dataset{{Name: "web", Slug: "web"}, {Name: "API", Slug: "api"}})
+
+			case r.Method == http.MethodGet && strings.HasPrefix(r.URL.Path, "/1/columns/"):
+				_ = json.NewEncoder(w).Encode([]honeycomb.Column{
+					{KeyName: "status_code", Type: "integer"},
+					{KeyName: "duration_ms", Type: "float"},
+					{KeyName: "service", Type: "string"},
+				})
+
+			case r.Method == http.MethodPost && r.URL.Path == "/1/queries/api":
+				var spec honeycomb.QuerySpec
+				_ = json.NewDecoder(r.Body).Decode(&spec)
+				specs = append(specs, spec)
+				_ = json.NewEncoder(w).Encode(honeycomb.QueryResponse{ID: "q1"})
+
+			case r.Method == http.MethodPost && r.URL.Path == "/1/query_results/api":
+				result := honeycomb.QueryResult{ID: "r1", Complete: true}
+				result.Data.Results = []map[string]any{
+					{"service": "checkout", "P99(duration_ms)": float64(250)},
+					{"service": "search", "P99(duration_ms)": float64(80)},
+				}
+				_ = json.NewEncoder(w).Encode(result)
+
+			default:
+				http.NotFound(w, r)
+			}
+		}))
+		defer server.Close()
+
+		keys := strings.Join([]string{
+			"ap\r",             // search for and open the api dataset
+			"by serv\t\r",      // complete the service column and add it as a breakdown
+			"calc P99:dur\t\r", // complete the duration_ms column in a calculation
+			"where status_code >= 500\r",
+			"\r",       // run the query
+			"\x1b[B\r", // select the second row and drill down
+			"quit\r",
+		}, "")
+
+		var out bytes.Buffer
+		root := cmd.NewRootCommand()
+		root.SetIn(strings.NewReader(keys))
+		root.SetOut(&out)
+		root.SetArgs([]string{"explore", "--api-key", "test", "--api-url", server.URL})
+
+		err := root.Execute()
+		is.NotError(t, err)
+
+		is.Equal(t, 2, len(specs))
+
+		first := specs[0]
+		is.Equal(t, 1, len(first.Calculations))
+		is.Equal(t, "P99", first.Calculations[0].Op)
+		is.Equal(t, "duration_ms", first.Calculations[0].Column)
+		is.EqualSlice(t, []string{"service"}, first.Breakdowns)
+		is.Equal(t, 1, len(first.Filters))
+		is.Equal(t, 7200, first.TimeRange)
+
+		second := specs[1]
+		is.Equal(t, 0, len(second.Breakdowns))
+		is.Equal(t, 2, len(second.Filters))
+		is.Equal(t, "service", second.Filters[1].Column)
+		is.Equal(t, "=", second.Filters[1].Op)
+		is.Equal(t, "search", second.Filters[1].Value.(string))
+
+		output := out.String()
+		is.True(t, contains(output, "checkout"))
+		is.True(t, contains(output, "Group by"))
+	})
+
+	t.Run("starts in the given dataset and undoes changes", func(t *testing.T) {
+		var specs []honeycomb.QuerySpec
+		var columnRequests []string
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch {
+			case r.Method == http.MethodGet && r.URL.Path == "/1/datasets":
+				_ = json.NewEncoder(w).Encode([]honeycomb.Dataset{{Name: "web", Slug: "web"}, {Name: "API", Slug: "api"}})
+
+			case r.Method == http.MethodGet && strings.HasPrefix(r.URL.Path, "/1/columns/"):
+				columnRequests = append(columnRequests, strings.TrimPrefix(r.URL.Path, "/1/columns/"))
+				_ = json.NewEncoder(w).Encode([]honeycomb.Column{
+					{KeyName: "status_code", Type: "integer"},
+					{KeyName: "duration_ms", Type: "float"},
+					{KeyName: "service", Type: "string"},
+				})
+
+			case r.Method == http.MethodPost && r.URL.Path == "/1/queries/api":
+				var spec honeycomb.QuerySpec
+				_ = json.NewDecoder(r.Body).Decode(&spec)
+				specs = append(specs, spec)
+				_ = json.NewEncoder(w).Encode(honeycomb.QueryResponse{ID: "q1"})
+
+			case r.Method == http.MethodPost && r.URL.Path == "/1/query_results/api":
+				result := honeycomb.QueryResult{ID: "r1", Complete: true}
+				result.Data.Results = []map[string]any{
+					{"service": "checkout", "P99(duration_ms)": float64(250)},
+					{"service": "search", "P99(duration_ms)": float64(80)},
+				}
+				_ = json.NewEncoder(w).Encode(result)
+
+			default:
+				http.NotFound(w, r)
+			}
+		}))
+		defer server.Close()
+
+		var out bytes.Buffer
+		root := cmd.NewRootCommand()
+		root.SetIn(strings.NewReader("by service\rtime 30m\rundo\rrun\r"))
+		root.SetOut(&out)
+		root.SetArgs([]string{"explore", "--dataset", "api", "--api-key", "test", "--api-url", server.URL})
+
+		err := root.Execute()
+		is.NotError(t, err)
+
+		is.EqualSlice(t, []string{"api"}, columnRequests)
+		is.Equal(t, 1, len(specs))
+		is.Equal(t, 7200, specs[0].TimeRange)
+		is.EqualSlice(t, []string{"service"}, specs[0].Breakdowns)
+		is.Equal(t, "COUNT", specs[0].Calculations[0].Op)
+	})
+
+	t.Run("opens a dataset with a mouse click", func(t *testing.T) {
+		var columnRequests []string
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch {
+			case r.Method == http.MethodGet && r.URL.Path == "/1/datasets":
+				_ = json.NewEncoder(w).Encode([]honeycomb.Dataset{{Name: "web", Slug: "web"}, {Name: "API", Slug: "api"}})
+
+			case r.Method == http.MethodGet && strings.HasPrefix(r.URL.Path, "/1/columns/"):
+				columnRequests = append(columnRequests, strings.TrimPrefix(r.URL.Path, "/1/columns/"))
+				_ = json.NewEncoder(w).Encode([]honeycomb.Column{
+					{KeyName: "status_code", Type: "integer"},
+					{KeyName: "duration_ms", Type: "float"},
+					{KeyName: "service", Type: "string"},
+				})
+
+			default:
+				http.NotFound(w, r)
+			}
+		}))
+		defer server.Close()
+
+		root := cmd.NewRootCommand()
+		// The dataset list starts on the fourth screen row, sorted by slug, so the fifth row is web
+		root.SetIn(strings.NewReader("\x1b[<0;3;5M"))
+		root.SetOut(&bytes.Buffer{})
+		root.SetArgs([]string{"explore", "--api-key", "test", "--api-url", server.URL})
+
+		err := root.Execute()
+		is.NotError(t, err)
+		is.EqualSlice(t, []string{"web"}, columnRequests)
+	})
+
+	t.Run("ignores clicks below the rendered part of a long list", func(t *testing.T) {
+		var columnRequests []string
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch {
+			case r.URL.Path == "/1/datasets":
+				var datasets []honeycomb.Dataset
+				for i := range 30 {
+					slug := fmt.Sprintf("dataset-%02d", i)
+					datasets = append(datasets, honeycomb.Dataset{Name: slug, Slug: slug})
+				}
+				_ = json.NewEncoder(w).Encode(datasets)
+			case strings.HasPrefix(r.URL.Path, "/1/columns/"):
+				columnRequests = append(columnRequests, strings.TrimPrefix(r.URL.Path, "/1/columns/"))
+				_, _ = w.Write([]byte(`[]`))
+			default:
+				http.NotFound(w, r)
+			}
+		}))
+		defer server.Close()
+
+		root := cmd.NewRootCommand()
+		// The 24-row screen has room for 19 datasets, so the last screen row isn't a dataset
+		root.SetIn(strings.NewReader("\x1b[<0;3;24M"))
+		root.SetOut(&bytes.Buffer{})
+		root.SetArgs([]string{"explore", "--api-key", "test", "--api-url", server.URL})
+
+		err := root.Execute()
+		is.NotError(t, err)
+		is.Equal(t, 0, len(columnRequests))
+	})
+
+	t.Run("shows an error for an unknown command", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch {
+			case r.Method == http.MethodGet && r.URL.Path == "/1/datasets":
+				_ = json.NewEncoder(w).Encode([]honeycomb.Dataset{{Name: "web", Slug: "web"}, {Name: "API", Slug: "api"}})
+
+			case r.Method == http.MethodGet && strings.HasPrefix(r.URL.Path, "/1/columns/"):
+				_ = json.NewEncoder(w).Encode([]honeycomb.Column{
+					{KeyName: "status_code", Type: "integer"},
+					{KeyName: "duration_ms", Type: "float"},
+					{KeyName: "service", Type: "string"},
+				})
+
+			default:
+				http.NotFound(w, r)
+			}
+		}))
+		defer server.Close()
+
+		var out bytes.Buffer
+		root := cmd.NewRootCommand()
+		root.SetIn(strings.NewReader("frobnicate\r"))
+		root.SetOut(&out)
+		root.SetArgs([]string{"explore", "--dataset", "api", "--api-key", "test", "--api-url", server.URL})
+
+		err := root.Execute()
+		is.NotError(t, err)
+		is.True(t, contains(out.String(), `Unknown command "frobnicate"`))
+	})
+}
@@ -0,0 +1,498 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/maragudk/honeycomb-cli/honeycomb"
+)
+
+// environmentSnapshot is the configuration of an environment as returned by the API, including IDs.
+type environmentSnapshot struct {
+	Recipients []honeycomb.Recipient
+	Boards     []boardSnapshot
+	Datasets   []datasetSnapshot
+}
+
+// boardSnapshot is a board with the query spec behind each of its queries, in the same order.
+type boardSnapshot struct {
+	Board honeycomb.Board
+	Specs []honeycomb.QuerySpec
+}
+
+// datasetSnapshot is a dataset and everything configured on it.
+type datasetSnapshot struct {
+	Dataset        honeycomb.Dataset
+	Definitions    *honeycomb.DatasetDefinitions
+	Columns        []honeycomb.Column
+	DerivedColumns []honeycomb.DerivedColumn
+	Triggers       []honeycomb.Trigger
+	SLOs           []honeycomb.SLO
+	// BurnAlerts by SLO ID.
+	BurnAlerts     map[string][]honeycomb.BurnAlert
+	MarkerSettings []honeycomb.MarkerSetting
+}
+
+// collectEnvironment fetches the configuration of the given datasets, or all datasets if none are given,
+// along with recipients and the boards whose queries only use those datasets.
+func collectEnvironment(ctx context.Context, c *honeycomb.Client, slugs []string) (*environmentSnapshot, error) {
+	datasets, err := c.ListDatasets(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("listing datasets: %w", err)
+	}
+
+	selected := map[string]bool{}
+	for _, d := range datasets {
+		if len(slugs) == 0 || slices.Contains(slugs, d.Slug) {
+			selected[d.Slug] = true
+		}
+	}
+	for _, slug := range slugs {
+		if !selected[slug] {
+			return nil, fmt.Errorf("dataset %q not found", slug)
+		}
+	}
+
+	var snap environmentSnapshot
+
+	for _, d := range datasets {
+		if !selected[d.Slug] {
+			continue
+		}
+		ds, err := collectDataset(ctx, c, d)
+		if err != nil {
+			return nil, fmt.Errorf("dataset %v: %w", d.Slug, err)
+		}
+		snap.Datasets = append(snap.Datasets, *ds)
+	}
+	sort.Slice(snap.Datasets, func(i, j int) bool { return snap.Datasets[i].Dataset.Slug < snap.Datasets[j].Dataset.Slug })
+
+	if snap.Recipients, err = c.ListRecipients(ctx); err != nil {
+		return nil, fmt.Errorf("listing recipients: %w", err)
+	}
+	sort.Slice(snap.Recipients, func(i, j int) bool {
+		a, b := snap.Recipients[i], snap.Recipients[j]
+		if a.Type != b.Type {
+			return a.Type < b.Type
+		}
+		return a.Target() < b.Target()
+	})
+
+	boards, err := c.ListBoards(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("listing boards: %w", err)
+	}
+	for _, b := range boards {
+		if !slices.ContainsFunc(b.Queries, func(q honeycomb.BoardQuery) bool { return selected[q.Dataset] }) {
+			continue
+		}
+		if slices.ContainsFunc(b.Queries, func(q honeycomb.BoardQuery) bool { return !selected[q.Dataset] }) {
+			continue
+		}
+
+		bs := boardSnapshot{Board: b}
+		for _, q := range b.Queries {
+			spec, err := c.GetQuery(ctx, q.Dataset, q.QueryID)
+			if err != nil {
+				return nil, fmt.Errorf("getting query %v for board %q: %w", q.QueryID, b.Name, err)
+			}
+			bs.Specs = append(bs.Specs, *spec)
+		}
+		snap.Boards = append(snap.Boards, bs)
+	}
+	sort.Slice(snap.Boards, func(i, j int) bool { return snap.Boards[i].Board.Name < snap.Boards[j].Board.Name })
+
+	return &snap, nil
+}
+
+func collectDataset(ctx context.Context, c *honeycomb.Client, d honeycomb.Dataset) (*datasetSnapshot, error) {
+	ds := datasetSnapshot{Dataset: d, BurnAlerts: map[string][]honeycomb.BurnAlert{}}
+	var err error
+
+	if ds.Definitions, err = c.GetDatasetDefinitions(ctx, d.Slug); err != nil {
+		return nil, fmt.Errorf("getting definitions: %w", err)
+	}
+
+	if ds.Columns, err = c.ListColumns(ctx, d.Slug); err != nil {
+		return nil, fmt.Errorf("listing columns: %w", err)
+	}
+	sort.Slice(ds.Columns, func(i, j int) bool { return ds.Columns[i].KeyName < ds.Columns[j].KeyName })
+
+	if ds.DerivedColumns, err = c.ListDerivedColumns(ctx, d.Slug); err != nil {
+		return nil, fmt.Errorf("listing derived columns: %w", err)
+	}
+	sort.Slice(ds.DerivedColumns, func(i, j int) bool { return ds.DerivedColumns[i].Alias < ds.DerivedColumns[j].Alias })
+
+	if ds.Triggers, err = c.ListTriggers(ctx, d.Slug); err != nil {
+		return nil, fmt.Errorf("listing triggers: %w", err)
+	}
+	for i, t := range ds.Triggers {
+		if t.Query == nil && t.QueryID != "" {
+			if ds.Triggers[i].Query, err = c.GetQuery(ctx, d.Slug, t.QueryID); err != nil {
+				return nil, fmt.Errorf("getting query for trigger %q: %w", t.Name, err)
+			}
+		}
+	}
+	sort.Slice(ds.Triggers, func(i, j int) bool { return ds.Triggers[i].Name < ds.Triggers[j].Name })
+
+	if ds.SLOs, err = c.ListSLOs(ctx, d.Slug); err != nil {
+		return nil, fmt.Errorf("listing SLOs: %w", err)
+	}
+	sort.Slice(ds.SLOs, func(i, j int) bool { return ds.SLOs[i].Name < ds.SLOs[j].Name })
+	for _, s := range ds.SLOs {
+		alerts, err := c.ListBurnAlerts(ctx, d.Slug, s.ID)
+		if err != nil {
+			return nil, fmt.Errorf("listing burn alerts for SLO %q: %w", s.Name, err)
+		}
+		ds.BurnAlerts[s.ID] = alerts
+	}
+
+	if ds.MarkerSettings, err = c.ListMarkerSettings(ctx, d.Slug); err != nil {
+		return nil, fmt.Errorf("listing marker settings: %w", err)
+	}
+	sort.Slice(ds.MarkerSettings, func(i, j int) bool { return ds.MarkerSettings[i].Type < ds.MarkerSettings[j].Type })
+
+	return &ds, nil
+}
+
+// environmentExport is the portable form of an [environmentSnapshot], without IDs and timestamps
+// except recipient IDs, which triggers and burn alerts refer to.
+type environmentExport struct {
+	Recipients []honeycomb.Recipient
+	Boards     []boardFile
+	Datasets   []datasetExport
+}
+
+type datasetExport struct {
+	Slug           string
+	Dataset        datasetFile
+	Columns        []honeycomb.CreateColumnRequest
+	DerivedColumns []honeycomb.DerivedColumn
+	Triggers       []honeycomb.Trigger
+	SLOs           []sloFile
+	MarkerSettings []honeycomb.MarkerSetting
+}
+
+// datasetFile is the YAML format for a dataset's settings and definitions.
+type datasetFile struct {
+	Name            string                        `json:"name"`
+	Description     string                        `json:"description,omitempty"`
+	ExpandJSONDepth int                           `json:"expand_json_depth,omitempty"`
+	DeleteProtected bool                          `json:"delete_protected"`
+	Definitions     *honeycomb.DatasetDefinitions `json:"definitions,omitempty"`
+}
+
+// sloFile is the YAML format for an SLO with its burn alerts.
+type sloFile struct {
+	honeycomb.SLO
+	BurnAlerts []honeycomb.BurnAlert `json:"burn_alerts,omitempty"`
+}
+
+// portable strips IDs and timestamps from the snapshot, and replaces recipient secrets with
+// placeholders for environment variables that import reads them from.
+func (s *environmentSnapshot) portable() environmentExport {
+	var e environmentExport
+
+	for _, r := range s.Recipients {
+		r.CreatedAt, r.UpdatedAt = "", ""
+		if r.Details.WebhookSecret != "" {
+			r.Details.WebhookSecret = "${" + recipientSecretEnvVar(r, "WEBHOOK_SECRET") + "}"
+		}
+		if r.Details.PagerDutyIntegrationKey != "" {
+			r.Details.PagerDutyIntegrationKey = "${" + recipientSecretEnvVar(r, "PAGERDUTY_INTEGRATION_KEY") + "}"
+		}
+		e.Recipients = append(e.Recipients, r)
+	}
+
+	for _, bs := range s.Boards {
+		bf := boardFile{
+			Name:         bs.Board.Name,
+			Description:  bs.Board.Description,
+			Style:        bs.Board.Style,
+			ColumnLayout: bs.Board.ColumnLayout,
+		}
+		for i, q := range bs.Board.Queries {
+			bf.Queries = append(bf.Queries, boardFileQuery{
+				Caption:    q.Caption,
+				QueryStyle: q.QueryStyle,
+				Dataset:    q.Dataset,
+				Query:      bs.Specs[i],
+			})
+		}
+		e.Boards = append(e.Boards, bf)
+	}
+
+	for _, ds := range s.Datasets {
+		de := datasetExport{
+			Slug: ds.Dataset.Slug,
+			Dataset: datasetFile{
+				Name:            ds.Dataset.Name,
+				Description:     ds.Dataset.Description,
+				ExpandJSONDepth: ds.Dataset.ExpandJSONDepth,
+				DeleteProtected: ds.Dataset.Settings.DeleteProtected,
+				Definitions:     ds.Definitions,
+			},
+		}
+
+		for _, col := range ds.Columns {
+			de.Columns = append(de.Columns, honeycomb.CreateColumnRequest{
+				KeyName:     col.KeyName,
+				Type:        col.Type,
+				Description: col.Description,
+				Hidden:      col.Hidden,
+			})
+		}
+
+		for _, dc := range ds.DerivedColumns {
+			dc.ID, dc.CreatedAt, dc.UpdatedAt = "", "", ""
+			de.DerivedColumns = append(de.DerivedColumns, dc)
+		}
+
+		for _, t := range ds.Triggers {
+			t.ID, t.QueryID, t.CreatedAt, t.UpdatedAt = "", "", "", ""
+			de.Triggers = append(de.Triggers, t)
+		}
+
+		for _, slo := range ds.SLOs {
+			sf := sloFile{SLO: slo}
+			sf.ID, sf.CreatedAt, sf.UpdatedAt = "", "", ""
+			for _, a := range ds.BurnAlerts[slo.ID] {
+				a.ID, a.SLO, a.CreatedAt, a.UpdatedAt = "", honeycomb.BurnAlertSLO{}, "", ""
+				sf.BurnAlerts = append(sf.BurnAlerts, a)
+			}
+			de.SLOs = append(de.SLOs, sf)
+		}
+
+		for _, ms := range ds.MarkerSettings {
+			ms.ID, ms.CreatedAt, ms.UpdatedAt = "", "", ""
+			de.MarkerSettings = append(de.MarkerSettings, ms)
+		}
+
+		e.Datasets = append(e.Datasets, de)
+	}
+
+	return e
+}
+
+var nonEnvVarRegexp = regexp.MustCompile(`[^A-Z0-9]+`)
+
+// recipientSecretEnvVar names the environment variable holding the secret of the given kind for a recipient,
+// such as HONEYCOMB_RECIPIENT_DEPLOYS_WEBHOOK_SECRET for the webhook named "deploys".
+func recipientSecretEnvVar(r honeycomb.Recipient, kind string) string {
+	target := strings.Trim(nonEnvVarRegexp.ReplaceAllString(strings.ToUpper(r.Target()), "_"), "_")
+	return "HONEYCOMB_RECIPIENT_" + target + "_" + kind
+}
+
+// exportFile is a file in an export directory and the value it holds.
+type exportFile struct {
+	path string
+	v    any
+}
+
+// files in the export directory layout:
+//
+//	recipients.yaml
+//	boards.yaml
+//	export.yaml (written by [writeExport])
+//	datasets/<slug>/dataset.yaml
+//	datasets/<slug>/columns.yaml
+//	datasets/<slug>/derived_columns.yaml
+//	datasets/<slug>/triggers.yaml
+//	datasets/<slug>/slos.yaml
+//	datasets/<slug>/marker_settings.yaml
+func (e *environmentExport) files() []exportFile {
+	files := []exportFile{
+		{"recipients.yaml", &e.Recipients},
+		{"boards.yaml", &e.Boards},
+	}
+	for i := range e.Datasets {
+		de := &e.Datasets[i]
+		dir := filepath.Join("datasets", de.Slug)
+		files = append(files,
+			exportFile{filepath.Join(dir, "dataset.yaml"), &de.Dataset},
+			exportFile{filepath.Join(dir, "columns.yaml"), &de.Columns},
+			exportFile{filepath.Join(dir, "derived_columns.yaml"), &de.DerivedColumns},
+			exportFile{filepath.Join(dir, "triggers.yaml"), &de.Triggers},
+			exportFile{filepath.Join(dir, "slos.yaml"), &de.SLOs},
+			exportFile{filepath.Join(dir, "marker_settings.yaml"), &de.MarkerSettings},
+		)
+	}
+	return files
+}
+
+// exportManifestFile lists the datasets in an export directory, so a later export knows which
+// dataset directories it may remove.
+const exportManifestFile = "export.yaml"
+
+type exportManifest struct {
+	Datasets []string `json:"datasets"`
+}
+
+// writeExport to the directory, creating it if needed. Unless partial is set because only some datasets
+// were exported, the directories of datasets that an earlier export wrote but that aren't in e are removed.
+func writeExport(dir string, e environmentExport, partial bool) error {
+	var m exportManifest
+	data, err := os.ReadFile(filepath.Join(dir, exportManifestFile))
+	switch {
+	case err == nil:
+		if err := decodeYAML(data, &m); err != nil {
+			return fmt.Errorf("parsing %v: %w", exportManifestFile, err)
+		}
+	case os.IsNotExist(err):
+		if _, err := os.Stat(filepath.Join(dir, "datasets")); err == nil {
+			return fmt.Errorf("%v has a datasets directory but no %v, so it doesn't look like an export", dir, exportManifestFile)
+		}
+	default:
+		return err
+	}
+
+	var slugs []string
+	for _, de := range e.Datasets {
+		slugs = append(slugs, de.Slug)
+	}
+	for _, slug := range m.Datasets {
+		if slices.Contains(slugs, slug) {
+			continue
+		}
+		if partial {
+			slugs = append(slugs, slug)
+			continue
+		}
+		// Remove datasets from an earlier export, so deleted datasets aren't brought back by import
+		if !filepath.IsLocal(slug) || filepath.Base(slug) != slug {
+			continue
+		}
+		if err := os.RemoveAll(filepath.Join(dir, "datasets", slug)); err != nil {
+			return err
+		}
+	}
+	sort.Strings(slugs)
+
+	files := append(e.files(), exportFile{exportManifestFile, &exportManifest{Datasets: slugs}})
+	for _, f := range files {
+		data, err := encodeYAML(f.v)
+		if err != nil {
+			return err
+		}
+		path := filepath.Join(dir, f.path)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return err
+		}
+		if err := os.WriteFile(path, data, 0600); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// readExport from a directory written by [writeExport]. Missing files are treated as empty.
+func readExport(dir string) (*environmentExport, error) {
+	var e environmentExport
+
+	entries, err := os.ReadDir(filepath.Join(dir, "datasets"))
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	for _, entry := range entries {
+		if entry.IsDir() {
+			e.Datasets = append(e.Datasets, datasetExport{Slug: entry.Name()})
+		}
+	}
+
+	for _, f := range e.files() {
+		data, err := os.ReadFile(filepath.Join(dir, f.path))
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		if err := decodeYAML(data, f.v); err != nil {
+			return nil, fmt.Errorf("parsing %v: %w", f.path, err)
+		}
+	}
+
+	for _, de := range e.Datasets {
+		if de.Dataset.Name == "" {
+			return nil, fmt.Errorf("dataset %v has no name in %v", de.Slug, filepath.Join("datasets", de.Slug, "dataset.yaml"))
+		}
+	}
+
+	return &e, nil
+}
+
+func newExportCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "export",
+		Short: "Export the environment's configuration to a directory of YAML files",
+		Long: `Export the environment's configuration to a directory of YAML files: datasets and
+their settings and definitions, column metadata, derived columns, triggers, SLOs and their
+burn alerts, marker settings, recipients, and boards.
+
+IDs and timestamps are left out and lists are sorted, so exports diff cleanly.
+export.yaml lists the exported datasets. Exporting all datasets again removes the directories
+of listed datasets that no longer exist, and export refuses to write to a directory that has
+a datasets directory but no export.yaml.
+Webhook secrets and PagerDuty integration keys are replaced with placeholders like
+${HONEYCOMB_RECIPIENT_DEPLOYS_WEBHOOK_SECRET}, which import reads from the environment.
+Use the import command to recreate the configuration in another environment.
+
+With --format terraform, resources.tf and imports.tf are written instead, with resource
+blocks for the Honeycomb Terraform provider and import blocks with the IDs of the existing
+columns, derived columns, triggers, SLOs, burn alerts, and boards. Queries can't be imported,
+so Terraform creates new ones and points triggers and boards at them on the first apply.
+
+Examples:
+  honeycomb-cli export --output ./honeycomb-config
+  honeycomb-cli export --format terraform --output ./terraform --dataset api`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c := newClient(cmd)
+			dir, _ := cmd.Flags().GetString("output")
+			datasets, _ := cmd.Flags().GetStringSlice("dataset")
+			format, _ := cmd.Flags().GetString("format")
+
+			if format != "yaml" && format != "terraform" {
+				return fmt.Errorf("invalid format %q (must be yaml or terraform)", format)
+			}
+
+			snap, err := collectEnvironment(cmd.Context(), c, datasets)
+			if err != nil {
+				return err
+			}
+
+			if format == "terraform" {
+				t, err := newTerraformConfig(snap)
+				if err != nil {
+					return err
+				}
+				if err := writeTerraform(dir, t); err != nil {
+					return err
+				}
+				fmt.Fprintf(cmd.OutOrStdout(), "Wrote %v resource(s) and %v import block(s) to %v\n",
+					len(t.Resources), len(t.Imports), dir)
+				return nil
+			}
+
+			if err := writeExport(dir, snap.portable(), len(datasets) > 0); err != nil {
+				return err
+			}
+
+			fmt.Fprintf(cmd.OutOrStdout(), "Exported %v dataset(s), %v board(s), and %v recipient(s) to %v\n",
+				len(snap.Datasets), len(snap.Boards), len(snap.Recipients), dir)
+			return nil
+		},
+	}
+	cmd.Flags().StringP("output", "o", "", "Directory to write the export to (required)")
+	_ = cmd.MarkFlagRequired("output")
+	cmd.Flags().StringSlice("dataset", nil, "Only export these datasets (default all)")
+	cmd.Flags().String("format", "yaml", "Output format: yaml or terraform")
+	return cmd
+}
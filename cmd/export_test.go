@@ -0,0 +1,221 @@
+package cmd_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"maragu.dev/is"
+
+	"github.com/maragudk/honeycomb-cli/cmd"
+	"github.com/maragudk/honeycomb-cli/honeycomb"
+)
+
+func TestExportCommand(t *testing.T) {
+	t.Run("writes the environment's configuration to YAML files without IDs", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch r.URL.Path {
+			case "/1/datasets":
+				_ = json.NewEncoder(w).Encode([]honeycomb.Dataset{{Name: "API", Slug: "api"}})
+			case "/1/dataset_definitions/api":
+				_ = json.NewEncoder(w).Encode(honeycomb.DatasetDefinitions{})
+			case "/1/columns/api":
+				_ = json.NewEncoder(w).Encode([]honeycomb.Column{{ID: "c1", KeyName: "duration_ms", Type: "float", Description: "Request duration"}})
+			case "/1/derived_columns/api":
+				_ = json.NewEncoder(w).Encode([]honeycomb.DerivedColumn{{ID: "d1", Alias: "is_error", Expression: "GTE($status, 500)"}})
+			case "/1/triggers/api":
+				_ = json.NewEncoder(w).Encode([]honeycomb.Trigger{{
+					ID:         "t1",
+					Name:       "High error rate",
+					QueryID:    "q1",
+					Recipients: []honeycomb.NotificationRecipient{{ID: "r1"}},
+				}})
+			case "/1/queries/api/q1":
+				_ = json.NewEncoder(w).Encode(honeycomb.QuerySpec{Calculations: []honeycomb.Calculation{{Op: "COUNT"}}})
+			case "/1/slos/api":
+				_ = json.NewEncoder(w).Encode([]honeycomb.SLO{{ID: "s1", Name: "Availability", TargetPerMillion: 999000}})
+			case "/1/burn_alerts/api":
+				is.Equal(t, "s1", r.URL.Query().Get("slo_id"))
+				_ = json.NewEncoder(w).Encode([]honeycomb.BurnAlert{{ID: "ba1", AlertType: "exhaustion_time", ExhaustionMinutes: 60}})
+			case "/1/marker_settings/api":
+				_ = json.NewEncoder(w).Encode([]honeycomb.MarkerSetting{{ID: "m1", Type: "deploy", Color: "#00ff00"}})
+			case "/1/recipients":
+				_ = json.NewEncoder(w).Encode([]honeycomb.Recipient{
+					{ID: "r1", Type: "email", Details: honeycomb.RecipientDetails{EmailAddress: "oncall@example.com"}},
+					{ID: "r2", Type: "webhook", Details: honeycomb.RecipientDetails{WebhookName: "Deploys", WebhookURL: "https://example.com/hook", WebhookSecret: "s3cret"}},
+				})
+			case "/1/boards":
+				_ = json.NewEncoder(w).Encode([]honeycomb.Board{
+					{ID: "b1", Name: "API overview", Queries: []honeycomb.BoardQuery{{Dataset: "api", QueryID: "q1"}}},
+					{ID: "b2", Name: "Other", Queries: []honeycomb.BoardQuery{{Dataset: "other", QueryID: "q2"}}},
+				})
+			default:
+				t.Errorf("unexpected path %v", r.URL.Path)
+				http.Error(w, `{"error": "not found"}`, http.StatusNotFound)
+			}
+		}))
+		defer server.Close()
+
+		dir := t.TempDir()
+
+		var buf bytes.Buffer
+		root := cmd.NewRootCommand()
+		root.SetOut(&buf)
+		root.SetArgs([]string{"export", "--output", dir, "--api-key", "test", "--api-url", server.URL})
+
+		err := root.Execute()
+		is.NotError(t, err)
+		is.True(t, contains(buf.String(), "Exported 1 dataset(s), 1 board(s), and 2 recipient(s)"))
+
+		triggers, err := os.ReadFile(filepath.Join(dir, "datasets", "api", "triggers.yaml"))
+		is.NotError(t, err)
+		is.True(t, contains(string(triggers), "High error rate"))
+		is.True(t, contains(string(triggers), "COUNT"))
+		is.True(t, contains(string(triggers), "id: r1"))
+		is.True(t, !contains(string(triggers), "t1"))
+
+		slos, err := os.ReadFile(filepath.Join(dir, "datasets", "api", "slos.yaml"))
+		is.NotError(t, err)
+		is.True(t, contains(string(slos), "exhaustion_minutes: 60"))
+		is.True(t, !contains(string(slos), "s1"))
+
+		boards, err := os.ReadFile(filepath.Join(dir, "boards.yaml"))
+		is.NotError(t, err)
+		is.True(t, contains(string(boards), "API overview"))
+		is.True(t, !contains(string(boards), "Other"))
+
+		recipients, err := os.ReadFile(filepath.Join(dir, "recipients.yaml"))
+		is.NotError(t, err)
+		is.True(t, !contains(string(recipients), "s3cret"))
+		is.True(t, contains(string(recipients), "webhook_secret: ${HONEYCOMB_RECIPIENT_DEPLOYS_WEBHOOK_SECRET}"))
+
+		for _, name := range []string{"recipients.yaml", "datasets/api/dataset.yaml", "datasets/api/columns.yaml",
+			"datasets/api/derived_columns.yaml", "datasets/api/marker_settings.yaml"} {
+			_, err := os.Stat(filepath.Join(dir, name))
+			is.NotError(t, err)
+		}
+	})
+
+	t.Run("removes datasets from an earlier export", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch r.URL.Path {
+			case "/1/datasets":
+				_ = json.NewEncoder(w).Encode([]honeycomb.Dataset{{Name: "API", Slug: "api"}})
+			case "/1/dataset_definitions/api":
+				_ = json.NewEncoder(w).Encode(honeycomb.DatasetDefinitions{})
+			default:
+				_, _ = w.Write([]byte(`[]`))
+			}
+		}))
+		defer server.Close()
+
+		dir := t.TempDir()
+		stale := filepath.Join(dir, "datasets", "deleted", "dataset.yaml")
+		is.NotError(t, os.MkdirAll(filepath.Dir(stale), 0755))
+		is.NotError(t, os.WriteFile(stale, []byte("name: Deleted\n"), 0600))
+		is.NotError(t, os.WriteFile(filepath.Join(dir, "export.yaml"), []byte("datasets:\n  - api\n  - deleted\n"), 0600))
+		unlisted := filepath.Join(dir, "datasets", "notes")
+		is.NotError(t, os.MkdirAll(unlisted, 0755))
+
+		root := cmd.NewRootCommand()
+		root.SetOut(&bytes.Buffer{})
+		root.SetArgs([]string{"export", "--output", dir, "--api-key", "test", "--api-url", server.URL})
+
+		err := root.Execute()
+		is.NotError(t, err)
+
+		_, err = os.Stat(filepath.Join(dir, "datasets", "deleted"))
+		is.True(t, os.IsNotExist(err))
+		_, err = os.Stat(filepath.Join(dir, "datasets", "api", "dataset.yaml"))
+		is.NotError(t, err)
+		_, err = os.Stat(unlisted)
+		is.NotError(t, err)
+
+		manifest, err := os.ReadFile(filepath.Join(dir, "export.yaml"))
+		is.NotError(t, err)
+		is.Equal(t, "datasets:\n  - api\n", string(manifest))
+	})
+
+	t.Run("keeps other datasets when only some are exported", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch r.URL.Path {
+			case "/1/datasets":
+				_ = json.NewEncoder(w).Encode([]honeycomb.Dataset{{Name: "API", Slug: "api"}, {Name: "Web", Slug: "web"}})
+			case "/1/dataset_definitions/api":
+				_ = json.NewEncoder(w).Encode(honeycomb.DatasetDefinitions{})
+			default:
+				_, _ = w.Write([]byte(`[]`))
+			}
+		}))
+		defer server.Close()
+
+		dir := t.TempDir()
+		is.NotError(t, os.MkdirAll(filepath.Join(dir, "datasets", "web"), 0755))
+		is.NotError(t, os.WriteFile(filepath.Join(dir, "export.yaml"), []byte("datasets:\n  - web\n"), 0600))
+
+		root := cmd.NewRootCommand()
+		root.SetOut(&bytes.Buffer{})
+		root.SetArgs([]string{"export", "--output", dir, "--dataset", "api", "--api-key", "test", "--api-url", server.URL})
+
+		err := root.Execute()
+		is.NotError(t, err)
+
+		_, err = os.Stat(filepath.Join(dir, "datasets", "web"))
+		is.NotError(t, err)
+
+		manifest, err := os.ReadFile(filepath.Join(dir, "export.yaml"))
+		is.NotError(t, err)
+		is.Equal(t, "datasets:\n  - api\n  - web\n", string(manifest))
+	})
+
+	t.Run("refuses to write to a directory with datasets that isn't an export", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch r.URL.Path {
+			case "/1/datasets":
+				_ = json.NewEncoder(w).Encode([]honeycomb.Dataset{{Name: "API", Slug: "api"}})
+			case "/1/dataset_definitions/api":
+				_ = json.NewEncoder(w).Encode(honeycomb.DatasetDefinitions{})
+			default:
+				_, _ = w.Write([]byte(`[]`))
+			}
+		}))
+		defer server.Close()
+
+		dir := t.TempDir()
+		own := filepath.Join(dir, "datasets", "training.csv")
+		is.NotError(t, os.MkdirAll(filepath.Dir(own), 0755))
+		is.NotError(t, os.WriteFile(own, []byte("a,b\n"), 0600))
+
+		root := cmd.NewRootCommand()
+		root.SetOut(&bytes.Buffer{})
+		root.SetErr(&bytes.Buffer{})
+		root.SetArgs([]string{"export", "--output", dir, "--api-key", "test", "--api-url", server.URL})
+
+		err := root.Execute()
+		is.True(t, err != nil)
+		is.True(t, contains(err.Error(), "doesn't look like an export"))
+
+		_, err = os.Stat(own)
+		is.NotError(t, err)
+	})
+
+	t.Run("errors on an unknown dataset", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_ = json.NewEncoder(w).Encode([]honeycomb.Dataset{{Name: "API", Slug: "api"}})
+		}))
+		defer server.Close()
+
+		root := cmd.NewRootCommand()
+		root.SetOut(&bytes.Buffer{})
+		root.SetErr(&bytes.Buffer{})
+		root.SetArgs([]string{"export", "--output", t.TempDir(), "--dataset", "nope", "--api-key", "test", "--api-url", server.URL})
+
+		err := root.Execute()
+		is.True(t, err != nil)
+		is.True(t, contains(err.Error(), `dataset "nope" not found`))
+	})
+}
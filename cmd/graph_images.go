@@ -0,0 +1,129 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/maragudk/honeycomb-cli/honeycomb"
+)
+
+// addGraphImageFlags adds the flags read by [saveGraphImageFromFlags].
+func addGraphImageFlags(cmd *cobra.Command) {
+	cmd.Flags().String("graph-image", "", "Save the graph image of the result to this PNG file")
+	cmd.Flags().Int("graph-width", 0, "Width of the graph image in pixels (default is Honeycomb's)")
+	cmd.Flags().Int("graph-height", 0, "Height of the graph image in pixels (default is Honeycomb's)")
+}
+
+// saveGraphImageFromFlags saves the graph image of the result to the file given with --graph-image, if any.
+func saveGraphImageFromFlags(cmd *cobra.Command, c *honeycomb.Client, result *honeycomb.QueryResult) error {
+	path, _ := cmd.Flags().GetString("graph-image")
+	if path == "" {
+		return nil
+	}
+	width, _ := cmd.Flags().GetInt("graph-width")
+	height, _ := cmd.Flags().GetInt("graph-height")
+
+	if err := saveGraphImage(cmd, c, result, path, honeycomb.GraphImageOptions{Width: width, Height: height}); err != nil {
+		return err
+	}
+	fmt.Fprintf(cmd.ErrOrStderr(), "Saved graph image to %v\n", path)
+	return nil
+}
+
+// saveGraphImage of the result to the file at path. The file is removed again if the download fails.
+func saveGraphImage(cmd *cobra.Command, c *honeycomb.Client, result *honeycomb.QueryResult, path string,
+	opts honeycomb.GraphImageOptions) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := c.DownloadGraphImage(cmd.Context(), result, f, opts); err != nil {
+		_ = f.Close()
+		_ = os.Remove(path)
+		return err
+	}
+	return f.Close()
+}
+
+var nonFileNameRegexp = regexp.MustCompile(`[^a-z0-9]+`)
+
+func newBoardsGraphImagesCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "graph-images <id>",
+		Short: "Save the graph image of every panel on a board",
+		Long: `Run the query behind every panel on a board, and save each result's graph image as a PNG file
+named after the panel number and caption, like "01-error-rate.png".
+Panels that fail are reported, and don't stop the other panels from being saved.
+
+Example:
+  honeycomb-cli boards graph-images abc123 --dir incident-42 --width 1200 --height 600`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c := newClient(cmd)
+			dir, _ := cmd.Flags().GetString("dir")
+			width, _ := cmd.Flags().GetInt("width")
+			height, _ := cmd.Flags().GetInt("height")
+			opts := honeycomb.GraphImageOptions{Width: width, Height: height}
+
+			board, err := c.GetBoard(cmd.Context(), args[0])
+			if err != nil {
+				return err
+			}
+
+			if err := os.MkdirAll(dir, 0o755); err != nil {
+				return err
+			}
+
+			var failed, saved int
+			for i, q := range board.Queries {
+				if q.QueryID == "" {
+					continue
+				}
+				dataset := q.Dataset
+				if dataset == "" {
+					dataset = "__all__"
+				}
+
+				name := fmt.Sprintf("%02d", i+1)
+				if slug := strings.Trim(nonFileNameRegexp.ReplaceAllString(strings.ToLower(q.Caption), "-"), "-"); slug != "" {
+					name += "-" + slug
+				}
+				path := filepath.Join(dir, name+".png")
+
+				spin := newSpinner(cmd.ErrOrStderr())
+				result, err := c.ExecuteQuery(cmd.Context(), dataset, q.QueryID, runQueryOptions(cmd, spin.progress))
+				spin.stop()
+				if err == nil {
+					err = saveGraphImage(cmd, c, result, path, opts)
+				}
+				if err != nil {
+					if cmd.Context().Err() != nil {
+						return err
+					}
+					failed++
+					fmt.Fprintf(cmd.ErrOrStderr(), "Error saving panel %v: %v\n", i+1, withResumeHint(err))
+					continue
+				}
+				saved++
+				fmt.Fprintf(cmd.OutOrStdout(), "Saved panel %v to %v\n", i+1, path)
+			}
+
+			if failed > 0 {
+				return fmt.Errorf("saving graph images failed for %v of %v panels", failed, failed+saved)
+			}
+			if saved == 0 {
+				fmt.Fprintln(cmd.OutOrStdout(), "No panels with queries.")
+			}
+			return nil
+		},
+	}
+	cmd.Flags().String("dir", ".", "Directory to save the images in (created if it doesn't exist)")
+	cmd.Flags().Int("width", 0, "Width of the graph images in pixels (default is Honeycomb's)")
+	cmd.Flags().Int("height", 0, "Height of the graph images in pixels (default is Honeycomb's)")
+	return cmd
+}
@@ -0,0 +1,253 @@
+package cmd_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"maragu.dev/is"
+
+	"github.com/maragudk/honeycomb-cli/cmd"
+	"github.com/maragudk/honeycomb-cli/honeycomb"
+)
+
+func TestQueryCommand_GraphImage(t *testing.T) {
+	t.Run("saves the graph image of the result", func(t *testing.T) {
+		var server *httptest.Server
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch {
+			case r.Method == http.MethodPost && r.URL.Path == "/1/queries/requests":
+				_ = json.NewEncoder(w).Encode(honeycomb.QueryResponse{ID: "q1"})
+
+			case r.Method == http.MethodPost && r.URL.Path == "/1/query_results/requests":
+				var req honeycomb.QueryResultRequest
+				_ = json.NewDecoder(r.Body).Decode(&req)
+				_ = json.NewEncoder(w).Encode(honeycomb.QueryResult{ID: "r" + req.QueryID[1:]})
+
+			case r.Method == http.MethodGet && r.URL.Path == "/1/query_results/requests/r1":
+				id := filepath.Base(r.URL.Path)
+				result := honeycomb.QueryResult{ID: id, Complete: true}
+				result.Data.Results = []map[string]any{{"COUNT": float64(42)}}
+				result.Links.GraphURL = server.URL + "/graphs/" + id + ".png"
+				_ = json.NewEncoder(w).Encode(result)
+
+			case r.URL.Path == "/graphs/r1.png":
+				w.Header().Set("Content-Type", "image/png")
+				_, _ = w.Write([]byte(filepath.Base(r.URL.Path) + " " + r.URL.Query().Get("width")))
+
+			default:
+				http.NotFound(w, r)
+			}
+		}))
+		defer server.Close()
+
+		path := filepath.Join(t.TempDir(), "graph.png")
+
+		var out, errOut bytes.Buffer
+		root := cmd.NewRootCommand()
+		root.SetOut(&out)
+		root.SetErr(&errOut)
+		root.SetArgs([]string{"query", "--dataset", "requests", "--graph-image", path, "--graph-width", "1200",
+			"--api-key", "test", "--api-url", server.URL})
+
+		err := root.Execute()
+		is.NotError(t, err)
+
+		data, err := os.ReadFile(path)
+		is.NotError(t, err)
+		is.Equal(t, "r1.png 1200", string(data))
+		is.True(t, contains(out.String(), "42"))
+		is.True(t, contains(errOut.String(), "Saved graph image to "+path))
+	})
+
+	t.Run("saves the graph image of a fetched result", func(t *testing.T) {
+		var server *httptest.Server
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch {
+			case r.Method == http.MethodGet && r.URL.Path == "/1/query_results/requests/r2":
+				id := filepath.Base(r.URL.Path)
+				result := honeycomb.QueryResult{ID: id, Complete: true}
+				result.Data.Results = []map[string]any{{"COUNT": float64(42)}}
+				result.Links.GraphURL = server.URL + "/graphs/" + id + ".png"
+				_ = json.NewEncoder(w).Encode(result)
+
+			case r.URL.Path == "/graphs/r2.png":
+				w.Header().Set("Content-Type", "image/png")
+				_, _ = w.Write([]byte(filepath.Base(r.URL.Path) + " " + r.URL.Query().Get("width")))
+
+			default:
+				http.NotFound(w, r)
+			}
+		}))
+		defer server.Close()
+
+		path := filepath.Join(t.TempDir(), "graph.png")
+
+		root := cmd.NewRootCommand()
+		root.SetOut(&bytes.Buffer{})
+		root.SetErr(&bytes.Buffer{})
+		root.SetArgs([]string{"query", "result", "r2", "--dataset", "requests", "--graph-image", path,
+			"--api-key", "test", "--api-url", server.URL})
+
+		err := root.Execute()
+		is.NotError(t, err)
+
+		data, err := os.ReadFile(path)
+		is.NotError(t, err)
+		is.Equal(t, "r2.png ", string(data))
+	})
+
+	t.Run("doesn't panic without any datasets to query", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			is.Equal(t, "/1/datasets", r.URL.Path)
+			_ = json.NewEncoder(w).Encode([]honeycomb.Dataset{})
+		}))
+		defer server.Close()
+
+		for _, flags := range [][]string{{"--dataset", ""}, {"--all-datasets"}} {
+			var buf bytes.Buffer
+			root := cmd.NewRootCommand()
+			root.SetOut(&buf)
+			root.SetErr(&bytes.Buffer{})
+			root.SetArgs(append([]string{"query", "--api-key", "test", "--api-url", server.URL}, flags...))
+
+			err := root.Execute()
+			is.NotError(t, err)
+			is.Equal(t, "No results.\n", buf.String())
+		}
+	})
+
+	t.Run("errors with several datasets", func(t *testing.T) {
+		root := cmd.NewRootCommand()
+		root.SetOut(&bytes.Buffer{})
+		root.SetErr(&bytes.Buffer{})
+		root.SetArgs([]string{"query", "--dataset", "api,web", "--graph-image", "graph.png",
+			"--api-key", "test", "--api-url", "http://localhost:0"})
+
+		err := root.Execute()
+		is.True(t, err != nil)
+		is.Equal(t, "--graph-image needs a single dataset", err.Error())
+	})
+}
+
+func TestBoardsGraphImagesCommand(t *testing.T) {
+	t.Run("saves the graph image of every panel", func(t *testing.T) {
+		var server *httptest.Server
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch {
+			case r.URL.Path == "/1/boards/b1":
+				_ = json.NewEncoder(w).Encode(honeycomb.Board{ID: "b1", Name: "Service Health", Queries: []honeycomb.BoardQuery{
+					{Caption: "Error Rate", Dataset: "requests", QueryID: "q1"},
+					{Caption: "Latency (P99)", Dataset: "requests", QueryID: "q2"},
+				}})
+
+			case r.Method == http.MethodPost && r.URL.Path == "/1/queries/requests":
+				_ = json.NewEncoder(w).Encode(honeycomb.QueryResponse{ID: "q1"})
+
+			case r.Method == http.MethodPost && r.URL.Path == "/1/query_results/requests":
+				var req honeycomb.QueryResultRequest
+				_ = json.NewDecoder(r.Body).Decode(&req)
+				_ = json.NewEncoder(w).Encode(honeycomb.QueryResult{ID: "r" + req.QueryID[1:]})
+
+			case r.Method == http.MethodGet && (r.URL.Path == "/1/query_results/requests/r1" || r.URL.Path == "/1/query_results/requests/r2"):
+				id := filepath.Base(r.URL.Path)
+				result := honeycomb.QueryResult{ID: id, Complete: true}
+				result.Data.Results = []map[string]any{{"COUNT": float64(42)}}
+				result.Links.GraphURL = server.URL + "/graphs/" + id + ".png"
+				_ = json.NewEncoder(w).Encode(result)
+
+			case r.URL.Path == "/graphs/r1.png" || r.URL.Path == "/graphs/r2.png":
+				w.Header().Set("Content-Type", "image/png")
+				_, _ = w.Write([]byte(filepath.Base(r.URL.Path) + " " + r.URL.Query().Get("width")))
+
+			default:
+				http.NotFound(w, r)
+			}
+		}))
+		defer server.Close()
+
+		dir := filepath.Join(t.TempDir(), "incident")
+
+		var buf bytes.Buffer
+		root := cmd.NewRootCommand()
+		root.SetOut(&buf)
+		root.SetArgs([]string{"boards", "graph-images", "b1", "--dir", dir, "--width", "800",
+			"--api-key", "test", "--api-url", server.URL})
+
+		err := root.Execute()
+		is.NotError(t, err)
+
+		data, err := os.ReadFile(filepath.Join(dir, "01-error-rate.png"))
+		is.NotError(t, err)
+		is.Equal(t, "r1.png 800", string(data))
+
+		data, err = os.ReadFile(filepath.Join(dir, "02-latency-p99.png"))
+		is.NotError(t, err)
+		is.Equal(t, "r2.png 800", string(data))
+
+		is.True(t, contains(buf.String(), "Saved panel 2 to "+filepath.Join(dir, "02-latency-p99.png")))
+	})
+
+	t.Run("saves the other panels when one fails", func(t *testing.T) {
+		var server *httptest.Server
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch {
+			case r.URL.Path == "/1/boards/b1":
+				_ = json.NewEncoder(w).Encode(honeycomb.Board{ID: "b1", Name: "Service Health", Queries: []honeycomb.BoardQuery{
+					{Caption: "Error Rate", Dataset: "requests", QueryID: "q1"},
+					{Caption: "Latency (P99)", Dataset: "requests", QueryID: "q2"},
+				}})
+
+			case r.Method == http.MethodPost && r.URL.Path == "/1/queries/requests":
+				_ = json.NewEncoder(w).Encode(honeycomb.QueryResponse{ID: "q1"})
+
+			case r.Method == http.MethodPost && r.URL.Path == "/1/query_results/requests":
+				var req honeycomb.QueryResultRequest
+				_ = json.NewDecoder(r.Body).Decode(&req)
+				if req.QueryID == "q2" {
+					http.Error(w, `{"error": "query not found"}`, http.StatusNotFound)
+					return
+				}
+				_ = json.NewEncoder(w).Encode(honeycomb.QueryResult{ID: "r" + req.QueryID[1:]})
+
+			case r.Method == http.MethodGet && r.URL.Path == "/1/query_results/requests/r1":
+				id := filepath.Base(r.URL.Path)
+				result := honeycomb.QueryResult{ID: id, Complete: true}
+				result.Data.Results = []map[string]any{{"COUNT": float64(42)}}
+				result.Links.GraphURL = server.URL + "/graphs/" + id + ".png"
+				_ = json.NewEncoder(w).Encode(result)
+
+			case r.URL.Path == "/graphs/r1.png":
+				w.Header().Set("Content-Type", "image/png")
+				_, _ = w.Write([]byte(filepath.Base(r.URL.Path) + " " + r.URL.Query().Get("width")))
+
+			default:
+				http.NotFound(w, r)
+			}
+		}))
+		defer server.Close()
+
+		dir := t.TempDir()
+
+		var out, errOut bytes.Buffer
+		root := cmd.NewRootCommand()
+		root.SetOut(&out)
+		root.SetErr(&errOut)
+		root.SetArgs([]string{"boards", "graph-images", "b1", "--dir", dir,
+			"--api-key", "test", "--api-url", server.URL})
+
+		err := root.Execute()
+		is.True(t, err != nil)
+		is.Equal(t, "saving graph images failed for 1 of 2 panels", err.Error())
+
+		_, err = os.Stat(filepath.Join(dir, "01-error-rate.png"))
+		is.NotError(t, err)
+		_, err = os.Stat(filepath.Join(dir, "02-latency-p99.png"))
+		is.True(t, os.IsNotExist(err))
+		is.True(t, contains(errOut.String(), "Error saving panel 2"))
+	})
+}
@@ -0,0 +1,388 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/maragudk/honeycomb-cli/honeycomb"
+)
+
+func newImportCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "import",
+		Short: "Recreate configuration from an export directory in this environment",
+		Long: `Recreate configuration from a directory written by the export command in the
+environment of the current API key.
+
+Datasets, columns, and derived columns that already exist are updated to match the export.
+Triggers, SLOs, and boards are matched by name, and existing ones are left alone.
+Recipients are matched by type and target, and created if missing. References between
+resources, such as trigger recipients and board queries, are remapped to the new IDs.
+Secrets of recipients to create are read from the environment variables named by the
+placeholders in recipients.yaml.
+
+Example:
+  honeycomb-cli import --input ./honeycomb-config --api-key $PROD_API_KEY`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c := newClient(cmd)
+			dir, _ := cmd.Flags().GetString("input")
+
+			e, err := readExport(dir)
+			if err != nil {
+				return err
+			}
+
+			return importEnvironment(cmd.Context(), c, e, cmd.OutOrStdout())
+		},
+	}
+	cmd.Flags().StringP("input", "i", "", "Directory to read the export from (required)")
+	_ = cmd.MarkFlagRequired("input")
+	return cmd
+}
+
+// importEnvironment creates the exported configuration, writing progress to out.
+func importEnvironment(ctx context.Context, c *honeycomb.Client, e *environmentExport, out io.Writer) error {
+	recipientIDs, err := importRecipients(ctx, c, e.Recipients, out)
+	if err != nil {
+		return err
+	}
+
+	slugs := map[string]string{}
+	for _, de := range e.Datasets {
+		slug, err := importDataset(ctx, c, de, recipientIDs, out)
+		if err != nil {
+			return fmt.Errorf("dataset %v: %w", de.Slug, err)
+		}
+		slugs[de.Slug] = slug
+	}
+
+	return importBoards(ctx, c, e.Boards, slugs, out)
+}
+
+// recipientKey identifies a recipient across environments, since IDs differ.
+func recipientKey(r honeycomb.Recipient) string {
+	return r.Type + "\x00" + r.Target()
+}
+
+// importRecipients creates missing recipients and returns a map from exported to imported IDs.
+func importRecipients(ctx context.Context, c *honeycomb.Client, recipients []honeycomb.Recipient, out io.Writer) (map[string]string, error) {
+	existing, err := c.ListRecipients(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("listing recipients: %w", err)
+	}
+	byKey := map[string]string{}
+	for _, r := range existing {
+		byKey[recipientKey(r)] = r.ID
+	}
+
+	ids := map[string]string{}
+	for _, r := range recipients {
+		if id, ok := byKey[recipientKey(r)]; ok {
+			ids[r.ID] = id
+			continue
+		}
+		if err := resolveRecipientSecrets(&r); err != nil {
+			return nil, fmt.Errorf("creating %v recipient %q: %w", r.Type, r.Target(), err)
+		}
+		created, err := c.CreateRecipient(ctx, r)
+		if err != nil {
+			return nil, fmt.Errorf("creating %v recipient %q: %w", r.Type, r.Target(), err)
+		}
+		ids[r.ID] = created.ID
+		fmt.Fprintf(out, "Created %v recipient %q\n", r.Type, r.Target())
+	}
+	return ids, nil
+}
+
+var envVarPlaceholderRegexp = regexp.MustCompile(`^\$\{([A-Za-z0-9_]+)\}$`)
+
+// resolveRecipientSecrets replaces the secret placeholders written by export with values from the environment.
+func resolveRecipientSecrets(r *honeycomb.Recipient) error {
+	for _, secret := range []*string{&r.Details.WebhookSecret, &r.Details.PagerDutyIntegrationKey} {
+		m := envVarPlaceholderRegexp.FindStringSubmatch(*secret)
+		if m == nil {
+			continue
+		}
+		v := os.Getenv(m[1])
+		if v == "" {
+			return fmt.Errorf("secret must be set in the %v environment variable", m[1])
+		}
+		*secret = v
+	}
+	return nil
+}
+
+// remapRecipients to the IDs they were imported with.
+func remapRecipients(recipients []honeycomb.NotificationRecipient, ids map[string]string) ([]honeycomb.NotificationRecipient, error) {
+	var remapped []honeycomb.NotificationRecipient
+	for _, r := range recipients {
+		id, ok := ids[r.ID]
+		if !ok {
+			return nil, fmt.Errorf("recipient %v (%v %v) is not in the export", r.ID, r.Type, r.Target)
+		}
+		remapped = append(remapped, honeycomb.NotificationRecipient{ID: id})
+	}
+	return remapped, nil
+}
+
+func isNotFound(err error) bool {
+	var apiErr *honeycomb.APIError
+	return errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound
+}
+
+// importDataset creates or updates the dataset and its configuration, and returns its slug.
+func importDataset(ctx context.Context, c *honeycomb.Client, de datasetExport, recipientIDs map[string]string, out io.Writer) (string, error) {
+	d := de.Dataset
+	slug := de.Slug
+
+	dataset, err := c.GetDataset(ctx, slug)
+	switch {
+	case isNotFound(err):
+		dataset, err = c.CreateDataset(ctx, honeycomb.CreateDatasetRequest{
+			Name:            d.Name,
+			Description:     d.Description,
+			ExpandJSONDepth: d.ExpandJSONDepth,
+		})
+		if err != nil {
+			return "", fmt.Errorf("creating dataset: %w", err)
+		}
+		slug = dataset.Slug
+		fmt.Fprintf(out, "Created dataset %q (%v)\n", dataset.Name, slug)
+	case err != nil:
+		return "", err
+	}
+
+	if dataset.Description != d.Description || dataset.ExpandJSONDepth != d.ExpandJSONDepth ||
+		dataset.Settings.DeleteProtected != d.DeleteProtected {
+		_, err := c.UpdateDataset(ctx, slug, honeycomb.UpdateDatasetRequest{
+			Description:     &d.Description,
+			ExpandJSONDepth: &d.ExpandJSONDepth,
+			Settings:        &honeycomb.UpdateDatasetSettingsRequest{DeleteProtected: &d.DeleteProtected},
+		})
+		if err != nil {
+			return "", fmt.Errorf("updating dataset settings: %w", err)
+		}
+	}
+
+	if err := importColumns(ctx, c, slug, de.Columns, out); err != nil {
+		return "", err
+	}
+	if err := importDerivedColumns(ctx, c, slug, de.DerivedColumns, out); err != nil {
+		return "", err
+	}
+
+	if d.Definitions != nil {
+		// Only send the column names, the column types are derived by Honeycomb.
+		var definitions honeycomb.DatasetDefinitions
+		for _, f := range datasetDefinitionFields {
+			if def := *f.field(d.Definitions); def != nil && def.Name != "" {
+				*f.field(&definitions) = &honeycomb.DatasetDefinition{Name: def.Name}
+			}
+		}
+		if _, err := c.UpdateDatasetDefinitions(ctx, slug, definitions); err != nil {
+			return "", fmt.Errorf("updating definitions: %w", err)
+		}
+	}
+
+	existingSettings, err := c.ListMarkerSettings(ctx, slug)
+	if err != nil {
+		return "", fmt.Errorf("listing marker settings: %w", err)
+	}
+	for _, ms := range de.MarkerSettings {
+		if hasMarkerSetting(existingSettings, ms.Type) {
+			continue
+		}
+		if _, err := c.CreateMarkerSetting(ctx, slug, ms); err != nil {
+			return "", fmt.Errorf("creating marker setting %q: %w", ms.Type, err)
+		}
+		fmt.Fprintf(out, "Created marker setting %q in %v\n", ms.Type, slug)
+	}
+
+	existingTriggers, err := c.ListTriggers(ctx, slug)
+	if err != nil {
+		return "", fmt.Errorf("listing triggers: %w", err)
+	}
+	for _, t := range de.Triggers {
+		if hasName(existingTriggers, t.Name, func(t honeycomb.Trigger) string { return t.Name }) {
+			fmt.Fprintf(out, "Skipped trigger %q in %v (already exists)\n", t.Name, slug)
+			continue
+		}
+		if t.Recipients, err = remapRecipients(t.Recipients, recipientIDs); err != nil {
+			return "", fmt.Errorf("trigger %q: %w", t.Name, err)
+		}
+		if _, err := c.CreateTrigger(ctx, slug, t); err != nil {
+			return "", fmt.Errorf("creating trigger %q: %w", t.Name, err)
+		}
+		fmt.Fprintf(out, "Created trigger %q in %v\n", t.Name, slug)
+	}
+
+	existingSLOs, err := c.ListSLOs(ctx, slug)
+	if err != nil {
+		return "", fmt.Errorf("listing SLOs: %w", err)
+	}
+	for _, sf := range de.SLOs {
+		if hasName(existingSLOs, sf.Name, func(s honeycomb.SLO) string { return s.Name }) {
+			fmt.Fprintf(out, "Skipped SLO %q in %v (already exists)\n", sf.Name, slug)
+			continue
+		}
+		slo, err := c.CreateSLO(ctx, slug, sf.SLO)
+		if err != nil {
+			return "", fmt.Errorf("creating SLO %q: %w", sf.Name, err)
+		}
+		fmt.Fprintf(out, "Created SLO %q in %v\n", sf.Name, slug)
+
+		for _, a := range sf.BurnAlerts {
+			a.SLO = honeycomb.BurnAlertSLO{ID: slo.ID}
+			if a.Recipients, err = remapRecipients(a.Recipients, recipientIDs); err != nil {
+				return "", fmt.Errorf("burn alert for SLO %q: %w", sf.Name, err)
+			}
+			if _, err := c.CreateBurnAlert(ctx, slug, a); err != nil {
+				return "", fmt.Errorf("creating burn alert for SLO %q: %w", sf.Name, err)
+			}
+		}
+	}
+
+	return slug, nil
+}
+
+func importColumns(ctx context.Context, c *honeycomb.Client, slug string, columns []honeycomb.CreateColumnRequest, out io.Writer) error {
+	existing, err := c.ListColumns(ctx, slug)
+	if err != nil {
+		return fmt.Errorf("listing columns: %w", err)
+	}
+	byName := map[string]honeycomb.Column{}
+	for _, col := range existing {
+		byName[col.KeyName] = col
+	}
+
+	var created, updated int
+	for _, col := range columns {
+		current, ok := byName[col.KeyName]
+		if !ok {
+			if _, err := c.CreateColumn(ctx, slug, col); err != nil {
+				return fmt.Errorf("creating column %q: %w", col.KeyName, err)
+			}
+			created++
+			continue
+		}
+		if current.Type == col.Type && current.Description == col.Description && current.Hidden == col.Hidden {
+			continue
+		}
+		_, err := c.UpdateColumn(ctx, slug, current.ID, honeycomb.UpdateColumnRequest{
+			Description: &col.Description,
+			Type:        &col.Type,
+			Hidden:      &col.Hidden,
+		})
+		if err != nil {
+			return fmt.Errorf("updating column %q: %w", col.KeyName, err)
+		}
+		updated++
+	}
+
+	if created > 0 || updated > 0 {
+		fmt.Fprintf(out, "Created %v and updated %v column(s) in %v\n", created, updated, slug)
+	}
+	return nil
+}
+
+func importDerivedColumns(ctx context.Context, c *honeycomb.Client, slug string, columns []honeycomb.DerivedColumn, out io.Writer) error {
+	existing, err := c.ListDerivedColumns(ctx, slug)
+	if err != nil {
+		return fmt.Errorf("listing derived columns: %w", err)
+	}
+	byAlias := map[string]honeycomb.DerivedColumn{}
+	for _, dc := range existing {
+		byAlias[dc.Alias] = dc
+	}
+
+	for _, dc := range columns {
+		current, ok := byAlias[dc.Alias]
+		if !ok {
+			if _, err := c.CreateDerivedColumn(ctx, slug, dc); err != nil {
+				return fmt.Errorf("creating derived column %q: %w", dc.Alias, err)
+			}
+			fmt.Fprintf(out, "Created derived column %q in %v\n", dc.Alias, slug)
+			continue
+		}
+		if current.Expression == dc.Expression && current.Description == dc.Description {
+			continue
+		}
+		dc.ID = current.ID
+		if _, err := c.UpdateDerivedColumn(ctx, slug, dc); err != nil {
+			return fmt.Errorf("updating derived column %q: %w", dc.Alias, err)
+		}
+		fmt.Fprintf(out, "Updated derived column %q in %v\n", dc.Alias, slug)
+	}
+	return nil
+}
+
+// importBoards creates boards that don't exist yet, pointing their queries at the imported datasets.
+func importBoards(ctx context.Context, c *honeycomb.Client, boards []boardFile, slugs map[string]string, out io.Writer) error {
+	existing, err := c.ListBoards(ctx)
+	if err != nil {
+		return fmt.Errorf("listing boards: %w", err)
+	}
+
+	for _, bf := range boards {
+		if hasName(existing, bf.Name, func(b honeycomb.Board) string { return b.Name }) {
+			fmt.Fprintf(out, "Skipped board %q (already exists)\n", bf.Name)
+			continue
+		}
+
+		board := honeycomb.Board{
+			Name:         bf.Name,
+			Description:  bf.Description,
+			Style:        bf.Style,
+			ColumnLayout: bf.ColumnLayout,
+		}
+		for i, q := range bf.Queries {
+			dataset := q.Dataset
+			if slug, ok := slugs[dataset]; ok {
+				dataset = slug
+			}
+			query, err := c.CreateQuery(ctx, dataset, q.Query)
+			if err != nil {
+				return fmt.Errorf("creating query %v for board %q: %w", i+1, bf.Name, err)
+			}
+			board.Queries = append(board.Queries, honeycomb.BoardQuery{
+				Caption:    q.Caption,
+				QueryStyle: q.QueryStyle,
+				Dataset:    dataset,
+				QueryID:    query.ID,
+			})
+		}
+
+		if _, err := c.CreateBoard(ctx, board); err != nil {
+			return fmt.Errorf("creating board %q: %w", bf.Name, err)
+		}
+		fmt.Fprintf(out, "Created board %q\n", bf.Name)
+	}
+	return nil
+}
+
+func hasMarkerSetting(settings []honeycomb.MarkerSetting, markerType string) bool {
+	for _, ms := range settings {
+		if strings.EqualFold(ms.Type, markerType) {
+			return true
+		}
+	}
+	return false
+}
+
+// hasName reports whether any of the items has the given name.
+func hasName[T any](items []T, name string, nameOf func(T) string) bool {
+	for _, item := range items {
+		if nameOf(item) == name {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,258 @@
+package cmd_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"maragu.dev/is"
+
+	"github.com/maragudk/honeycomb-cli/cmd"
+	"github.com/maragudk/honeycomb-cli/honeycomb"
+)
+
+func writeTestExport(t *testing.T, dir string) {
+	t.Helper()
+
+	files := map[string]string{
+		"recipients.yaml": `- id: r1
+  type: email
+  details:
+    email_address: oncall@example.com
+`,
+		"boards.yaml": `- name: API overview
+  queries:
+    - caption: Requests
+      dataset: api
+      query:
+        calculations:
+          - op: COUNT
+`,
+		"datasets/api/dataset.yaml": `name: API
+description: Public API
+delete_protected: true
+`,
+		"datasets/api/columns.yaml": `- key_name: duration_ms
+  type: float
+  description: Request duration
+`,
+		"datasets/api/triggers.yaml": `- name: High error rate
+  disabled: false
+  threshold:
+    op: '>'
+    value: 10
+  query:
+    calculations:
+      - op: COUNT
+  recipients:
+    - id: r1
+`,
+		"datasets/api/slos.yaml": `- name: Availability
+  sli:
+    alias: is_good
+  target_per_million: 999000
+  time_period_days: 30
+  burn_alerts:
+    - alert_type: exhaustion_time
+      exhaustion_minutes: 60
+      recipients:
+        - id: r1
+`,
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		is.NotError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		is.NotError(t, os.WriteFile(path, []byte(content), 0600))
+	}
+}
+
+func TestImportCommand(t *testing.T) {
+	t.Run("creates configuration and remaps recipient and query IDs", func(t *testing.T) {
+		dir := t.TempDir()
+		writeTestExport(t, dir)
+
+		var createdTrigger honeycomb.Trigger
+		var createdBurnAlert honeycomb.BurnAlert
+		var createdBoard honeycomb.Board
+		var createdColumn honeycomb.CreateColumnRequest
+		var datasetUpdated bool
+
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch r.Method + " " + r.URL.Path {
+			case "GET /1/recipients":
+				_ = json.NewEncoder(w).Encode([]honeycomb.Recipient{})
+			case "POST /1/recipients":
+				_ = json.NewEncoder(w).Encode(honeycomb.Recipient{ID: "new-r1"})
+			case "GET /1/datasets/api":
+				w.WriteHeader(http.StatusNotFound)
+				_, _ = w.Write([]byte(`{"error":"dataset not found"}`))
+			case "POST /1/datasets":
+				_ = json.NewEncoder(w).Encode(honeycomb.Dataset{Name: "API", Slug: "api", Description: "Public API"})
+			case "PUT /1/datasets/api":
+				datasetUpdated = true
+				_ = json.NewEncoder(w).Encode(honeycomb.Dataset{Name: "API", Slug: "api"})
+			case "GET /1/columns/api":
+				_ = json.NewEncoder(w).Encode([]honeycomb.Column{})
+			case "POST /1/columns/api":
+				_ = json.NewDecoder(r.Body).Decode(&createdColumn)
+				_ = json.NewEncoder(w).Encode(honeycomb.Column{ID: "c1", KeyName: createdColumn.KeyName})
+			case "GET /1/derived_columns/api", "GET /1/marker_settings/api", "GET /1/triggers/api", "GET /1/slos/api", "GET /1/boards":
+				_, _ = w.Write([]byte(`[]`))
+			case "POST /1/triggers/api":
+				_ = json.NewDecoder(r.Body).Decode(&createdTrigger)
+				_ = json.NewEncoder(w).Encode(honeycomb.Trigger{ID: "t1", Name: createdTrigger.Name})
+			case "POST /1/slos/api":
+				_ = json.NewEncoder(w).Encode(honeycomb.SLO{ID: "new-s1", Name: "Availability"})
+			case "POST /1/burn_alerts/api":
+				_ = json.NewDecoder(r.Body).Decode(&createdBurnAlert)
+				_ = json.NewEncoder(w).Encode(honeycomb.BurnAlert{ID: "ba1"})
+			case "POST /1/queries/api":
+				_ = json.NewEncoder(w).Encode(honeycomb.QueryResponse{ID: "new-q1"})
+			case "POST /1/boards":
+				_ = json.NewDecoder(r.Body).Decode(&createdBoard)
+				_ = json.NewEncoder(w).Encode(honeycomb.Board{ID: "b1", Name: createdBoard.Name})
+			default:
+				t.Errorf("unexpected request %v %v", r.Method, r.URL.Path)
+				http.Error(w, `{"error": "not found"}`, http.StatusNotFound)
+			}
+		}))
+		defer server.Close()
+
+		var buf bytes.Buffer
+		root := cmd.NewRootCommand()
+		root.SetOut(&buf)
+		root.SetArgs([]string{"import", "--input", dir, "--api-key", "test", "--api-url", server.URL})
+
+		err := root.Execute()
+		is.NotError(t, err)
+
+		output := buf.String()
+		is.True(t, contains(output, `Created dataset "API" (api)`))
+		is.True(t, contains(output, `Created trigger "High error rate" in api`))
+		is.True(t, contains(output, `Created board "API overview"`))
+
+		is.True(t, datasetUpdated)
+		is.Equal(t, "duration_ms", createdColumn.KeyName)
+		is.Equal(t, 1, len(createdTrigger.Recipients))
+		is.Equal(t, "new-r1", createdTrigger.Recipients[0].ID)
+		is.Equal(t, "new-s1", createdBurnAlert.SLO.ID)
+		is.Equal(t, "new-r1", createdBurnAlert.Recipients[0].ID)
+		is.Equal(t, 1, len(createdBoard.Queries))
+		is.Equal(t, "new-q1", createdBoard.Queries[0].QueryID)
+	})
+
+	t.Run("skips triggers that already exist and reuses matching recipients", func(t *testing.T) {
+		dir := t.TempDir()
+		writeTestExport(t, dir)
+
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch r.Method + " " + r.URL.Path {
+			case "GET /1/recipients":
+				_ = json.NewEncoder(w).Encode([]honeycomb.Recipient{{ID: "r9", Type: "email", Details: honeycomb.RecipientDetails{EmailAddress: "oncall@example.com"}}})
+			case "GET /1/datasets/api":
+				_ = json.NewEncoder(w).Encode(honeycomb.Dataset{Name: "API", Slug: "api", Description: "Public API", Settings: honeycomb.DatasetSettings{DeleteProtected: true}})
+			case "GET /1/columns/api":
+				_ = json.NewEncoder(w).Encode([]honeycomb.Column{{ID: "c1", KeyName: "duration_ms", Type: "float", Description: "Request duration"}})
+			case "GET /1/triggers/api":
+				_ = json.NewEncoder(w).Encode([]honeycomb.Trigger{{ID: "t1", Name: "High error rate"}})
+			case "GET /1/slos/api":
+				_ = json.NewEncoder(w).Encode([]honeycomb.SLO{{ID: "s1", Name: "Availability"}})
+			case "GET /1/boards":
+				_ = json.NewEncoder(w).Encode([]honeycomb.Board{{ID: "b1", Name: "API overview"}})
+			case "GET /1/derived_columns/api", "GET /1/marker_settings/api":
+				_, _ = w.Write([]byte(`[]`))
+			default:
+				t.Errorf("unexpected request %v %v", r.Method, r.URL.Path)
+				http.Error(w, `{"error": "not found"}`, http.StatusNotFound)
+			}
+		}))
+		defer server.Close()
+
+		var buf bytes.Buffer
+		root := cmd.NewRootCommand()
+		root.SetOut(&buf)
+		root.SetArgs([]string{"import", "--input", dir, "--api-key", "test", "--api-url", server.URL})
+
+		err := root.Execute()
+		is.NotError(t, err)
+
+		output := buf.String()
+		is.True(t, contains(output, `Skipped trigger "High error rate" in api (already exists)`))
+		is.True(t, contains(output, `Skipped SLO "Availability" in api (already exists)`))
+		is.True(t, contains(output, `Skipped board "API overview" (already exists)`))
+		is.True(t, !contains(output, "Created"))
+	})
+	t.Run("reads recipient secrets from the environment", func(t *testing.T) {
+		dir := t.TempDir()
+		err := os.WriteFile(filepath.Join(dir, "recipients.yaml"), []byte(`- id: r1
+  type: webhook
+  details:
+    webhook_name: Deploys
+    webhook_url: https://example.com/hook
+    webhook_secret: ${HONEYCOMB_RECIPIENT_DEPLOYS_WEBHOOK_SECRET}
+`), 0600)
+		is.NotError(t, err)
+
+		var created honeycomb.Recipient
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch r.Method + " " + r.URL.Path {
+			case "GET /1/recipients", "GET /1/boards":
+				_, _ = w.Write([]byte(`[]`))
+			case "POST /1/recipients":
+				_ = json.NewDecoder(r.Body).Decode(&created)
+				_ = json.NewEncoder(w).Encode(honeycomb.Recipient{ID: "new-r1"})
+			default:
+				t.Errorf("unexpected request %v %v", r.Method, r.URL.Path)
+				http.Error(w, `{"error": "not found"}`, http.StatusNotFound)
+			}
+		}))
+		defer server.Close()
+
+		t.Setenv("HONEYCOMB_RECIPIENT_DEPLOYS_WEBHOOK_SECRET", "s3cret")
+
+		root := cmd.NewRootCommand()
+		root.SetOut(&bytes.Buffer{})
+		root.SetArgs([]string{"import", "--input", dir, "--api-key", "test", "--api-url", server.URL})
+
+		err = root.Execute()
+		is.NotError(t, err)
+		is.Equal(t, "s3cret", created.Details.WebhookSecret)
+	})
+
+	t.Run("errors if a recipient secret is missing from the environment", func(t *testing.T) {
+		dir := t.TempDir()
+		err := os.WriteFile(filepath.Join(dir, "recipients.yaml"), []byte(`- id: r1
+  type: pagerduty
+  details:
+    pagerduty_integration_name: On-call
+    pagerduty_integration_key: ${HONEYCOMB_RECIPIENT_ON_CALL_PAGERDUTY_INTEGRATION_KEY}
+`), 0600)
+		is.NotError(t, err)
+
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch r.Method + " " + r.URL.Path {
+			case "GET /1/recipients":
+				_, _ = w.Write([]byte(`[]`))
+			default:
+				t.Errorf("unexpected request %v %v", r.Method, r.URL.Path)
+				http.Error(w, `{"error": "not found"}`, http.StatusNotFound)
+			}
+		}))
+		defer server.Close()
+
+		t.Setenv("HONEYCOMB_RECIPIENT_ON_CALL_PAGERDUTY_INTEGRATION_KEY", "")
+
+		root := cmd.NewRootCommand()
+		root.SetOut(&bytes.Buffer{})
+		root.SetErr(&bytes.Buffer{})
+		root.SetArgs([]string{"import", "--input", dir, "--api-key", "test", "--api-url", server.URL})
+
+		err = root.Execute()
+		is.True(t, err != nil)
+		is.True(t, contains(err.Error(), "HONEYCOMB_RECIPIENT_ON_CALL_PAGERDUTY_INTEGRATION_KEY environment variable"))
+	})
+}
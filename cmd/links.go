@@ -0,0 +1,93 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+
+	"github.com/spf13/cobra"
+
+	"github.com/maragudk/honeycomb-cli/honeycomb"
+)
+
+// uiLink is a labelled URL to the Honeycomb UI.
+type uiLink struct {
+	Label string
+	URL   string
+}
+
+// addLinkFlags adds the --link and --open flags read by [showLinks].
+func addLinkFlags(cmd *cobra.Command) {
+	cmd.Flags().Bool("link", false, "Print the Honeycomb UI URL")
+	cmd.Flags().Bool("open", false, "Open the Honeycomb UI URL in a browser")
+}
+
+// showLinks prints the links with --link, and opens the first one with --open.
+// Links are printed to stderr with --json, so they don't mix with the JSON output.
+// The links function is only called, and the team and environment only looked up, when one of the flags is set.
+func showLinks(cmd *cobra.Command, c *honeycomb.Client, links func(l *honeycomb.UILinks) ([]uiLink, error)) error {
+	printLinks, _ := cmd.Flags().GetBool("link")
+	openLink, _ := cmd.Flags().GetBool("open")
+	if !printLinks && !openLink {
+		return nil
+	}
+
+	l, err := c.UILinks(cmd.Context())
+	if err != nil {
+		return fmt.Errorf("getting team and environment for links: %w", err)
+	}
+	ls, err := links(l)
+	if err != nil {
+		return err
+	}
+	if len(ls) == 0 {
+		return nil
+	}
+
+	if printLinks {
+		out := cmd.OutOrStdout()
+		if asJSON, _ := cmd.Flags().GetBool("json"); asJSON {
+			out = cmd.ErrOrStderr()
+		}
+		for _, link := range ls {
+			fmt.Fprintf(out, "%v: %v\n", link.Label, link.URL)
+		}
+	}
+
+	if openLink {
+		if err := openBrowser(ls[0].URL); err != nil {
+			return fmt.Errorf("opening %v: %w", ls[0].URL, err)
+		}
+	}
+	return nil
+}
+
+// queryResultLinks for a query result, preferring the URL returned by the API, and with its graph image if there is one.
+func queryResultLinks(l *honeycomb.UILinks, label, dataset string, result *honeycomb.QueryResult) []uiLink {
+	u := result.Links.QueryURL
+	if u == "" {
+		u = l.QueryResult(dataset, result.ID)
+	}
+	links := []uiLink{{Label: label, URL: u}}
+	if result.Links.GraphURL != "" {
+		links = append(links, uiLink{Label: "Graph image", URL: result.Links.GraphURL})
+	}
+	return links
+}
+
+// openBrowser opens the URL with the command in the BROWSER environment variable, or the system's default browser.
+func openBrowser(url string) error {
+	if browser := os.Getenv("BROWSER"); browser != "" {
+		return exec.Command(browser, url).Run()
+	}
+
+	switch runtime.GOOS {
+	case "darwin":
+		return exec.Command("open", url).Run()
+	case "windows":
+		return exec.Command("rundll32", "url.dll,FileProtocolHandler", url).Run()
+	default:
+		return exec.Command("xdg-open", url).Run()
+	}
+}
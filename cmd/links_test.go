@@ -0,0 +1,165 @@
+package cmd_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"maragu.dev/is"
+
+	"github.com/maragudk/honeycomb-cli/cmd"
+	"github.com/maragudk/honeycomb-cli/honeycomb"
+)
+
+func TestLinks(t *testing.T) {
+	t.Run("prints the trigger link", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch {
+			case r.URL.Path == "/1/auth":
+				_ = json.NewEncoder(w).Encode(honeycomb.AuthResponse{
+					Team:        honeycomb.AuthTeam{Slug: "acme"},
+					Environment: honeycomb.AuthEnvironment{Slug: "production"},
+				})
+
+			case r.URL.Path == "/1/triggers/requests/t1":
+				_ = json.NewEncoder(w).Encode(honeycomb.Trigger{ID: "t1", Name: "High Error Rate"})
+
+			default:
+				http.NotFound(w, r)
+			}
+		}))
+		defer server.Close()
+
+		var buf bytes.Buffer
+		root := cmd.NewRootCommand()
+		root.SetOut(&buf)
+		root.SetArgs([]string{"triggers", "get", "t1", "--dataset", "requests", "--link",
+			"--api-key", "test", "--api-url", server.URL})
+
+		err := root.Execute()
+		is.NotError(t, err)
+
+		is.True(t, contains(buf.String(), "High Error Rate"))
+		is.True(t, contains(buf.String(), "Trigger: https://ui.honeycomb.io/acme/environments/production/datasets/requests/triggers/t1\n"))
+	})
+
+	t.Run("prints links to stderr with json output", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch {
+			case r.URL.Path == "/1/auth":
+				_ = json.NewEncoder(w).Encode(honeycomb.AuthResponse{
+					Team:        honeycomb.AuthTeam{Slug: "acme"},
+					Environment: honeycomb.AuthEnvironment{Slug: "production"},
+				})
+
+			case r.URL.Path == "/1/triggers/requests/t1":
+				_ = json.NewEncoder(w).Encode(honeycomb.Trigger{ID: "t1", Name: "High Error Rate"})
+
+			default:
+				http.NotFound(w, r)
+			}
+		}))
+		defer server.Close()
+
+		var out, errOut bytes.Buffer
+		root := cmd.NewRootCommand()
+		root.SetOut(&out)
+		root.SetErr(&errOut)
+		root.SetArgs([]string{"triggers", "get", "t1", "--dataset", "requests", "--link", "--json",
+			"--api-key", "test", "--api-url", server.URL})
+
+		err := root.Execute()
+		is.NotError(t, err)
+
+		var trigger honeycomb.Trigger
+		is.NotError(t, json.Unmarshal(out.Bytes(), &trigger))
+		is.Equal(t, "t1", trigger.ID)
+		is.True(t, contains(errOut.String(), "Trigger: https://ui.honeycomb.io/"))
+	})
+
+	t.Run("prints the query result and graph image links", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch {
+			case r.URL.Path == "/1/auth":
+				_ = json.NewEncoder(w).Encode(honeycomb.AuthResponse{
+					Team:        honeycomb.AuthTeam{Slug: "acme"},
+					Environment: honeycomb.AuthEnvironment{Slug: "production"},
+				})
+
+			case r.Method == http.MethodPost && r.URL.Path == "/1/queries/requests":
+				_ = json.NewEncoder(w).Encode(honeycomb.QueryResponse{ID: "q1"})
+
+			case r.Method == http.MethodPost && r.URL.Path == "/1/query_results/requests":
+				_ = json.NewEncoder(w).Encode(honeycomb.QueryResult{ID: "r1"})
+
+			case r.Method == http.MethodGet && r.URL.Path == "/1/query_results/requests/r1":
+				result := honeycomb.QueryResult{ID: "r1", Complete: true}
+				result.Data.Results = []map[string]any{{"COUNT": float64(42)}}
+				result.Links.GraphURL = "https://example.com/graph.png"
+				_ = json.NewEncoder(w).Encode(result)
+
+			default:
+				http.NotFound(w, r)
+			}
+		}))
+		defer server.Close()
+
+		var buf bytes.Buffer
+		root := cmd.NewRootCommand()
+		root.SetOut(&buf)
+		root.SetArgs([]string{"query", "--dataset", "requests", "--link",
+			"--api-key", "test", "--api-url", server.URL})
+
+		err := root.Execute()
+		is.NotError(t, err)
+
+		is.True(t, contains(buf.String(), "42"))
+		is.True(t, contains(buf.String(), "Query result: https://ui.honeycomb.io/acme/environments/production/datasets/requests/result/r1\n"))
+		is.True(t, contains(buf.String(), "Graph image: https://example.com/graph.png\n"))
+	})
+
+	t.Run("opens the link in the browser", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch {
+			case r.URL.Path == "/1/auth":
+				_ = json.NewEncoder(w).Encode(honeycomb.AuthResponse{
+					Team:        honeycomb.AuthTeam{Slug: "acme"},
+					Environment: honeycomb.AuthEnvironment{Slug: "production"},
+				})
+
+			case r.URL.Path == "/1/triggers/requests/t1":
+				_ = json.NewEncoder(w).Encode(honeycomb.Trigger{ID: "t1", Name: "High Error Rate"})
+
+			default:
+				http.NotFound(w, r)
+			}
+		}))
+		defer server.Close()
+
+		dir := t.TempDir()
+		opened := filepath.Join(dir, "opened")
+		browser := filepath.Join(dir, "browser")
+		err := os.WriteFile(browser, []byte("#!/bin/sh\necho \"$1\" > "+opened+"\n"), 0o755)
+		is.NotError(t, err)
+		t.Setenv("BROWSER", browser)
+
+		var buf bytes.Buffer
+		root := cmd.NewRootCommand()
+		root.SetOut(&buf)
+		root.SetArgs([]string{"triggers", "get", "t1", "--dataset", "requests", "--open",
+			"--api-key", "test", "--api-url", server.URL})
+
+		err = root.Execute()
+		is.NotError(t, err)
+
+		data, err := os.ReadFile(opened)
+		is.NotError(t, err)
+		is.Equal(t, "https://ui.honeycomb.io/acme/environments/production/datasets/requests/triggers/t1", strings.TrimSpace(string(data)))
+		is.True(t, !contains(buf.String(), "Trigger: "))
+	})
+}
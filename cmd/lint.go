@@ -0,0 +1,257 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/maragudk/honeycomb-cli/honeycomb"
+)
+
+// lintFinding is a problem with a trigger or SLO.
+type lintFinding struct {
+	Dataset  string `json:"dataset"`
+	Kind     string `json:"kind"`
+	ID       string `json:"id"`
+	Name     string `json:"name"`
+	Rule     string `json:"rule"`
+	Severity string `json:"severity"`
+	Message  string `json:"message"`
+}
+
+const (
+	severityError   = "error"
+	severityWarning = "warning"
+)
+
+// maxTriggerTimeRangeFactor is how many times the trigger frequency a trigger query's time range may be.
+const maxTriggerTimeRangeFactor = 4
+
+// queryColumns returns the columns a query refers to, deduplicated and sorted.
+func queryColumns(spec honeycomb.QuerySpec) []string {
+	var columns []string
+	for _, c := range spec.Calculations {
+		columns = append(columns, c.Column)
+	}
+	columns = append(columns, spec.Breakdowns...)
+	for _, f := range spec.Filters {
+		columns = append(columns, f.Column)
+	}
+	for _, o := range spec.Orders {
+		columns = append(columns, o.Column)
+	}
+
+	columns = slices.DeleteFunc(columns, func(c string) bool { return c == "" })
+	sort.Strings(columns)
+	return slices.Compact(columns)
+}
+
+// lintDataset checks the triggers and SLOs of a dataset against its columns and derived columns.
+// Trigger queries must already be fetched.
+func lintDataset(slug string, columns []honeycomb.Column, derived []honeycomb.DerivedColumn, triggers []honeycomb.Trigger,
+	slos []honeycomb.SLO, now time.Time, staleAfter time.Duration) []lintFinding {
+	var findings []lintFinding
+
+	columnsByName := map[string]honeycomb.Column{}
+	for _, col := range columns {
+		columnsByName[col.KeyName] = col
+	}
+	derivedByAlias := map[string]bool{}
+	for _, dc := range derived {
+		derivedByAlias[dc.Alias] = true
+	}
+
+	triggerNames := map[string]int{}
+	for _, t := range triggers {
+		triggerNames[t.Name]++
+		add := func(rule, severity, format string, args ...any) {
+			findings = append(findings, lintFinding{Dataset: slug, Kind: "trigger", ID: t.ID, Name: t.Name,
+				Rule: rule, Severity: severity, Message: fmt.Sprintf(format, args...)})
+		}
+
+		if t.Query != nil {
+			for _, name := range queryColumns(*t.Query) {
+				if derivedByAlias[name] {
+					continue
+				}
+				col, ok := columnsByName[name]
+				if !ok {
+					add("missing-column", severityError, "query refers to column %q, which doesn't exist", name)
+					continue
+				}
+				if lastWritten, err := time.Parse(time.RFC3339, col.LastWritten); err == nil && now.Sub(lastWritten) > staleAfter {
+					add("stale-column", severityWarning, "query refers to column %q, which was last written %v",
+						name, lastWritten.Format(time.DateOnly))
+				}
+			}
+
+			if t.Frequency > 0 && t.Query.TimeRange > 0 {
+				switch {
+				case t.Query.TimeRange < t.Frequency:
+					add("time-range-gap", severityWarning,
+						"query time range %vs is shorter than the frequency %vs, so some events are never evaluated",
+						t.Query.TimeRange, t.Frequency)
+				case t.Query.TimeRange > maxTriggerTimeRangeFactor*t.Frequency:
+					add("time-range-too-long", severityError,
+						"query time range %vs is more than %v times the frequency %vs",
+						t.Query.TimeRange, maxTriggerTimeRangeFactor, t.Frequency)
+				}
+			}
+		}
+
+		if t.Disabled && t.Description == "" {
+			add("disabled-undocumented", severityWarning, "trigger is disabled without a description saying why")
+		}
+		if !t.Disabled && len(t.Recipients) == 0 {
+			add("no-recipients", severityWarning, "trigger has no recipients, so nobody is notified when it fires")
+		}
+	}
+
+	sloNames := map[string]int{}
+	for _, s := range slos {
+		sloNames[s.Name]++
+		if !derivedByAlias[s.SLI.Alias] {
+			findings = append(findings, lintFinding{Dataset: slug, Kind: "slo", ID: s.ID, Name: s.Name,
+				Rule: "missing-sli", Severity: severityError,
+				Message: fmt.Sprintf("SLI derived column %q doesn't exist", s.SLI.Alias)})
+		}
+	}
+
+	for kind, names := range map[string]map[string]int{"trigger": triggerNames, "slo": sloNames} {
+		for name, count := range names {
+			if count > 1 {
+				findings = append(findings, lintFinding{Dataset: slug, Kind: kind, Name: name,
+					Rule: "duplicate-name", Severity: severityWarning,
+					Message: fmt.Sprintf("%v %vs have this name", count, kind)})
+			}
+		}
+	}
+
+	sort.SliceStable(findings, func(i, j int) bool {
+		a, b := findings[i], findings[j]
+		if a.Kind != b.Kind {
+			return a.Kind > b.Kind
+		}
+		return a.Name < b.Name
+	})
+	return findings
+}
+
+func newLintCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "lint",
+		Short: "Check triggers and SLOs for problems",
+		Long: `Check the triggers and SLOs in one or more datasets, or all datasets, for problems:
+
+  missing-column         trigger query refers to a column that doesn't exist (error)
+  stale-column           trigger query refers to a column not written recently (warning)
+  time-range-gap         trigger query time range is shorter than its frequency (warning)
+  time-range-too-long    trigger query time range is more than 4 times its frequency (error)
+  disabled-undocumented  trigger is disabled without a description (warning)
+  no-recipients          enabled trigger has no recipients (warning)
+  missing-sli            SLO's SLI derived column doesn't exist (error)
+  duplicate-name         several triggers or SLOs in a dataset have the same name (warning)
+
+Exits non-zero when problems are found, so it can run in CI. Use --fail-on error
+to only fail on errors.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c := newClient(cmd)
+			ctx := cmd.Context()
+			slugs, _ := cmd.Flags().GetStringSlice("dataset")
+			staleDays, _ := cmd.Flags().GetInt("stale-days")
+			failOn, _ := cmd.Flags().GetString("fail-on")
+
+			if failOn != severityWarning && failOn != severityError {
+				return fmt.Errorf("invalid --fail-on %q (must be warning or error)", failOn)
+			}
+
+			if len(slugs) == 0 {
+				datasets, err := c.ListDatasets(ctx)
+				if err != nil {
+					return err
+				}
+				for _, d := range datasets {
+					slugs = append(slugs, d.Slug)
+				}
+				sort.Strings(slugs)
+			}
+
+			// Environment-wide derived columns can be used in every dataset
+			environmentDerived, err := c.ListDerivedColumns(ctx, "__all__")
+			if err != nil {
+				return fmt.Errorf("listing environment-wide derived columns: %w", err)
+			}
+
+			findings := []lintFinding{}
+			for _, slug := range slugs {
+				columns, err := c.ListColumns(ctx, slug)
+				if err != nil {
+					return fmt.Errorf("listing columns for %v: %w", slug, err)
+				}
+				derived, err := c.ListDerivedColumns(ctx, slug)
+				if err != nil {
+					return fmt.Errorf("listing derived columns for %v: %w", slug, err)
+				}
+				derived = append(derived, environmentDerived...)
+				triggers, err := c.ListTriggers(ctx, slug)
+				if err != nil {
+					return fmt.Errorf("listing triggers for %v: %w", slug, err)
+				}
+				for i, t := range triggers {
+					if t.Query == nil && t.QueryID != "" {
+						if triggers[i].Query, err = c.GetQuery(ctx, slug, t.QueryID); err != nil {
+							return fmt.Errorf("getting query for trigger %q: %w", t.Name, err)
+						}
+					}
+				}
+				slos, err := c.ListSLOs(ctx, slug)
+				if err != nil {
+					return fmt.Errorf("listing SLOs for %v: %w", slug, err)
+				}
+
+				findings = append(findings, lintDataset(slug, columns, derived, triggers, slos, time.Now(),
+					time.Duration(staleDays)*24*time.Hour)...)
+			}
+
+			var errorCount, warningCount int
+			for _, f := range findings {
+				if f.Severity == severityError {
+					errorCount++
+				} else {
+					warningCount++
+				}
+			}
+
+			asJSON, _ := cmd.Flags().GetBool("json")
+			if asJSON {
+				if err := json.NewEncoder(cmd.OutOrStdout()).Encode(findings); err != nil {
+					return err
+				}
+			} else {
+				out := cmd.OutOrStdout()
+				for _, f := range findings {
+					fmt.Fprintf(out, "%v/%v %q: %v: %v (%v)\n", f.Dataset, f.Kind, f.Name, f.Severity, f.Message, f.Rule)
+				}
+				if len(findings) == 0 {
+					fmt.Fprintf(out, "No problems found in %v dataset(s)\n", len(slugs))
+				} else {
+					fmt.Fprintf(out, "\n%v error(s), %v warning(s)\n", errorCount, warningCount)
+				}
+			}
+
+			if errorCount > 0 || (failOn == severityWarning && warningCount > 0) {
+				return fmt.Errorf("lint found %v error(s) and %v warning(s)", errorCount, warningCount)
+			}
+			return nil
+		},
+	}
+	cmd.Flags().StringSlice("dataset", nil, "Datasets to check (default all)")
+	cmd.Flags().Int("stale-days", 30, "Report columns not written in this many days as stale")
+	cmd.Flags().String("fail-on", severityWarning, "Lowest severity that makes the command fail: warning or error")
+	cmd.Flags().Bool("json", false, "Output as JSON")
+	return cmd
+}
@@ -0,0 +1,157 @@
+package cmd_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"maragu.dev/is"
+
+	"github.com/maragudk/honeycomb-cli/cmd"
+	"github.com/maragudk/honeycomb-cli/honeycomb"
+)
+
+func TestLintCommand(t *testing.T) {
+	t.Run("reports problems and fails", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch r.URL.Path {
+			case "/1/datasets":
+				_ = json.NewEncoder(w).Encode([]honeycomb.Dataset{{Name: "API", Slug: "api"}})
+			case "/1/columns/api":
+				_ = json.NewEncoder(w).Encode([]honeycomb.Column{
+					{KeyName: "status_code", LastWritten: time.Now().Format(time.RFC3339)},
+					{KeyName: "legacy_status", LastWritten: time.Now().AddDate(0, -3, 0).Format(time.RFC3339)},
+				})
+			case "/1/derived_columns/api":
+				_ = json.NewEncoder(w).Encode([]honeycomb.DerivedColumn{{Alias: "is_good"}})
+			case "/1/derived_columns/__all__":
+				_ = json.NewEncoder(w).Encode([]honeycomb.DerivedColumn{{Alias: "is_slow"}})
+			case "/1/triggers/api":
+				_ = json.NewEncoder(w).Encode([]honeycomb.Trigger{
+					{
+						ID: "t1", Name: "Errors", Frequency: 300,
+						Query: &honeycomb.QuerySpec{
+							TimeRange:  3600,
+							Breakdowns: []string{"legacy_status"},
+							Filters:    []honeycomb.Filter{{Column: "removed_column", Op: "exists"}},
+						},
+					},
+					{ID: "t2", Name: "Old", Disabled: true},
+					{ID: "t3", Name: "Old", Disabled: true, Description: "Replaced by Errors"},
+				})
+			case "/1/slos/api":
+				_ = json.NewEncoder(w).Encode([]honeycomb.SLO{{ID: "s1", Name: "Availability", SLI: honeycomb.SLI{Alias: "gone"}}})
+			default:
+				t.Errorf("unexpected path %v", r.URL.Path)
+				http.Error(w, `{"error": "not found"}`, http.StatusNotFound)
+			}
+		}))
+		defer server.Close()
+
+		var buf bytes.Buffer
+		root := cmd.NewRootCommand()
+		root.SetOut(&buf)
+		root.SetArgs([]string{"lint", "--api-key", "test", "--api-url", server.URL})
+
+		err := root.Execute()
+		is.True(t, err != nil)
+		is.True(t, contains(err.Error(), "lint found 3 error(s) and 4 warning(s)"))
+
+		output := buf.String()
+		is.True(t, contains(output, `api/trigger "Errors": error: query refers to column "removed_column", which doesn't exist (missing-column)`))
+		is.True(t, contains(output, `(stale-column)`))
+		is.True(t, contains(output, `(time-range-too-long)`))
+		is.True(t, contains(output, `(no-recipients)`))
+		is.True(t, contains(output, `api/trigger "Old": warning: trigger is disabled without a description saying why (disabled-undocumented)`))
+		is.True(t, contains(output, `api/trigger "Old": warning: 2 triggers have this name (duplicate-name)`))
+		is.True(t, contains(output, `api/slo "Availability": error: SLI derived column "gone" doesn't exist (missing-sli)`))
+	})
+
+	t.Run("outputs findings as JSON", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch r.URL.Path {
+			case "/1/datasets":
+				_ = json.NewEncoder(w).Encode([]honeycomb.Dataset{{Name: "API", Slug: "api"}})
+			case "/1/columns/api":
+				_ = json.NewEncoder(w).Encode([]honeycomb.Column{
+					{KeyName: "status_code", LastWritten: time.Now().Format(time.RFC3339)},
+					{KeyName: "legacy_status", LastWritten: time.Now().AddDate(0, -3, 0).Format(time.RFC3339)},
+				})
+			case "/1/derived_columns/api":
+				_ = json.NewEncoder(w).Encode([]honeycomb.DerivedColumn{{Alias: "is_good"}})
+			case "/1/derived_columns/__all__":
+				_ = json.NewEncoder(w).Encode([]honeycomb.DerivedColumn{{Alias: "is_slow"}})
+			case "/1/triggers/api":
+				_ = json.NewEncoder(w).Encode([]honeycomb.Trigger{{
+					ID: "t1", Name: "Errors", Frequency: 900,
+					Query:      &honeycomb.QuerySpec{TimeRange: 300, Calculations: []honeycomb.Calculation{{Op: "COUNT"}}},
+					Recipients: []honeycomb.NotificationRecipient{{ID: "r1"}},
+				}})
+			case "/1/slos/api":
+				_, _ = w.Write([]byte(`[]`))
+			default:
+				t.Errorf("unexpected path %v", r.URL.Path)
+				http.Error(w, `{"error": "not found"}`, http.StatusNotFound)
+			}
+		}))
+		defer server.Close()
+
+		var buf bytes.Buffer
+		root := cmd.NewRootCommand()
+		root.SetOut(&buf)
+		root.SetArgs([]string{"lint", "--dataset", "api", "--json", "--fail-on", "error", "--api-key", "test", "--api-url", server.URL})
+
+		err := root.Execute()
+		is.NotError(t, err)
+
+		var findings []map[string]any
+		is.NotError(t, json.Unmarshal(buf.Bytes(), &findings))
+		is.Equal(t, 1, len(findings))
+		is.Equal(t, "time-range-gap", findings[0]["rule"])
+	})
+
+	t.Run("passes without problems", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch r.URL.Path {
+			case "/1/datasets":
+				_ = json.NewEncoder(w).Encode([]honeycomb.Dataset{{Name: "API", Slug: "api"}})
+			case "/1/columns/api":
+				_ = json.NewEncoder(w).Encode([]honeycomb.Column{
+					{KeyName: "status_code", LastWritten: time.Now().Format(time.RFC3339)},
+					{KeyName: "legacy_status", LastWritten: time.Now().AddDate(0, -3, 0).Format(time.RFC3339)},
+				})
+			case "/1/derived_columns/api":
+				_ = json.NewEncoder(w).Encode([]honeycomb.DerivedColumn{{Alias: "is_good"}})
+			case "/1/derived_columns/__all__":
+				_ = json.NewEncoder(w).Encode([]honeycomb.DerivedColumn{{Alias: "is_slow"}})
+			case "/1/triggers/api":
+				_ = json.NewEncoder(w).Encode([]honeycomb.Trigger{{
+					ID: "t1", Name: "Errors", Frequency: 900,
+					Query:      &honeycomb.QuerySpec{TimeRange: 900, Breakdowns: []string{"status_code", "is_good", "is_slow"}},
+					Recipients: []honeycomb.NotificationRecipient{{ID: "r1"}},
+				}})
+			case "/1/slos/api":
+				_ = json.NewEncoder(w).Encode([]honeycomb.SLO{
+					{ID: "s1", Name: "Availability", SLI: honeycomb.SLI{Alias: "is_good"}},
+					{ID: "s2", Name: "Latency", SLI: honeycomb.SLI{Alias: "is_slow"}},
+				})
+			default:
+				t.Errorf("unexpected path %v", r.URL.Path)
+				http.Error(w, `{"error": "not found"}`, http.StatusNotFound)
+			}
+		}))
+		defer server.Close()
+
+		var buf bytes.Buffer
+		root := cmd.NewRootCommand()
+		root.SetOut(&buf)
+		root.SetArgs([]string{"lint", "--api-key", "test", "--api-url", server.URL})
+
+		err := root.Execute()
+		is.NotError(t, err)
+		is.True(t, contains(buf.String(), "No problems found in 1 dataset(s)"))
+	})
+}
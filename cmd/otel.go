@@ -0,0 +1,95 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/spf13/cobra"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.41.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// tracerProviderContextKey is the context key for the command's [trace.TracerProvider], set when tracing is on.
+type tracerProviderContextKey struct{}
+
+// tracingEnabled with --otel, or with the standard OTEL_* environment variables for an OTLP trace exporter.
+// OTEL_SDK_DISABLED=true or OTEL_TRACES_EXPORTER=none always turn it off.
+func tracingEnabled(cmd *cobra.Command) bool {
+	if disabled, _ := strconv.ParseBool(os.Getenv("OTEL_SDK_DISABLED")); disabled {
+		return false
+	}
+	switch os.Getenv("OTEL_TRACES_EXPORTER") {
+	case "none":
+		return false
+	case "otlp":
+		return true
+	}
+	if enabled, _ := cmd.Flags().GetBool("otel"); enabled {
+		return true
+	}
+	if endpoint, _ := cmd.Flags().GetString("otel-endpoint"); endpoint != "" {
+		return true
+	}
+	return os.Getenv("OTEL_EXPORTER_OTLP_ENDPOINT") != "" || os.Getenv("OTEL_EXPORTER_OTLP_TRACES_ENDPOINT") != ""
+}
+
+// startTracing starts a span for the command, and exports it and the spans of its API requests over OTLP/HTTP.
+// The command's RunE is wrapped so that the span ends and the spans are flushed when it returns, failed or not.
+// Failing to export doesn't fail the command.
+func startTracing(cmd *cobra.Command) error {
+	var opts []otlptracehttp.Option
+	if endpoint, _ := cmd.Flags().GetString("otel-endpoint"); endpoint != "" {
+		opts = append(opts, otlptracehttp.WithEndpointURL(endpoint))
+	}
+	exporter, err := otlptracehttp.New(cmd.Context(), opts...)
+	if err != nil {
+		return fmt.Errorf("creating trace exporter: %w", err)
+	}
+
+	// Attributes from OTEL_SERVICE_NAME and OTEL_RESOURCE_ATTRIBUTES take precedence
+	res, err := resource.Merge(
+		resource.NewWithAttributes(semconv.SchemaURL, semconv.ServiceName("honeycomb-cli"), semconv.ServiceVersion(version)),
+		resource.Environment(),
+	)
+	if err != nil {
+		return fmt.Errorf("creating trace resource: %w", err)
+	}
+
+	tp := sdktrace.NewTracerProvider(sdktrace.WithBatcher(exporter), sdktrace.WithResource(res))
+
+	ctx, span := tp.Tracer("github.com/maragudk/honeycomb-cli/cmd").Start(cmd.Context(), cmd.CommandPath(),
+		trace.WithAttributes(attribute.String("cli.command", cmd.CommandPath()), attribute.Int("cli.args", len(cmd.Flags().Args()))))
+	cmd.SetContext(context.WithValue(ctx, tracerProviderContextKey{}, tp))
+
+	run := cmd.RunE
+	if run == nil {
+		run = func(cmd *cobra.Command, args []string) error {
+			cmd.Run(cmd, args)
+			return nil
+		}
+	}
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		err := run(cmd, args)
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+		}
+		span.End()
+
+		ctx, cancel := context.WithTimeout(context.WithoutCancel(cmd.Context()), 5*time.Second)
+		defer cancel()
+		if err := tp.Shutdown(ctx); err != nil {
+			fmt.Fprintf(cmd.ErrOrStderr(), "Error exporting traces: %v\n", err)
+		}
+		return err
+	}
+	return nil
+}
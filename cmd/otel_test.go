@@ -0,0 +1,147 @@
+package cmd_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	coltracepb "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+	tracepb "go.opentelemetry.io/proto/otlp/trace/v1"
+	"google.golang.org/protobuf/proto"
+	"maragu.dev/is"
+
+	"github.com/maragudk/honeycomb-cli/cmd"
+	"github.com/maragudk/honeycomb-cli/honeycomb"
+)
+
+func TestOtel(t *testing.T) {
+	t.Run("exports a span for the command with a child span per API request", func(t *testing.T) {
+		var lock sync.Mutex
+		var spans []*tracepb.Span
+		var serviceName string
+		receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			is.Equal(t, "/v1/traces", r.URL.Path)
+			body, _ := io.ReadAll(r.Body)
+			var req coltracepb.ExportTraceServiceRequest
+			is.NotError(t, proto.Unmarshal(body, &req))
+
+			lock.Lock()
+			defer lock.Unlock()
+			for _, rs := range req.ResourceSpans {
+				for _, attr := range rs.Resource.Attributes {
+					if attr.Key == "service.name" {
+						serviceName = attr.Value.GetStringValue()
+					}
+				}
+				for _, ss := range rs.ScopeSpans {
+					spans = append(spans, ss.Spans...)
+				}
+			}
+			w.Header().Set("Content-Type", "application/x-protobuf")
+			data, _ := proto.Marshal(&coltracepb.ExportTraceServiceResponse{})
+			_, _ = w.Write(data)
+		}))
+		defer receiver.Close()
+
+		api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_ = json.NewEncoder(w).Encode([]honeycomb.Dataset{{Name: "Requests", Slug: "requests"}})
+		}))
+		defer api.Close()
+
+		root := cmd.NewRootCommand()
+		root.SetOut(&bytes.Buffer{})
+		root.SetArgs([]string{"datasets", "list", "--otel", "--otel-endpoint", receiver.URL,
+			"--api-key", "test", "--api-url", api.URL})
+
+		err := root.Execute()
+		is.NotError(t, err)
+
+		lock.Lock()
+		defer lock.Unlock()
+		is.Equal(t, "honeycomb-cli", serviceName)
+		is.Equal(t, 2, len(spans))
+
+		request, command := spans[0], spans[1]
+		is.Equal(t, "GET /1/datasets", request.Name)
+		is.Equal(t, "honeycomb-cli datasets list", command.Name)
+		is.True(t, bytes.Equal(command.SpanId, request.ParentSpanId))
+		is.True(t, bytes.Equal(command.TraceId, request.TraceId))
+		is.Equal(t, tracepb.Status_STATUS_CODE_UNSET, command.Status.Code)
+	})
+
+	t.Run("marks the command span as an error when the command fails", func(t *testing.T) {
+		var lock sync.Mutex
+		var spans []*tracepb.Span
+		var serviceName string
+		receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			is.Equal(t, "/v1/traces", r.URL.Path)
+			body, _ := io.ReadAll(r.Body)
+			var req coltracepb.ExportTraceServiceRequest
+			is.NotError(t, proto.Unmarshal(body, &req))
+
+			lock.Lock()
+			defer lock.Unlock()
+			for _, rs := range req.ResourceSpans {
+				for _, attr := range rs.Resource.Attributes {
+					if attr.Key == "service.name" {
+						serviceName = attr.Value.GetStringValue()
+					}
+				}
+				for _, ss := range rs.ScopeSpans {
+					spans = append(spans, ss.Spans...)
+				}
+			}
+			w.Header().Set("Content-Type", "application/x-protobuf")
+			data, _ := proto.Marshal(&coltracepb.ExportTraceServiceResponse{})
+			_, _ = w.Write(data)
+		}))
+		defer receiver.Close()
+
+		api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			http.Error(w, `{"error": "unauthorized"}`, http.StatusUnauthorized)
+		}))
+		defer api.Close()
+		t.Setenv("OTEL_EXPORTER_OTLP_ENDPOINT", receiver.URL)
+		t.Setenv("OTEL_SERVICE_NAME", "nightly-export")
+
+		root := cmd.NewRootCommand()
+		root.SetOut(&bytes.Buffer{})
+		root.SetArgs([]string{"datasets", "list", "--api-key", "test", "--api-url", api.URL})
+
+		err := root.Execute()
+		is.True(t, err != nil)
+
+		lock.Lock()
+		defer lock.Unlock()
+		is.Equal(t, "nightly-export", serviceName)
+		is.Equal(t, 2, len(spans))
+		is.Equal(t, tracepb.Status_STATUS_CODE_ERROR, spans[0].Status.Code)
+		is.Equal(t, tracepb.Status_STATUS_CODE_ERROR, spans[1].Status.Code)
+	})
+
+	t.Run("doesn't export with tracing turned off", func(t *testing.T) {
+		receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			t.Errorf("unexpected request %v %v", r.Method, r.URL.Path)
+			http.Error(w, `{"error": "not found"}`, http.StatusNotFound)
+		}))
+		defer receiver.Close()
+
+		api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_ = json.NewEncoder(w).Encode([]honeycomb.Dataset{{Name: "Requests", Slug: "requests"}})
+		}))
+		defer api.Close()
+		t.Setenv("OTEL_EXPORTER_OTLP_ENDPOINT", receiver.URL)
+		t.Setenv("OTEL_SDK_DISABLED", "true")
+
+		root := cmd.NewRootCommand()
+		root.SetOut(&bytes.Buffer{})
+		root.SetArgs([]string{"datasets", "list", "--otel", "--api-key", "test", "--api-url", api.URL})
+
+		err := root.Execute()
+		is.NotError(t, err)
+	})
+}
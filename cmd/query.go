@@ -1,8 +1,12 @@
 package cmd
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"maps"
+	"slices"
 	"strings"
 	"text/tabwriter"
 
@@ -28,75 +32,265 @@ Examples:
   honeycomb-cli query --dataset requests --calculation "P99:duration_ms" --filter "status_code = 200"
 
   # Multiple calculations
-  honeycomb-cli query --dataset requests --calculation COUNT --calculation "AVG:duration_ms"`,
+  honeycomb-cli query --dataset requests --calculation COUNT --calculation "AVG:duration_ms"
+
+  # The same query in several datasets, with a dataset column in the results
+  honeycomb-cli query --dataset api,web,worker --calculation "P99:duration_ms"
+
+  # The same query in every dataset, or once across the whole environment
+  honeycomb-cli query --all-datasets --calculation COUNT
+  honeycomb-cli query --dataset __all__ --calculation COUNT
+
+  # Compare the last 2 hours with the same 2 hours yesterday
+  honeycomb-cli query --dataset requests --calculation "P99:duration_ms" --breakdown service --compare 1d
+
+  # Re-run the query every 30 seconds during a deploy, highlighting changed values
+  honeycomb-cli query --dataset requests --calculation COUNT --breakdown status_code --time-range 600 --watch 30s
+
+  # Save the graph image for an incident timeline
+  honeycomb-cli query --dataset requests --calculation "P99:duration_ms" --graph-image latency.png --graph-width 1200
+
+  # Reuse results of identical queries from the last 10 minutes, like in a shell loop
+  honeycomb-cli query --dataset requests --calculation COUNT --cache --cache-ttl 10m
+
+  # Define a query, run it, and fetch its result in separate steps
+  honeycomb-cli query create --dataset requests --calculation COUNT
+  honeycomb-cli query run-id <query-id> --dataset requests
+  honeycomb-cli query result <result-id> --dataset requests --wait`,
+		Args: cobra.NoArgs,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			c := newClient(cmd)
-			dataset, _ := cmd.Flags().GetString("dataset")
-			calcs, _ := cmd.Flags().GetStringSlice("calculation")
-			breakdowns, _ := cmd.Flags().GetStringSlice("breakdown")
-			filters, _ := cmd.Flags().GetStringSlice("filter")
-			timeRange, _ := cmd.Flags().GetInt("time-range")
-			limit, _ := cmd.Flags().GetInt("limit")
-
-			spec := honeycomb.QuerySpec{
-				TimeRange: timeRange,
-			}
+			datasets, _ := cmd.Flags().GetStringSlice("dataset")
+			allDatasets, _ := cmd.Flags().GetBool("all-datasets")
+			concurrency, _ := cmd.Flags().GetInt("concurrency")
 
-			if limit > 0 {
-				spec.Limit = limit
+			spec, err := querySpecFromFlags(cmd)
+			if err != nil {
+				return err
 			}
 
-			for _, calc := range calcs {
-				c, err := ParseCalculation(calc)
+			if compare, _ := cmd.Flags().GetString("compare"); compare != "" {
+				offset, err := parseLookback(compare)
 				if err != nil {
 					return err
 				}
-				spec.Calculations = append(spec.Calculations, c)
+				if allDatasets || len(datasets) != 1 {
+					return fmt.Errorf("--compare needs a single dataset")
+				}
+				return runQueryComparison(cmd, c, datasets[0], spec, offset)
 			}
 
-			if len(spec.Calculations) == 0 {
-				spec.Calculations = []honeycomb.Calculation{{Op: "COUNT"}}
+			if graphImage, _ := cmd.Flags().GetString("graph-image"); graphImage != "" && (allDatasets || len(datasets) != 1) {
+				return fmt.Errorf("--graph-image needs a single dataset")
 			}
 
-			spec.Breakdowns = breakdowns
-
-			for _, f := range filters {
-				filter, err := ParseFilter(f)
+			if allDatasets {
+				all, err := c.ListDatasets(cmd.Context())
 				if err != nil {
 					return err
 				}
-				spec.Filters = append(spec.Filters, filter)
+				datasets = nil
+				for _, d := range all {
+					datasets = append(datasets, d.Slug)
+				}
+			}
+
+			if watch, _ := cmd.Flags().GetString("watch"); watch != "" {
+				interval, err := parseLookback(watch)
+				if err != nil {
+					return fmt.Errorf("invalid --watch interval %q (use e.g. 30s, 1m, or 1d)", watch)
+				}
+				// Cached results would hide the changes being watched for
+				c = newClient(cmd, honeycomb.WithQueryCache(nil))
+				return watchQuery(cmd, c, datasets, spec, concurrency, interval)
 			}
 
-			result, err := c.RunQuery(cmd.Context(), dataset, spec)
+			spin := newSpinner(cmd.ErrOrStderr())
+			c = newClient(cmd, honeycomb.WithRunQueryOptions(runQueryOptions(cmd, spin.progress)))
+			rows, results, err := queryRows(cmd.Context(), c, datasets, spec, concurrency)
+			spin.stop()
 			if err != nil {
 				return err
 			}
+			failures := queryFailures(results)
+			for _, err := range failures {
+				fmt.Fprintf(cmd.ErrOrStderr(), "Error %v\n", err)
+			}
 
-			asJSON, _ := cmd.Flags().GetBool("json")
-			if asJSON {
-				return json.NewEncoder(cmd.OutOrStdout()).Encode(result.Data.Results)
+			if err := outputQueryResults(cmd, rows); err != nil {
+				return err
 			}
 
-			if len(result.Data.Results) == 0 {
-				fmt.Fprintln(cmd.OutOrStdout(), "No results.")
-				return nil
+			if graphImage, _ := cmd.Flags().GetString("graph-image"); graphImage != "" && len(results) == 1 && results[0].Err == nil {
+				if err := saveGraphImageFromFlags(cmd, c, results[0].Result); err != nil {
+					return err
+				}
 			}
 
-			return printQueryResults(cmd, result.Data.Results)
+			if err := showLinks(cmd, c, func(l *honeycomb.UILinks) ([]uiLink, error) {
+				var links []uiLink
+				for _, r := range results {
+					if r.Err != nil {
+						continue
+					}
+					label := "Query result"
+					if len(results) > 1 {
+						label = fmt.Sprintf("Query result (%v)", r.Dataset)
+					}
+					links = append(links, queryResultLinks(l, label, r.Dataset, r.Result)...)
+				}
+				return links, nil
+			}); err != nil {
+				return err
+			}
+
+			if len(failures) > 0 {
+				return fmt.Errorf("query failed in %v of %v datasets", len(failures), len(datasets))
+			}
+			return nil
 		},
 	}
 
-	cmd.Flags().String("dataset", "", "Dataset slug (required)")
-	_ = cmd.MarkFlagRequired("dataset")
+	cmd.Flags().StringSlice("dataset", nil, "Dataset slugs, comma-separated (use __all__ for an environment-wide query)")
+	cmd.Flags().Bool("all-datasets", false, "Run the query in every dataset")
+	cmd.MarkFlagsOneRequired("dataset", "all-datasets")
+	cmd.MarkFlagsMutuallyExclusive("dataset", "all-datasets")
+	cmd.Flags().Int("concurrency", 4, "Maximum number of datasets to query at the same time")
+	cmd.Flags().String("compare", "", "Compare with the same time range this long ago (e.g. 1d, 1w)")
+	cmd.Flags().Float64("significance", 20, "Mark changes of at least this many percent with --compare")
+	cmd.Flags().String("watch", "", "Re-run the query on this interval until interrupted (e.g. 30s, 1m, or 1d)")
+	cmd.MarkFlagsMutuallyExclusive("watch", "compare")
+	addQuerySpecFlags(cmd)
+	cmd.Flags().Bool("json", false, "Output as JSON")
+	addLinkFlags(cmd)
+	addGraphImageFlags(cmd)
+	for _, mode := range []string{"watch", "compare"} {
+		cmd.MarkFlagsMutuallyExclusive(mode, "link")
+		cmd.MarkFlagsMutuallyExclusive(mode, "open")
+		cmd.MarkFlagsMutuallyExclusive(mode, "graph-image")
+	}
+
+	cmd.AddCommand(newQueryCreateCommand())
+	cmd.AddCommand(newQueryRunIDCommand())
+	cmd.AddCommand(newQueryResultCommand())
+
+	return cmd
+}
+
+// addQuerySpecFlags adds the flags read by [querySpecFromFlags].
+func addQuerySpecFlags(cmd *cobra.Command) {
 	cmd.Flags().StringSlice("calculation", nil, "Calculation (e.g. COUNT, AVG:column, P99:column)")
 	cmd.Flags().StringSlice("breakdown", nil, "Breakdown column")
 	cmd.Flags().StringSlice("filter", nil, "Filter (e.g. \"status_code = 200\")")
 	cmd.Flags().Int("time-range", 7200, "Time range in seconds (default 2 hours)")
 	cmd.Flags().Int("limit", 0, "Maximum number of results")
-	cmd.Flags().Bool("json", false, "Output as JSON")
+}
 
-	return cmd
+// querySpecFromFlags builds a query spec from the calculation, breakdown, filter, time range, and limit flags.
+// Without calculations, the query counts events.
+func querySpecFromFlags(cmd *cobra.Command) (honeycomb.QuerySpec, error) {
+	calcs, _ := cmd.Flags().GetStringSlice("calculation")
+	breakdowns, _ := cmd.Flags().GetStringSlice("breakdown")
+	filters, _ := cmd.Flags().GetStringSlice("filter")
+	timeRange, _ := cmd.Flags().GetInt("time-range")
+	limit, _ := cmd.Flags().GetInt("limit")
+
+	spec := honeycomb.QuerySpec{
+		TimeRange: timeRange,
+	}
+
+	if limit > 0 {
+		spec.Limit = limit
+	}
+
+	for _, calc := range calcs {
+		c, err := ParseCalculation(calc)
+		if err != nil {
+			return spec, err
+		}
+		spec.Calculations = append(spec.Calculations, c)
+	}
+
+	if len(spec.Calculations) == 0 {
+		spec.Calculations = []honeycomb.Calculation{{Op: "COUNT"}}
+	}
+
+	spec.Breakdowns = breakdowns
+
+	for _, f := range filters {
+		filter, err := ParseFilter(f)
+		if err != nil {
+			return spec, err
+		}
+		spec.Filters = append(spec.Filters, filter)
+	}
+
+	return spec, nil
+}
+
+// outputQueryResults as JSON with the --json flag, or else as a table.
+func outputQueryResults(cmd *cobra.Command, rows []map[string]any) error {
+	asJSON, _ := cmd.Flags().GetBool("json")
+	if asJSON {
+		return json.NewEncoder(cmd.OutOrStdout()).Encode(rows)
+	}
+	if len(rows) == 0 {
+		fmt.Fprintln(cmd.OutOrStdout(), "No results.")
+		return nil
+	}
+	return printQueryResults(cmd, rows)
+}
+
+// withResumeHint adds how to fetch the query result later to query timeout errors.
+func withResumeHint(err error) error {
+	var timeoutErr *honeycomb.QueryTimeoutError
+	if errors.As(err, &timeoutErr) {
+		return fmt.Errorf("%w (fetch it later with: honeycomb-cli query result %v --dataset %v --wait)",
+			err, timeoutErr.ResultID, timeoutErr.Dataset)
+	}
+	return err
+}
+
+// queryRows runs the query in the datasets, and returns the result rows and the result of each dataset.
+// With a single dataset, the rows are the query results as they are, and an error is returned if the query fails.
+// With several, each row gets a dataset column, and failed datasets have their error in the dataset's result,
+// so the rows from the other datasets are still returned.
+func queryRows(ctx context.Context, c *honeycomb.Client, datasets []string, spec honeycomb.QuerySpec,
+	concurrency int) ([]map[string]any, []honeycomb.DatasetQueryResult, error) {
+	if len(datasets) == 1 {
+		result, err := c.RunQuery(ctx, datasets[0], spec)
+		if err != nil {
+			return nil, nil, withResumeHint(err)
+		}
+		return result.Data.Results, []honeycomb.DatasetQueryResult{{Dataset: datasets[0], Result: result}}, nil
+	}
+
+	rows := []map[string]any{}
+	results := c.RunQueries(ctx, datasets, spec, concurrency)
+	for _, r := range results {
+		if r.Err != nil {
+			continue
+		}
+		for _, row := range r.Result.Data.Results {
+			merged := map[string]any{"dataset": r.Dataset}
+			for k, v := range row {
+				merged[k] = v
+			}
+			rows = append(rows, merged)
+		}
+	}
+	return rows, results, nil
+}
+
+// queryFailures are the errors of the datasets where the query failed.
+func queryFailures(results []honeycomb.DatasetQueryResult) []error {
+	var failures []error
+	for _, r := range results {
+		if r.Err != nil {
+			failures = append(failures, fmt.Errorf("querying %v: %w", r.Dataset, withResumeHint(r.Err)))
+		}
+	}
+	return failures
 }
 
 // ParseCalculation from a string like "COUNT" or "AVG:duration_ms".
@@ -153,20 +347,44 @@ func printQueryResults(cmd *cobra.Command, results []map[string]any) error {
 		return nil
 	}
 
-	// Collect column headers from first result
-	var headers []string
-	for key := range results[0] {
-		headers = append(headers, key)
-	}
-
 	w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 2, ' ', 0)
+	headers := queryHeaders(results)
 	fmt.Fprintln(w, strings.Join(headers, "\t"))
 	for _, row := range results {
-		var vals []string
-		for _, h := range headers {
-			vals = append(vals, fmt.Sprint(row[h]))
-		}
-		fmt.Fprintln(w, strings.Join(vals, "\t"))
+		fmt.Fprintln(w, strings.Join(queryCells(row, headers), "\t"))
 	}
 	return w.Flush()
 }
+
+// queryHeaders collects column headers from all results, sorted, with the dataset column first.
+func queryHeaders(results []map[string]any) []string {
+	seen := map[string]bool{}
+	for _, row := range results {
+		for key := range row {
+			seen[key] = true
+		}
+	}
+	return slices.SortedFunc(maps.Keys(seen), func(a, b string) int {
+		if (a == "dataset") != (b == "dataset") {
+			if a == "dataset" {
+				return -1
+			}
+			return 1
+		}
+		return strings.Compare(a, b)
+	})
+}
+
+// queryCells formats a result row's values in header order, with blanks for missing values.
+func queryCells(row map[string]any, headers []string) []string {
+	var cells []string
+	for _, h := range headers {
+		v, ok := row[h]
+		if !ok {
+			cells = append(cells, "")
+			continue
+		}
+		cells = append(cells, fmt.Sprint(v))
+	}
+	return cells
+}
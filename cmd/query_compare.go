@@ -0,0 +1,242 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/maragudk/honeycomb-cli/honeycomb"
+)
+
+// comparisonRow is a breakdown group with its calculation values in the current and previous windows.
+type comparisonRow struct {
+	Group  map[string]any    `json:"group"`
+	Values []comparisonValue `json:"values"`
+}
+
+// comparisonValue is a calculation's value in both windows. Current or Previous is nil when the group
+// only has results in the other window.
+type comparisonValue struct {
+	Calculation string   `json:"calculation"`
+	Current     *float64 `json:"current"`
+	Previous    *float64 `json:"previous"`
+	Change      *float64 `json:"change"`
+	// PercentChange is nil when the previous value is missing or zero.
+	PercentChange *float64 `json:"percent_change"`
+}
+
+// significant reports whether the value changed by at least the given percentage, or appeared or disappeared.
+func (v comparisonValue) significant(percent float64) bool {
+	if (v.Current == nil) != (v.Previous == nil) {
+		return true
+	}
+	return v.PercentChange != nil && math.Abs(*v.PercentChange) >= percent
+}
+
+// compareResults joins current and previous results on the breakdown values, in the order of the current results,
+// followed by groups only in the previous results.
+func compareResults(current, previous []map[string]any, breakdowns, calculations []string) []comparisonRow {
+	groupKey := func(row map[string]any) string {
+		var parts []string
+		for _, b := range breakdowns {
+			parts = append(parts, fmt.Sprint(row[b]))
+		}
+		return strings.Join(parts, "\x00")
+	}
+
+	previousByKey := map[string]map[string]any{}
+	for _, row := range previous {
+		previousByKey[groupKey(row)] = row
+	}
+
+	var rows []comparisonRow
+	seen := map[string]bool{}
+	add := func(cur, prev map[string]any) {
+		source := cur
+		if source == nil {
+			source = prev
+		}
+		row := comparisonRow{Group: map[string]any{}}
+		for _, b := range breakdowns {
+			row.Group[b] = source[b]
+		}
+		for _, calc := range calculations {
+			v := comparisonValue{Calculation: calc, Current: numberValue(cur, calc), Previous: numberValue(prev, calc)}
+			if v.Current != nil && v.Previous != nil {
+				change := *v.Current - *v.Previous
+				v.Change = &change
+				if *v.Previous != 0 {
+					percent := 100 * change / math.Abs(*v.Previous)
+					v.PercentChange = &percent
+				}
+			}
+			row.Values = append(row.Values, v)
+		}
+		rows = append(rows, row)
+	}
+
+	for _, cur := range current {
+		key := groupKey(cur)
+		seen[key] = true
+		add(cur, previousByKey[key])
+	}
+	for _, prev := range previous {
+		if key := groupKey(prev); !seen[key] {
+			seen[key] = true
+			add(nil, prev)
+		}
+	}
+	return rows
+}
+
+func numberValue(row map[string]any, key string) *float64 {
+	if v, ok := row[key].(float64); ok {
+		return &v
+	}
+	return nil
+}
+
+// runQueryComparison runs the query for the time range ending now and for the same range offset into the past,
+// and prints the changes per breakdown group.
+func runQueryComparison(cmd *cobra.Command, c *honeycomb.Client, dataset string, spec honeycomb.QuerySpec, offset time.Duration) error {
+	significance, _ := cmd.Flags().GetFloat64("significance")
+	asJSON, _ := cmd.Flags().GetBool("json")
+
+	timeRange := time.Duration(spec.TimeRange) * time.Second
+	end := time.Now().Truncate(time.Second)
+
+	current := spec
+	current.TimeRange = 0
+	current.StartTime = end.Add(-timeRange).Unix()
+	current.EndTime = end.Unix()
+
+	previous := current
+	previous.StartTime = end.Add(-timeRange - offset).Unix()
+	previous.EndTime = end.Add(-offset).Unix()
+
+	spin := newSpinner(cmd.ErrOrStderr())
+	opts := runQueryOptions(cmd, spin.progress)
+	currentResult, err := c.RunQueryWithOptions(cmd.Context(), dataset, current, opts)
+	if err != nil {
+		spin.stop()
+		return fmt.Errorf("current window: %w", err)
+	}
+	previousResult, err := c.RunQueryWithOptions(cmd.Context(), dataset, previous, opts)
+	spin.stop()
+	if err != nil {
+		return fmt.Errorf("previous window: %w", err)
+	}
+
+	var calculations []string
+	for _, calc := range spec.Calculations {
+		calculations = append(calculations, calc.Name())
+	}
+	rows := compareResults(currentResult.Data.Results, previousResult.Data.Results, spec.Breakdowns, calculations)
+
+	if asJSON {
+		if rows == nil {
+			rows = []comparisonRow{}
+		}
+		return json.NewEncoder(cmd.OutOrStdout()).Encode(rows)
+	}
+
+	if len(rows) == 0 {
+		fmt.Fprintln(cmd.OutOrStdout(), "No results.")
+		return nil
+	}
+
+	fmt.Fprintf(cmd.OutOrStdout(), "Comparing %v to %v earlier (changes of %v%% or more are marked with *)\n\n",
+		formatDuration(timeRange), formatDuration(offset), significance)
+
+	var buf bytes.Buffer
+	w := tabwriter.NewWriter(&buf, 0, 0, 2, ' ', 0)
+	headers := append([]string{}, spec.Breakdowns...)
+	for _, calc := range calculations {
+		headers = append(headers, calc, "PREVIOUS", "CHANGE", "CHANGE %")
+	}
+	fmt.Fprintln(w, strings.Join(headers, "\t")+"\t")
+
+	var significantRows []bool
+	for _, row := range rows {
+		var cells []string
+		for _, b := range spec.Breakdowns {
+			cells = append(cells, fmt.Sprint(row.Group[b]))
+		}
+		significant := false
+		for _, v := range row.Values {
+			cells = append(cells, formatNumber(v.Current), formatNumber(v.Previous), formatChange(v.Change), formatPercentChange(v))
+			significant = significant || v.significant(significance)
+		}
+		marker := ""
+		if significant {
+			marker = "*"
+		}
+		fmt.Fprintln(w, strings.Join(cells, "\t")+"\t"+marker)
+		significantRows = append(significantRows, significant)
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	h := newHighlighter(cmd.OutOrStdout())
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	for i, line := range lines {
+		if i > 0 && significantRows[i-1] {
+			line = h.highlight(line)
+		}
+		fmt.Fprintln(cmd.OutOrStdout(), line)
+	}
+	return nil
+}
+
+func formatNumber(v *float64) string {
+	if v == nil {
+		return "-"
+	}
+	return fmt.Sprint(math.Round(*v*100) / 100)
+}
+
+func formatChange(v *float64) string {
+	if v == nil {
+		return "-"
+	}
+	rounded := math.Round(*v*100) / 100
+	if rounded > 0 {
+		return fmt.Sprintf("+%v", rounded)
+	}
+	return fmt.Sprint(rounded)
+}
+
+func formatPercentChange(v comparisonValue) string {
+	switch {
+	case v.Previous == nil && v.Current != nil:
+		return "new"
+	case v.Current == nil && v.Previous != nil:
+		return "gone"
+	case v.PercentChange == nil:
+		return "-"
+	case *v.PercentChange > 0:
+		return fmt.Sprintf("+%.1f%%", *v.PercentChange)
+	default:
+		return fmt.Sprintf("%.1f%%", *v.PercentChange)
+	}
+}
+
+// formatDuration like "2h", "1d", or "1w" when it's a whole number of the unit.
+func formatDuration(d time.Duration) string {
+	for _, u := range []struct {
+		unit   time.Duration
+		suffix string
+	}{{7 * 24 * time.Hour, "w"}, {24 * time.Hour, "d"}, {time.Hour, "h"}, {time.Minute, "m"}} {
+		if d >= u.unit && d%u.unit == 0 {
+			return fmt.Sprintf("%v%v", int64(d/u.unit), u.suffix)
+		}
+	}
+	return d.String()
+}
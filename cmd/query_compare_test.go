@@ -0,0 +1,165 @@
+package cmd_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"maragu.dev/is"
+
+	"github.com/maragudk/honeycomb-cli/cmd"
+	"github.com/maragudk/honeycomb-cli/honeycomb"
+)
+
+func TestQueryCommand_Compare(t *testing.T) {
+	current := []map[string]any{
+		{"service": "api", "COUNT": float64(150)},
+		{"service": "web", "COUNT": float64(100)},
+		{"service": "jobs", "COUNT": float64(5)},
+	}
+	previous := []map[string]any{
+		{"service": "api", "COUNT": float64(100)},
+		{"service": "web", "COUNT": float64(95)},
+		{"service": "cron", "COUNT": float64(3)},
+	}
+
+	t.Run("compares with the same time range a day earlier", func(t *testing.T) {
+		// The first query gets the current results and the second the previous results
+		var mu sync.Mutex
+		var specs []honeycomb.QuerySpec
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			mu.Lock()
+			defer mu.Unlock()
+			switch {
+			case r.Method == http.MethodPost && r.URL.Path == "/1/queries/requests":
+				var spec honeycomb.QuerySpec
+				_ = json.NewDecoder(r.Body).Decode(&spec)
+				specs = append(specs, spec)
+				_ = json.NewEncoder(w).Encode(honeycomb.QueryResponse{ID: "q1"})
+
+			case r.Method == http.MethodPost && r.URL.Path == "/1/query_results/requests":
+				result := honeycomb.QueryResult{ID: "r1", Complete: true}
+				result.Data.Results = current
+				if len(specs) > 1 {
+					result.Data.Results = previous
+				}
+				_ = json.NewEncoder(w).Encode(result)
+			}
+		}))
+		defer server.Close()
+
+		var buf bytes.Buffer
+		root := cmd.NewRootCommand()
+		root.SetOut(&buf)
+		root.SetArgs([]string{"query", "--dataset", "requests", "--calculation", "COUNT", "--breakdown", "service",
+			"--time-range", "3600", "--compare", "1d", "--api-key", "test", "--api-url", server.URL})
+
+		err := root.Execute()
+		is.NotError(t, err)
+
+		is.Equal(t, 2, len(specs))
+		cur, prev := specs[0], specs[1]
+		is.Equal(t, 0, cur.TimeRange)
+		is.Equal(t, int64(3600), cur.EndTime-cur.StartTime)
+		is.Equal(t, cur.EndTime-86400, prev.EndTime)
+		is.Equal(t, cur.StartTime-86400, prev.StartTime)
+
+		output := buf.String()
+		is.True(t, contains(output, "Comparing 1h to 1d earlier"))
+		is.True(t, contains(output, "+50.0%"))
+		is.True(t, contains(output, "new"))
+		is.True(t, contains(output, "gone"))
+
+		for _, line := range strings.Split(output, "\n") {
+			switch {
+			case strings.HasPrefix(line, "api "), strings.HasPrefix(line, "jobs "), strings.HasPrefix(line, "cron "):
+				is.True(t, strings.HasSuffix(strings.TrimSpace(line), "*"))
+			case strings.HasPrefix(line, "web "):
+				is.True(t, !strings.HasSuffix(strings.TrimSpace(line), "*"))
+			}
+		}
+	})
+
+	t.Run("outputs changes as JSON", func(t *testing.T) {
+		var mu sync.Mutex
+		var queries int
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			mu.Lock()
+			defer mu.Unlock()
+			switch {
+			case r.Method == http.MethodPost && r.URL.Path == "/1/queries/requests":
+				queries++
+				_ = json.NewEncoder(w).Encode(honeycomb.QueryResponse{ID: "q1"})
+
+			case r.Method == http.MethodPost && r.URL.Path == "/1/query_results/requests":
+				result := honeycomb.QueryResult{ID: "r1", Complete: true}
+				result.Data.Results = current
+				if queries > 1 {
+					result.Data.Results = previous
+				}
+				_ = json.NewEncoder(w).Encode(result)
+			}
+		}))
+		defer server.Close()
+
+		var buf bytes.Buffer
+		root := cmd.NewRootCommand()
+		root.SetOut(&buf)
+		root.SetArgs([]string{"query", "--dataset", "requests", "--calculation", "COUNT", "--breakdown", "service",
+			"--compare", "1w", "--json", "--api-key", "test", "--api-url", server.URL})
+
+		err := root.Execute()
+		is.NotError(t, err)
+
+		var rows []struct {
+			Group  map[string]any `json:"group"`
+			Values []struct {
+				Calculation   string   `json:"calculation"`
+				Current       *float64 `json:"current"`
+				Previous      *float64 `json:"previous"`
+				Change        *float64 `json:"change"`
+				PercentChange *float64 `json:"percent_change"`
+			} `json:"values"`
+		}
+		is.NotError(t, json.Unmarshal(buf.Bytes(), &rows))
+		is.Equal(t, 4, len(rows))
+
+		is.Equal(t, "api", rows[0].Group["service"].(string))
+		is.Equal(t, "COUNT", rows[0].Values[0].Calculation)
+		is.Equal(t, 50.0, *rows[0].Values[0].Change)
+		is.Equal(t, 50.0, *rows[0].Values[0].PercentChange)
+
+		is.Equal(t, "jobs", rows[2].Group["service"].(string))
+		is.True(t, rows[2].Values[0].Previous == nil)
+
+		is.Equal(t, "cron", rows[3].Group["service"].(string))
+		is.True(t, rows[3].Values[0].Current == nil)
+	})
+
+	t.Run("errors with several datasets", func(t *testing.T) {
+		root := cmd.NewRootCommand()
+		root.SetOut(&bytes.Buffer{})
+		root.SetErr(&bytes.Buffer{})
+		root.SetArgs([]string{"query", "--dataset", "a,b", "--calculation", "COUNT",
+			"--compare", "1d", "--api-key", "test", "--api-url", "http://localhost"})
+
+		err := root.Execute()
+		is.True(t, err != nil)
+		is.True(t, contains(err.Error(), "single dataset"))
+	})
+
+	t.Run("errors on an invalid offset", func(t *testing.T) {
+		root := cmd.NewRootCommand()
+		root.SetOut(&bytes.Buffer{})
+		root.SetErr(&bytes.Buffer{})
+		root.SetArgs([]string{"query", "--dataset", "requests", "--calculation", "COUNT",
+			"--compare", "yesterday", "--api-key", "test", "--api-url", "http://localhost"})
+
+		err := root.Execute()
+		is.True(t, err != nil)
+	})
+}
@@ -0,0 +1,158 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/maragudk/honeycomb-cli/honeycomb"
+)
+
+func newQueryCreateCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "create",
+		Short: "Define a query without running it",
+		Long: `Define a query without running it, and print its ID and spec.
+Run it later with "query run-id".
+
+Example:
+  honeycomb-cli query create --dataset requests --calculation "P99:duration_ms" --breakdown service`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c := newClient(cmd)
+			dataset, _ := cmd.Flags().GetString("dataset")
+
+			spec, err := querySpecFromFlags(cmd)
+			if err != nil {
+				return err
+			}
+
+			query, err := c.CreateQuery(cmd.Context(), dataset, spec)
+			if err != nil {
+				return err
+			}
+
+			asJSON, _ := cmd.Flags().GetBool("json")
+			if asJSON {
+				if err := json.NewEncoder(cmd.OutOrStdout()).Encode(honeycomb.QueryResponse{ID: query.ID, Spec: spec}); err != nil {
+					return err
+				}
+			} else {
+				data, err := json.MarshalIndent(spec, "", "  ")
+				if err != nil {
+					return err
+				}
+				fmt.Fprintf(cmd.OutOrStdout(), "Created query %v\n%v\n", query.ID, string(data))
+			}
+
+			return showLinks(cmd, c, func(l *honeycomb.UILinks) ([]uiLink, error) {
+				u, err := l.Query(dataset, spec)
+				return []uiLink{{Label: "Query", URL: u}}, err
+			})
+		},
+	}
+	cmd.Flags().String("dataset", "", "Dataset slug (required, use __all__ for environment-wide)")
+	_ = cmd.MarkFlagRequired("dataset")
+	addQuerySpecFlags(cmd)
+	cmd.Flags().Bool("json", false, "Output the ID and spec as JSON")
+	addLinkFlags(cmd)
+	return cmd
+}
+
+func newQueryRunIDCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "run-id <query-id>",
+		Short: "Run a previously defined query",
+		Long: `Run a query defined with "query create", or any other query ID, and display the results.
+
+Example:
+  honeycomb-cli query run-id abc123 --dataset requests`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c := newClient(cmd)
+			dataset, _ := cmd.Flags().GetString("dataset")
+
+			spin := newSpinner(cmd.ErrOrStderr())
+			result, err := c.ExecuteQuery(cmd.Context(), dataset, args[0], runQueryOptions(cmd, spin.progress))
+			spin.stop()
+			if err != nil {
+				return withResumeHint(err)
+			}
+
+			if err := outputQueryResults(cmd, result.Data.Results); err != nil {
+				return err
+			}
+
+			if err := saveGraphImageFromFlags(cmd, c, result); err != nil {
+				return err
+			}
+
+			return showLinks(cmd, c, func(l *honeycomb.UILinks) ([]uiLink, error) {
+				return queryResultLinks(l, "Query result", dataset, result), nil
+			})
+		},
+	}
+	cmd.Flags().String("dataset", "", "Dataset slug (required, use __all__ for environment-wide)")
+	_ = cmd.MarkFlagRequired("dataset")
+	cmd.Flags().Bool("json", false, "Output as JSON")
+	addLinkFlags(cmd)
+	addGraphImageFlags(cmd)
+	return cmd
+}
+
+func newQueryResultCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "result <result-id>",
+		Short: "Fetch the result of a query that has already run",
+		Long: `Fetch the result of a query that has already run, like one that timed out.
+With --wait, poll until the result is complete, instead of failing if it isn't.
+
+Example:
+  honeycomb-cli query result xyz789 --dataset requests --wait`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c := newClient(cmd)
+			dataset, _ := cmd.Flags().GetString("dataset")
+			wait, _ := cmd.Flags().GetBool("wait")
+
+			var result *honeycomb.QueryResult
+			var err error
+			if wait {
+				spin := newSpinner(cmd.ErrOrStderr())
+				result, err = c.WaitForQueryResult(cmd.Context(), dataset, args[0], runQueryOptions(cmd, spin.progress))
+				spin.stop()
+				if err != nil {
+					return withResumeHint(err)
+				}
+			} else {
+				result, err = c.GetQueryResult(cmd.Context(), dataset, args[0])
+				if err != nil {
+					return err
+				}
+				if !result.Complete {
+					return fmt.Errorf("query result %v isn't complete yet (use --wait to wait for it)", result.ID)
+				}
+			}
+
+			if err := outputQueryResults(cmd, result.Data.Results); err != nil {
+				return err
+			}
+
+			if err := saveGraphImageFromFlags(cmd, c, result); err != nil {
+				return err
+			}
+
+			return showLinks(cmd, c, func(l *honeycomb.UILinks) ([]uiLink, error) {
+				return queryResultLinks(l, "Query result", dataset, result), nil
+			})
+		},
+	}
+	cmd.Flags().String("dataset", "", "Dataset slug (required, use __all__ for environment-wide)")
+	_ = cmd.MarkFlagRequired("dataset")
+	cmd.Flags().Bool("wait", false, "Wait for the result to complete")
+	cmd.Flags().Bool("json", false, "Output as JSON")
+	addLinkFlags(cmd)
+	addGraphImageFlags(cmd)
+	return cmd
+}
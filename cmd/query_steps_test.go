@@ -0,0 +1,213 @@
+package cmd_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"maragu.dev/is"
+
+	"github.com/maragudk/honeycomb-cli/cmd"
+	"github.com/maragudk/honeycomb-cli/honeycomb"
+)
+
+func TestQueryCreateCommand(t *testing.T) {
+	t.Run("creates a query and prints its ID and spec", func(t *testing.T) {
+		var created honeycomb.QuerySpec
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch {
+			case r.Method == http.MethodPost && r.URL.Path == "/1/queries/requests":
+				_ = json.NewDecoder(r.Body).Decode(&created)
+				_ = json.NewEncoder(w).Encode(honeycomb.QueryResponse{ID: "q1"})
+
+			default:
+				http.NotFound(w, r)
+			}
+		}))
+		defer server.Close()
+
+		var buf bytes.Buffer
+		root := cmd.NewRootCommand()
+		root.SetOut(&buf)
+		root.SetArgs([]string{"query", "create", "--dataset", "requests", "--calculation", "P99:duration_ms",
+			"--breakdown", "service", "--api-key", "test", "--api-url", server.URL})
+
+		err := root.Execute()
+		is.NotError(t, err)
+
+		is.Equal(t, "P99", created.Calculations[0].Op)
+		is.Equal(t, "service", created.Breakdowns[0])
+		is.True(t, contains(buf.String(), "Created query q1"))
+		is.True(t, contains(buf.String(), `"duration_ms"`))
+	})
+
+	t.Run("outputs the ID and spec as JSON", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch {
+			case r.Method == http.MethodPost && r.URL.Path == "/1/queries/requests":
+				_ = json.NewEncoder(w).Encode(honeycomb.QueryResponse{ID: "q1"})
+
+			default:
+				http.NotFound(w, r)
+			}
+		}))
+		defer server.Close()
+
+		var buf bytes.Buffer
+		root := cmd.NewRootCommand()
+		root.SetOut(&buf)
+		root.SetArgs([]string{"query", "create", "--dataset", "requests", "--json",
+			"--api-key", "test", "--api-url", server.URL})
+
+		err := root.Execute()
+		is.NotError(t, err)
+
+		var query honeycomb.QueryResponse
+		is.NotError(t, json.Unmarshal(buf.Bytes(), &query))
+		is.Equal(t, "q1", query.ID)
+		is.Equal(t, "COUNT", query.Spec.Calculations[0].Op)
+		is.Equal(t, 7200, query.Spec.TimeRange)
+	})
+}
+
+func TestQueryRunIDCommand(t *testing.T) {
+	t.Run("runs a query by ID and displays the results", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch {
+			case r.Method == http.MethodPost && r.URL.Path == "/1/query_results/requests":
+				var req honeycomb.QueryResultRequest
+				_ = json.NewDecoder(r.Body).Decode(&req)
+				is.Equal(t, "q1", req.QueryID)
+				_ = json.NewEncoder(w).Encode(honeycomb.QueryResult{ID: "r1"})
+
+			case r.Method == http.MethodGet && r.URL.Path == "/1/query_results/requests/r1":
+				result := honeycomb.QueryResult{ID: "r1", Complete: true}
+				result.Data.Results = []map[string]any{{"COUNT": float64(42)}}
+				_ = json.NewEncoder(w).Encode(result)
+
+			default:
+				http.NotFound(w, r)
+			}
+		}))
+		defer server.Close()
+
+		var buf bytes.Buffer
+		root := cmd.NewRootCommand()
+		root.SetOut(&buf)
+		root.SetArgs([]string{"query", "run-id", "q1", "--dataset", "requests", "--api-key", "test", "--api-url", server.URL})
+
+		err := root.Execute()
+		is.NotError(t, err)
+		is.True(t, contains(buf.String(), "42"))
+	})
+
+	t.Run("suggests fetching the result later on timeout", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch {
+			case r.Method == http.MethodPost && r.URL.Path == "/1/query_results/requests":
+				var req honeycomb.QueryResultRequest
+				_ = json.NewDecoder(r.Body).Decode(&req)
+				is.Equal(t, "q1", req.QueryID)
+				_ = json.NewEncoder(w).Encode(honeycomb.QueryResult{ID: "r1"})
+
+			case r.Method == http.MethodGet && r.URL.Path == "/1/query_results/requests/r1":
+				_ = json.NewEncoder(w).Encode(honeycomb.QueryResult{ID: "r1"})
+
+			default:
+				http.NotFound(w, r)
+			}
+		}))
+		defer server.Close()
+
+		root := cmd.NewRootCommand()
+		root.SetOut(&bytes.Buffer{})
+		root.SetArgs([]string{"query", "run-id", "q1", "--dataset", "requests", "--query-timeout", "50ms",
+			"--api-key", "test", "--api-url", server.URL})
+
+		err := root.Execute()
+		is.True(t, err != nil)
+		is.True(t, contains(err.Error(), "honeycomb-cli query result r1 --dataset requests --wait"))
+	})
+}
+
+func TestQueryResultCommand(t *testing.T) {
+	t.Run("fetches a complete result", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch {
+			case r.Method == http.MethodGet && r.URL.Path == "/1/query_results/requests/r1":
+				result := honeycomb.QueryResult{ID: "r1", Complete: true}
+				result.Data.Results = []map[string]any{{"COUNT": float64(42)}}
+				_ = json.NewEncoder(w).Encode(result)
+
+			default:
+				http.NotFound(w, r)
+			}
+		}))
+		defer server.Close()
+
+		var buf bytes.Buffer
+		root := cmd.NewRootCommand()
+		root.SetOut(&buf)
+		root.SetArgs([]string{"query", "result", "r1", "--dataset", "requests", "--json",
+			"--api-key", "test", "--api-url", server.URL})
+
+		err := root.Execute()
+		is.NotError(t, err)
+
+		var rows []map[string]any
+		is.NotError(t, json.Unmarshal(buf.Bytes(), &rows))
+		is.Equal(t, 42.0, rows[0]["COUNT"].(float64))
+	})
+
+	t.Run("errors on an incomplete result", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch {
+			case r.Method == http.MethodGet && r.URL.Path == "/1/query_results/requests/r1":
+				_ = json.NewEncoder(w).Encode(honeycomb.QueryResult{ID: "r1"})
+
+			default:
+				http.NotFound(w, r)
+			}
+		}))
+		defer server.Close()
+
+		root := cmd.NewRootCommand()
+		root.SetOut(&bytes.Buffer{})
+		root.SetArgs([]string{"query", "result", "r1", "--dataset", "requests", "--api-key", "test", "--api-url", server.URL})
+
+		err := root.Execute()
+		is.True(t, err != nil)
+		is.True(t, contains(err.Error(), "isn't complete yet"))
+	})
+
+	t.Run("waits for an incomplete result", func(t *testing.T) {
+		var polls atomic.Int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch {
+			case r.Method == http.MethodGet && r.URL.Path == "/1/query_results/requests/r1":
+				result := honeycomb.QueryResult{ID: "r1", Complete: polls.Add(1) >= 2}
+				if result.Complete {
+					result.Data.Results = []map[string]any{{"COUNT": float64(42)}}
+				}
+				_ = json.NewEncoder(w).Encode(result)
+
+			default:
+				http.NotFound(w, r)
+			}
+		}))
+		defer server.Close()
+
+		var buf bytes.Buffer
+		root := cmd.NewRootCommand()
+		root.SetOut(&buf)
+		root.SetArgs([]string{"query", "result", "r1", "--dataset", "requests", "--wait",
+			"--api-key", "test", "--api-url", server.URL})
+
+		err := root.Execute()
+		is.NotError(t, err)
+		is.True(t, contains(buf.String(), "42"))
+	})
+}
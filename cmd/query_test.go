@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
 	"maragu.dev/is"
@@ -97,6 +98,99 @@ func TestQueryCommand(t *testing.T) {
 	})
 }
 
+// newMultiDatasetQueryServer serves datasets "api" and "web" with a COUNT each, and fails queries in "broken".
+func newMultiDatasetQueryServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	counts := map[string]float64{"api": 42, "web": 7}
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/1/datasets":
+			_ = json.NewEncoder(w).Encode([]honeycomb.Dataset{{Slug: "api"}, {Slug: "web"}})
+
+		case r.URL.Path == "/1/queries/broken":
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"error": "dataset not found"}`))
+
+		case r.Method == http.MethodPost && strings.HasPrefix(r.URL.Path, "/1/queries/"):
+			_ = json.NewEncoder(w).Encode(honeycomb.QueryResponse{ID: "q1"})
+
+		case r.Method == http.MethodPost && strings.HasPrefix(r.URL.Path, "/1/query_results/"):
+			dataset := strings.TrimPrefix(r.URL.Path, "/1/query_results/")
+			result := honeycomb.QueryResult{ID: "r1", Complete: true}
+			result.Data.Results = []map[string]any{{"COUNT": counts[dataset]}}
+			_ = json.NewEncoder(w).Encode(result)
+		}
+	}))
+}
+
+func TestQueryCommandMultipleDatasets(t *testing.T) {
+	t.Run("merges results with a dataset column", func(t *testing.T) {
+		server := newMultiDatasetQueryServer(t)
+		defer server.Close()
+
+		var buf bytes.Buffer
+		root := cmd.NewRootCommand()
+		root.SetOut(&buf)
+		root.SetArgs([]string{"query", "--dataset", "api,web", "--json", "--api-key", "test", "--api-url", server.URL})
+
+		err := root.Execute()
+		is.NotError(t, err)
+
+		var rows []map[string]any
+		is.NotError(t, json.Unmarshal(buf.Bytes(), &rows))
+		is.Equal(t, 2, len(rows))
+		is.Equal(t, "api", rows[0]["dataset"].(string))
+		is.Equal(t, float64(42), rows[0]["COUNT"].(float64))
+		is.Equal(t, "web", rows[1]["dataset"].(string))
+		is.Equal(t, float64(7), rows[1]["COUNT"].(float64))
+	})
+
+	t.Run("queries all datasets", func(t *testing.T) {
+		server := newMultiDatasetQueryServer(t)
+		defer server.Close()
+
+		var buf bytes.Buffer
+		root := cmd.NewRootCommand()
+		root.SetOut(&buf)
+		root.SetArgs([]string{"query", "--all-datasets", "--api-key", "test", "--api-url", server.URL})
+
+		err := root.Execute()
+		is.NotError(t, err)
+
+		output := buf.String()
+		is.True(t, strings.HasPrefix(output, "dataset"))
+		is.True(t, contains(output, "api"))
+		is.True(t, contains(output, "web"))
+	})
+
+	t.Run("reports failed datasets and shows the rest", func(t *testing.T) {
+		server := newMultiDatasetQueryServer(t)
+		defer server.Close()
+
+		var buf, errBuf bytes.Buffer
+		root := cmd.NewRootCommand()
+		root.SetOut(&buf)
+		root.SetErr(&errBuf)
+		root.SetArgs([]string{"query", "--dataset", "api", "--dataset", "broken", "--api-key", "test", "--api-url", server.URL})
+
+		err := root.Execute()
+		is.True(t, err != nil)
+		is.True(t, contains(err.Error(), "query failed in 1 of 2 datasets"))
+		is.True(t, contains(errBuf.String(), "Error querying broken"))
+		is.True(t, contains(buf.String(), "42"))
+	})
+
+	t.Run("requires a dataset", func(t *testing.T) {
+		root := cmd.NewRootCommand()
+		root.SetOut(&bytes.Buffer{})
+		root.SetErr(&bytes.Buffer{})
+		root.SetArgs([]string{"query", "--api-key", "test", "--api-url", "http://localhost"})
+
+		err := root.Execute()
+		is.True(t, err != nil)
+	})
+}
+
 func TestParseCalculation(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -157,3 +251,27 @@ func TestParseFilter(t *testing.T) {
 		})
 	}
 }
+
+func TestQueryCommand_QueryTimeout(t *testing.T) {
+	t.Run("errors with the result ID when the result isn't complete in time", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch {
+			case r.Method == http.MethodPost && r.URL.Path == "/1/queries/requests":
+				_ = json.NewEncoder(w).Encode(honeycomb.QueryResponse{ID: "q1"})
+			case strings.HasPrefix(r.URL.Path, "/1/query_results/requests"):
+				_ = json.NewEncoder(w).Encode(honeycomb.QueryResult{ID: "r1"})
+			}
+		}))
+		defer server.Close()
+
+		root := cmd.NewRootCommand()
+		root.SetOut(&bytes.Buffer{})
+		root.SetErr(&bytes.Buffer{})
+		root.SetArgs([]string{"query", "--dataset", "requests", "--query-timeout", "100ms",
+			"--api-key", "test", "--api-url", server.URL})
+
+		err := root.Execute()
+		is.True(t, err != nil)
+		is.True(t, contains(err.Error(), "query result r1 in dataset requests not complete"))
+	})
+}
@@ -0,0 +1,182 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"os/signal"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/spf13/cobra"
+
+	"github.com/maragudk/honeycomb-cli/honeycomb"
+)
+
+// ANSI escape codes for full-screen terminal output.
+const (
+	enterAlternateScreen = "\x1b[?1049h\x1b[?25l"
+	exitAlternateScreen  = "\x1b[?25h\x1b[?1049l"
+	clearScreen          = "\x1b[H\x1b[2J"
+)
+
+// watchFrame is a single refresh of a watched query, written as a JSON line when not in a terminal.
+type watchFrame struct {
+	Time    time.Time        `json:"time"`
+	Results []map[string]any `json:"results"`
+	Error   string           `json:"error,omitempty"`
+}
+
+// watchQuery re-runs the query on the interval until the context is cancelled or the process is interrupted.
+// In a terminal, the results table is redrawn in place with changed values highlighted. Otherwise, or with --json,
+// each refresh is appended as a JSON line. Errors are shown and don't stop the watch.
+func watchQuery(cmd *cobra.Command, c *honeycomb.Client, datasets []string, spec honeycomb.QuerySpec, concurrency int,
+	interval time.Duration) error {
+	ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt)
+	defer stop()
+
+	out := cmd.OutOrStdout()
+	asJSON, _ := cmd.Flags().GetBool("json")
+	fullScreen := !asJSON && isTerminal(out)
+	h := newHighlighter(out)
+
+	calculations := map[string]bool{}
+	for _, calc := range spec.Calculations {
+		calculations[calc.Name()] = true
+	}
+
+	var lastFrame string
+	if fullScreen {
+		fmt.Fprint(out, enterAlternateScreen)
+		defer func() {
+			// Leave the last results on the normal screen after exiting
+			fmt.Fprint(out, exitAlternateScreen+lastFrame)
+		}()
+	}
+
+	var previous map[string]map[string]any
+	for {
+		rows, results, err := queryRows(ctx, c, datasets, spec, concurrency)
+		if ctx.Err() != nil {
+			return nil
+		}
+		failures := queryFailures(results)
+		failed := len(failures)
+		if err == nil && failed > 0 {
+			if !fullScreen {
+				for _, err := range failures {
+					fmt.Fprintf(cmd.ErrOrStderr(), "Error %v\n", err)
+				}
+			}
+			err = fmt.Errorf("query failed in %v of %v datasets", failed, len(datasets))
+		}
+
+		now := time.Now()
+		if !fullScreen {
+			frame := watchFrame{Time: now.UTC(), Results: rows}
+			if frame.Results == nil {
+				frame.Results = []map[string]any{}
+			}
+			if err != nil {
+				frame.Error = err.Error()
+			}
+			if err := json.NewEncoder(out).Encode(frame); err != nil {
+				return err
+			}
+		} else {
+			var buf bytes.Buffer
+			fmt.Fprintf(&buf, "Every %v, updated %v (Ctrl-C to stop)\n\n", interval, now.Format(time.TimeOnly))
+			switch {
+			case err != nil && failed == 0:
+				fmt.Fprintf(&buf, "Error: %v\n", err)
+			case len(rows) == 0:
+				fmt.Fprintln(&buf, "No results.")
+			default:
+				writeWatchTable(&buf, rows, previous, calculations, h)
+				if err != nil {
+					fmt.Fprintf(&buf, "\nError: %v\n", err)
+					for _, err := range failures {
+						fmt.Fprintf(&buf, "  %v\n", err)
+					}
+				}
+			}
+			lastFrame = buf.String()
+			fmt.Fprint(out, clearScreen+lastFrame)
+		}
+
+		if err == nil || failed > 0 {
+			previous = map[string]map[string]any{}
+			for _, row := range rows {
+				previous[watchRowKey(row, calculations)] = row
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(interval):
+		}
+	}
+}
+
+// watchRowKey identifies a result row across refreshes by its values that aren't calculations,
+// like the dataset and breakdowns.
+func watchRowKey(row map[string]any, calculations map[string]bool) string {
+	var parts []string
+	for _, k := range slices.Sorted(maps.Keys(row)) {
+		if !calculations[k] {
+			parts = append(parts, k+"="+fmt.Sprint(row[k]))
+		}
+	}
+	return strings.Join(parts, "\x00")
+}
+
+// writeWatchTable writes the rows as a table, highlighting values that changed since the previous rows.
+// Rows that weren't in the previous rows are highlighted entirely, unless there are no previous rows at all.
+// Columns are padded by hand instead of with tabwriter, so that escape codes can be applied to single cells.
+func writeWatchTable(w io.Writer, rows []map[string]any, previous map[string]map[string]any,
+	calculations map[string]bool, h highlighter) {
+	headers := queryHeaders(rows)
+	table := [][]string{headers}
+	for _, row := range rows {
+		table = append(table, queryCells(row, headers))
+	}
+
+	widths := make([]int, len(headers))
+	for _, cells := range table {
+		for i, cell := range cells {
+			widths[i] = max(widths[i], utf8.RuneCountInString(cell))
+		}
+	}
+
+	for r, cells := range table {
+		var prevCells []string
+		var isNew bool
+		if r > 0 && previous != nil {
+			prev, ok := previous[watchRowKey(rows[r-1], calculations)]
+			if ok {
+				prevCells = queryCells(prev, headers)
+			}
+			isNew = !ok
+		}
+
+		var line strings.Builder
+		for i, cell := range cells {
+			padding := ""
+			if i < len(cells)-1 {
+				padding = strings.Repeat(" ", widths[i]-utf8.RuneCountInString(cell)+2)
+			}
+			changed := isNew || (prevCells != nil && calculations[headers[i]] && prevCells[i] != cell)
+			if changed {
+				cell = h.highlight(cell)
+			}
+			line.WriteString(cell + padding)
+		}
+		fmt.Fprintln(w, line.String())
+	}
+}
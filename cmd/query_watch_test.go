@@ -0,0 +1,167 @@
+package cmd_test
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"maragu.dev/is"
+
+	"github.com/maragudk/honeycomb-cli/cmd"
+	"github.com/maragudk/honeycomb-cli/honeycomb"
+)
+
+func TestQueryCommand_Watch(t *testing.T) {
+	t.Run("appends a JSON line per refresh until cancelled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(t.Context())
+		defer cancel()
+
+		var mu sync.Mutex
+		var runs int
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			mu.Lock()
+			defer mu.Unlock()
+			switch {
+			case r.Method == http.MethodPost && r.URL.Path == "/1/queries/requests":
+				_ = json.NewEncoder(w).Encode(honeycomb.QueryResponse{ID: "q1"})
+
+			case r.Method == http.MethodPost && r.URL.Path == "/1/query_results/requests":
+				runs++
+				result := honeycomb.QueryResult{ID: "r1", Complete: true}
+				result.Data.Results = []map[string]any{{"COUNT": float64(runs)}}
+				_ = json.NewEncoder(w).Encode(result)
+				if runs == 2 {
+					cancel()
+				}
+			}
+		}))
+		defer server.Close()
+
+		var buf bytes.Buffer
+		root := cmd.NewRootCommand()
+		root.SetOut(&buf)
+		root.SetArgs([]string{"query", "--dataset", "requests", "--calculation", "COUNT", "--watch", "10ms",
+			"--api-key", "test", "--api-url", server.URL})
+
+		start := time.Now()
+		err := root.ExecuteContext(ctx)
+		is.NotError(t, err)
+		is.True(t, time.Since(start) < 5*time.Second)
+
+		type watchFrame struct {
+			Time    time.Time        `json:"time"`
+			Results []map[string]any `json:"results"`
+		}
+		var frames []watchFrame
+		scanner := bufio.NewScanner(&buf)
+		for scanner.Scan() {
+			var frame watchFrame
+			is.NotError(t, json.Unmarshal(scanner.Bytes(), &frame))
+			frames = append(frames, frame)
+		}
+		is.Equal(t, 1, len(frames))
+		is.Equal(t, 1.0, frames[0].Results[0]["COUNT"].(float64))
+		is.True(t, !frames[0].Time.IsZero())
+	})
+
+	t.Run("keeps watching after an error", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(t.Context())
+		defer cancel()
+
+		var mu sync.Mutex
+		var runs int
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			mu.Lock()
+			defer mu.Unlock()
+			switch {
+			case r.Method == http.MethodPost && r.URL.Path == "/1/queries/requests":
+				runs++
+				switch runs {
+				case 1:
+					http.Error(w, `{"error": "oops"}`, http.StatusInternalServerError)
+					return
+				case 3:
+					cancel()
+				}
+				_ = json.NewEncoder(w).Encode(honeycomb.QueryResponse{ID: "q1"})
+
+			case r.Method == http.MethodPost && r.URL.Path == "/1/query_results/requests":
+				result := honeycomb.QueryResult{ID: "r1", Complete: true}
+				result.Data.Results = []map[string]any{{"COUNT": float64(42)}}
+				_ = json.NewEncoder(w).Encode(result)
+			}
+		}))
+		defer server.Close()
+
+		var buf bytes.Buffer
+		root := cmd.NewRootCommand()
+		root.SetOut(&buf)
+		root.SetArgs([]string{"query", "--dataset", "requests", "--watch", "10ms",
+			"--api-key", "test", "--api-url", server.URL})
+
+		err := root.ExecuteContext(ctx)
+		is.NotError(t, err)
+
+		var frames []map[string]any
+		scanner := bufio.NewScanner(&buf)
+		for scanner.Scan() {
+			var frame map[string]any
+			is.NotError(t, json.Unmarshal(scanner.Bytes(), &frame))
+			frames = append(frames, frame)
+		}
+		is.Equal(t, 2, len(frames))
+		is.True(t, contains(frames[0]["error"].(string), "creating query"))
+		is.Equal(t, nil, frames[1]["error"])
+	})
+
+	t.Run("accepts an interval in days", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(t.Context())
+		defer cancel()
+
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch {
+			case r.Method == http.MethodPost && r.URL.Path == "/1/queries/requests":
+				_ = json.NewEncoder(w).Encode(honeycomb.QueryResponse{ID: "q1"})
+
+			case r.Method == http.MethodPost && r.URL.Path == "/1/query_results/requests":
+				_ = json.NewEncoder(w).Encode(honeycomb.QueryResult{ID: "r1", Complete: true})
+				cancel()
+			}
+		}))
+		defer server.Close()
+
+		root := cmd.NewRootCommand()
+		root.SetOut(&bytes.Buffer{})
+		root.SetArgs([]string{"query", "--dataset", "requests", "--watch", "1d", "--api-key", "test", "--api-url", server.URL})
+
+		err := root.ExecuteContext(ctx)
+		is.NotError(t, err)
+	})
+
+	t.Run("errors on an invalid interval", func(t *testing.T) {
+		root := cmd.NewRootCommand()
+		root.SetOut(&bytes.Buffer{})
+		root.SetErr(&bytes.Buffer{})
+		root.SetArgs([]string{"query", "--dataset", "requests", "--watch", "soon", "--api-key", "test"})
+
+		err := root.Execute()
+		is.True(t, err != nil)
+		is.True(t, contains(err.Error(), "invalid --watch interval"))
+	})
+
+	t.Run("can't be combined with compare", func(t *testing.T) {
+		root := cmd.NewRootCommand()
+		root.SetOut(&bytes.Buffer{})
+		root.SetErr(&bytes.Buffer{})
+		root.SetArgs([]string{"query", "--dataset", "requests", "--watch", "30s", "--compare", "1d", "--api-key", "test"})
+
+		err := root.Execute()
+		is.True(t, err != nil)
+	})
+}
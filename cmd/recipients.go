@@ -0,0 +1,358 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"github.com/maragudk/honeycomb-cli/honeycomb"
+)
+
+var recipientTypes = []string{"email", "slack", "pagerduty", "webhook", "msteams_workflow"}
+
+func newRecipientsCommand() *cobra.Command {
+	recipientsCmd := &cobra.Command{
+		Use:   "recipients",
+		Short: "Manage notification recipients for triggers and burn alerts",
+	}
+
+	recipientsCmd.AddCommand(newRecipientsListCommand())
+	recipientsCmd.AddCommand(newRecipientsGetCommand())
+	recipientsCmd.AddCommand(newRecipientsCreateCommand())
+	recipientsCmd.AddCommand(newRecipientsUpdateCommand())
+	recipientsCmd.AddCommand(newRecipientsDeleteCommand())
+	recipientsCmd.AddCommand(newRecipientsUsageCommand())
+
+	return recipientsCmd
+}
+
+func newRecipientsListCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List all recipients",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c := newClient(cmd)
+			recipientType, _ := cmd.Flags().GetString("type")
+
+			recipients, err := c.ListRecipients(cmd.Context())
+			if err != nil {
+				return err
+			}
+			if recipientType != "" {
+				recipients = slices.DeleteFunc(recipients, func(r honeycomb.Recipient) bool { return r.Type != recipientType })
+			}
+
+			asJSON, _ := cmd.Flags().GetBool("json")
+			if asJSON {
+				for i := range recipients {
+					maskRecipientSecrets(&recipients[i])
+				}
+				return json.NewEncoder(cmd.OutOrStdout()).Encode(recipients)
+			}
+
+			w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 2, ' ', 0)
+			fmt.Fprintln(w, "ID\tTYPE\tTARGET")
+			for _, r := range recipients {
+				fmt.Fprintf(w, "%v\t%v\t%v\n", r.ID, r.Type, r.Target())
+			}
+			return w.Flush()
+		},
+	}
+	cmd.Flags().String("type", "", "Only list recipients of this type")
+	cmd.Flags().Bool("json", false, "Output as JSON")
+	return cmd
+}
+
+func newRecipientsGetCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "get <id>",
+		Short: "Get a recipient by ID",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c := newClient(cmd)
+
+			recipient, err := c.GetRecipient(cmd.Context(), args[0])
+			if err != nil {
+				return err
+			}
+
+			asJSON, _ := cmd.Flags().GetBool("json")
+			if asJSON {
+				maskRecipientSecrets(recipient)
+				return json.NewEncoder(cmd.OutOrStdout()).Encode(recipient)
+			}
+
+			out := cmd.OutOrStdout()
+			d := recipient.Details
+			fmt.Fprintf(out, "ID:     %v\n", recipient.ID)
+			fmt.Fprintf(out, "Type:   %v\n", recipient.Type)
+			fmt.Fprintf(out, "Target: %v\n", recipient.Target())
+			if d.WebhookURL != "" {
+				fmt.Fprintf(out, "URL:    %v\n", d.WebhookURL)
+			}
+			if d.PagerDutyIntegrationKey != "" {
+				fmt.Fprintf(out, "Key:    %v\n", maskSecret(d.PagerDutyIntegrationKey))
+			}
+			if d.WebhookSecret != "" {
+				fmt.Fprintf(out, "Secret: %v\n", maskSecret(d.WebhookSecret))
+			}
+			return nil
+		},
+	}
+	cmd.Flags().Bool("json", false, "Output as JSON")
+	return cmd
+}
+
+// maskSecret shows only the last four characters of a secret.
+func maskSecret(s string) string {
+	if len(s) <= 4 {
+		return "****"
+	}
+	return "****" + s[len(s)-4:]
+}
+
+// maskRecipientSecrets so JSON output doesn't reveal webhook secrets and PagerDuty integration keys.
+func maskRecipientSecrets(r *honeycomb.Recipient) {
+	if r.Details.WebhookSecret != "" {
+		r.Details.WebhookSecret = maskSecret(r.Details.WebhookSecret)
+	}
+	if r.Details.PagerDutyIntegrationKey != "" {
+		r.Details.PagerDutyIntegrationKey = maskSecret(r.Details.PagerDutyIntegrationKey)
+	}
+}
+
+// addRecipientDetailsFlags for the recipient detail fields, used by create and update.
+func addRecipientDetailsFlags(cmd *cobra.Command) {
+	cmd.Flags().String("email", "", "Email address (email)")
+	cmd.Flags().String("channel", "", "Slack channel (slack)")
+	cmd.Flags().String("integration-key", "", "PagerDuty integration key (pagerduty)")
+	cmd.Flags().String("integration-name", "", "PagerDuty integration name (pagerduty)")
+	cmd.Flags().String("name", "", "Webhook name (webhook, msteams_workflow)")
+	cmd.Flags().String("url", "", "Webhook URL (webhook, msteams_workflow)")
+	cmd.Flags().String("secret", "", "Webhook shared secret (webhook)")
+}
+
+// recipientDetailsFromFlags sets the details fields for the flags that were given.
+func recipientDetailsFromFlags(cmd *cobra.Command, d *honeycomb.RecipientDetails) {
+	fields := map[string]*string{
+		"email":            &d.EmailAddress,
+		"channel":          &d.SlackChannel,
+		"integration-key":  &d.PagerDutyIntegrationKey,
+		"integration-name": &d.PagerDutyIntegrationName,
+		"name":             &d.WebhookName,
+		"url":              &d.WebhookURL,
+		"secret":           &d.WebhookSecret,
+	}
+	for flag, field := range fields {
+		if cmd.Flags().Changed(flag) {
+			*field, _ = cmd.Flags().GetString(flag)
+		}
+	}
+}
+
+func newRecipientsCreateCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "create",
+		Short: "Create a recipient",
+		Long: `Create a recipient. Which details are needed depends on the type:
+
+  email             --email
+  slack             --channel
+  pagerduty         --integration-key and --integration-name
+  webhook           --name and --url, optionally --secret
+  msteams_workflow  --name and --url
+
+Example:
+  honeycomb-cli recipients create --type pagerduty --integration-key abc123 --integration-name Payments`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c := newClient(cmd)
+			recipientType, _ := cmd.Flags().GetString("type")
+
+			if !slices.Contains(recipientTypes, recipientType) {
+				return fmt.Errorf("invalid recipient type %q (must be one of %v)", recipientType, recipientTypes)
+			}
+
+			recipient := honeycomb.Recipient{Type: recipientType}
+			recipientDetailsFromFlags(cmd, &recipient.Details)
+
+			created, err := c.CreateRecipient(cmd.Context(), recipient)
+			if err != nil {
+				return err
+			}
+
+			fmt.Fprintf(cmd.OutOrStdout(), "Created %v recipient %q (%v)\n", created.Type, created.Target(), created.ID)
+			return nil
+		},
+	}
+	cmd.Flags().String("type", "", "Recipient type: email, slack, pagerduty, webhook, or msteams_workflow (required)")
+	_ = cmd.MarkFlagRequired("type")
+	addRecipientDetailsFlags(cmd)
+	return cmd
+}
+
+func newRecipientsUpdateCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "update <id>",
+		Short: "Update a recipient's details",
+		Long: `Update a recipient's details, such as a rotated PagerDuty integration key or webhook URL.
+Only the given flags are changed. Triggers and burn alerts keep pointing at the recipient.
+
+Example:
+  honeycomb-cli recipients update abc123 --integration-key new-key`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c := newClient(cmd)
+
+			recipient, err := c.GetRecipient(cmd.Context(), args[0])
+			if err != nil {
+				return err
+			}
+			recipientDetailsFromFlags(cmd, &recipient.Details)
+
+			updated, err := c.UpdateRecipient(cmd.Context(), *recipient)
+			if err != nil {
+				return err
+			}
+
+			fmt.Fprintf(cmd.OutOrStdout(), "Updated %v recipient %q (%v)\n", updated.Type, updated.Target(), updated.ID)
+			return nil
+		},
+	}
+	addRecipientDetailsFlags(cmd)
+	return cmd
+}
+
+func newRecipientsDeleteCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "delete <id>",
+		Short: "Delete a recipient that no trigger or burn alert uses",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c := newClient(cmd)
+			force, _ := cmd.Flags().GetBool("force")
+
+			if !force {
+				usage, err := findRecipientUsage(cmd.Context(), c, args[0])
+				if err != nil {
+					return err
+				}
+				if len(usage) > 0 {
+					return fmt.Errorf("recipient %v is used by %v trigger(s) and burn alert(s), see 'recipients usage %v' or use --force",
+						args[0], len(usage), args[0])
+				}
+			}
+
+			if err := c.DeleteRecipient(cmd.Context(), args[0]); err != nil {
+				return err
+			}
+
+			fmt.Fprintf(cmd.OutOrStdout(), "Deleted recipient %v\n", args[0])
+			return nil
+		},
+	}
+	cmd.Flags().Bool("force", false, "Delete even if triggers or burn alerts use the recipient")
+	return cmd
+}
+
+// recipientUsage is a trigger or burn alert that notifies a recipient.
+type recipientUsage struct {
+	Kind    string `json:"kind"`
+	Dataset string `json:"dataset"`
+	ID      string `json:"id"`
+	Name    string `json:"name"`
+}
+
+// findRecipientUsage in the triggers and burn alerts of all datasets, and in environment-wide triggers.
+func findRecipientUsage(ctx context.Context, c *honeycomb.Client, id string) ([]recipientUsage, error) {
+	datasets, err := c.ListDatasets(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("listing datasets: %w", err)
+	}
+
+	notifies := func(recipients []honeycomb.NotificationRecipient) bool {
+		return slices.ContainsFunc(recipients, func(r honeycomb.NotificationRecipient) bool { return r.ID == id })
+	}
+
+	var usage []recipientUsage
+	for _, d := range datasets {
+		triggers, err := c.ListTriggers(ctx, d.Slug)
+		if err != nil {
+			return nil, fmt.Errorf("listing triggers in %v: %w", d.Slug, err)
+		}
+		for _, t := range triggers {
+			if notifies(t.Recipients) {
+				usage = append(usage, recipientUsage{Kind: "trigger", Dataset: d.Slug, ID: t.ID, Name: t.Name})
+			}
+		}
+
+		slos, err := c.ListSLOs(ctx, d.Slug)
+		if err != nil {
+			return nil, fmt.Errorf("listing SLOs in %v: %w", d.Slug, err)
+		}
+		for _, s := range slos {
+			alerts, err := c.ListBurnAlerts(ctx, d.Slug, s.ID)
+			if err != nil {
+				return nil, fmt.Errorf("listing burn alerts for SLO %q: %w", s.Name, err)
+			}
+			for _, a := range alerts {
+				if notifies(a.Recipients) {
+					name := fmt.Sprintf("%v (%v)", s.Name, a.AlertType)
+					usage = append(usage, recipientUsage{Kind: "burn alert", Dataset: d.Slug, ID: a.ID, Name: name})
+				}
+			}
+		}
+	}
+
+	// Environment-wide triggers aren't listed in any dataset
+	triggers, err := c.ListTriggers(ctx, "__all__")
+	if err != nil {
+		return nil, fmt.Errorf("listing environment-wide triggers: %w", err)
+	}
+	for _, t := range triggers {
+		if notifies(t.Recipients) {
+			usage = append(usage, recipientUsage{Kind: "trigger", Dataset: "__all__", ID: t.ID, Name: t.Name})
+		}
+	}
+	return usage, nil
+}
+
+func newRecipientsUsageCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "usage <id>",
+		Short: "List the triggers and burn alerts that notify a recipient",
+		Long: `List every trigger and burn alert in every dataset that notifies the recipient,
+so you know what is affected before rotating or deleting it.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c := newClient(cmd)
+
+			usage, err := findRecipientUsage(cmd.Context(), c, args[0])
+			if err != nil {
+				return err
+			}
+
+			asJSON, _ := cmd.Flags().GetBool("json")
+			if asJSON {
+				return json.NewEncoder(cmd.OutOrStdout()).Encode(usage)
+			}
+
+			if len(usage) == 0 {
+				fmt.Fprintf(cmd.OutOrStdout(), "No triggers or burn alerts notify recipient %v\n", args[0])
+				return nil
+			}
+
+			w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 2, ' ', 0)
+			fmt.Fprintln(w, "KIND\tDATASET\tID\tNAME")
+			for _, u := range usage {
+				fmt.Fprintf(w, "%v\t%v\t%v\t%v\n", u.Kind, u.Dataset, u.ID, u.Name)
+			}
+			return w.Flush()
+		},
+	}
+	cmd.Flags().Bool("json", false, "Output as JSON")
+	return cmd
+}
@@ -0,0 +1,286 @@
+package cmd_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"maragu.dev/is"
+
+	"github.com/maragudk/honeycomb-cli/cmd"
+	"github.com/maragudk/honeycomb-cli/honeycomb"
+)
+
+func TestRecipientsListCommand(t *testing.T) {
+	t.Run("lists recipients filtered by type", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			is.Equal(t, "/1/recipients", r.URL.Path)
+			_ = json.NewEncoder(w).Encode([]honeycomb.Recipient{
+				{ID: "r1", Type: "email", Details: honeycomb.RecipientDetails{EmailAddress: "oncall@example.com"}},
+				{ID: "r2", Type: "slack", Details: honeycomb.RecipientDetails{SlackChannel: "#alerts"}},
+			})
+		}))
+		defer server.Close()
+
+		var buf bytes.Buffer
+		root := cmd.NewRootCommand()
+		root.SetOut(&buf)
+		root.SetArgs([]string{"recipients", "list", "--type", "slack", "--api-key", "test", "--api-url", server.URL})
+
+		err := root.Execute()
+		is.NotError(t, err)
+
+		output := buf.String()
+		is.True(t, contains(output, "#alerts"))
+		is.True(t, !contains(output, "oncall@example.com"))
+	})
+
+	t.Run("masks secrets in JSON", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_ = json.NewEncoder(w).Encode([]honeycomb.Recipient{
+				{ID: "r1", Type: "webhook", Details: honeycomb.RecipientDetails{WebhookName: "Deploys", WebhookSecret: "supersecret"}},
+				{ID: "r2", Type: "pagerduty", Details: honeycomb.RecipientDetails{PagerDutyIntegrationKey: "integrationkey"}},
+			})
+		}))
+		defer server.Close()
+
+		var buf bytes.Buffer
+		root := cmd.NewRootCommand()
+		root.SetOut(&buf)
+		root.SetArgs([]string{"recipients", "list", "--json", "--api-key", "test", "--api-url", server.URL})
+
+		err := root.Execute()
+		is.NotError(t, err)
+
+		output := buf.String()
+		is.True(t, !contains(output, "supersecret"))
+		is.True(t, contains(output, `"webhook_secret":"****cret"`))
+		is.True(t, !contains(output, "integrationkey"))
+		is.True(t, contains(output, `"pagerduty_integration_key":"****nkey"`))
+	})
+}
+
+func TestRecipientsCreateCommand(t *testing.T) {
+	t.Run("creates a recipient from flags", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			is.Equal(t, http.MethodPost, r.Method)
+			var recipient honeycomb.Recipient
+			_ = json.NewDecoder(r.Body).Decode(&recipient)
+			is.Equal(t, "pagerduty", recipient.Type)
+			is.Equal(t, "abc123", recipient.Details.PagerDutyIntegrationKey)
+			recipient.ID = "r1"
+			_ = json.NewEncoder(w).Encode(recipient)
+		}))
+		defer server.Close()
+
+		var buf bytes.Buffer
+		root := cmd.NewRootCommand()
+		root.SetOut(&buf)
+		root.SetArgs([]string{"recipients", "create", "--type", "pagerduty", "--integration-key", "abc123",
+			"--integration-name", "Payments", "--api-key", "test", "--api-url", server.URL})
+
+		err := root.Execute()
+		is.NotError(t, err)
+		is.True(t, contains(buf.String(), `Created pagerduty recipient "Payments" (r1)`))
+	})
+
+	t.Run("errors on an invalid type", func(t *testing.T) {
+		root := cmd.NewRootCommand()
+		root.SetOut(&bytes.Buffer{})
+		root.SetArgs([]string{"recipients", "create", "--type", "carrier-pigeon", "--api-key", "test", "--api-url", "http://localhost"})
+
+		err := root.Execute()
+		is.True(t, err != nil)
+		is.True(t, contains(err.Error(), `invalid recipient type "carrier-pigeon"`))
+	})
+}
+
+func TestRecipientsUpdateCommand(t *testing.T) {
+	t.Run("changes only the given details", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			is.Equal(t, "/1/recipients/r1", r.URL.Path)
+			switch r.Method {
+			case http.MethodGet:
+				_ = json.NewEncoder(w).Encode(honeycomb.Recipient{ID: "r1", Type: "pagerduty", Details: honeycomb.RecipientDetails{
+					PagerDutyIntegrationKey: "old", PagerDutyIntegrationName: "Payments",
+				}})
+			case http.MethodPut:
+				var recipient honeycomb.Recipient
+				_ = json.NewDecoder(r.Body).Decode(&recipient)
+				is.Equal(t, "new", recipient.Details.PagerDutyIntegrationKey)
+				is.Equal(t, "Payments", recipient.Details.PagerDutyIntegrationName)
+				_ = json.NewEncoder(w).Encode(recipient)
+			}
+		}))
+		defer server.Close()
+
+		var buf bytes.Buffer
+		root := cmd.NewRootCommand()
+		root.SetOut(&buf)
+		root.SetArgs([]string{"recipients", "update", "r1", "--integration-key", "new", "--api-key", "test", "--api-url", server.URL})
+
+		err := root.Execute()
+		is.NotError(t, err)
+		is.True(t, contains(buf.String(), `Updated pagerduty recipient "Payments" (r1)`))
+	})
+}
+
+func TestRecipientsUsageCommand(t *testing.T) {
+	t.Run("lists triggers and burn alerts that notify the recipient", func(t *testing.T) {
+		// A trigger, an environment-wide trigger, and a burn alert notify recipient r1
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch r.Method + " " + r.URL.Path {
+			case "GET /1/datasets":
+				_ = json.NewEncoder(w).Encode([]honeycomb.Dataset{{Name: "API", Slug: "api"}})
+			case "GET /1/triggers/api":
+				_ = json.NewEncoder(w).Encode([]honeycomb.Trigger{
+					{ID: "t1", Name: "High error rate", Recipients: []honeycomb.NotificationRecipient{{ID: "r1"}}},
+					{ID: "t2", Name: "Slow requests", Recipients: []honeycomb.NotificationRecipient{{ID: "r2"}}},
+				})
+			case "GET /1/slos/api":
+				_ = json.NewEncoder(w).Encode([]honeycomb.SLO{{ID: "s1", Name: "Availability"}})
+			case "GET /1/burn_alerts/api":
+				_ = json.NewEncoder(w).Encode([]honeycomb.BurnAlert{
+					{ID: "ba1", AlertType: "exhaustion_time", Recipients: []honeycomb.NotificationRecipient{{ID: "r1"}}},
+				})
+			case "GET /1/triggers/__all__":
+				_ = json.NewEncoder(w).Encode([]honeycomb.Trigger{
+					{ID: "t3", Name: "Ingest volume", Recipients: []honeycomb.NotificationRecipient{{ID: "r1"}}},
+				})
+			default:
+				t.Errorf("unexpected request %v %v", r.Method, r.URL.Path)
+				http.Error(w, `{"error": "not found"}`, http.StatusNotFound)
+			}
+		}))
+		defer server.Close()
+
+		var buf bytes.Buffer
+		root := cmd.NewRootCommand()
+		root.SetOut(&buf)
+		root.SetArgs([]string{"recipients", "usage", "r1", "--api-key", "test", "--api-url", server.URL})
+
+		err := root.Execute()
+		is.NotError(t, err)
+
+		output := buf.String()
+		is.True(t, contains(output, "High error rate"))
+		is.True(t, contains(output, "Ingest volume"))
+		is.True(t, contains(output, "Availability (exhaustion_time)"))
+		is.True(t, !contains(output, "Slow requests"))
+	})
+}
+
+func TestRecipientsDeleteCommand(t *testing.T) {
+	t.Run("refuses to delete a recipient in use", func(t *testing.T) {
+		var deleted bool
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch r.Method + " " + r.URL.Path {
+			case "GET /1/datasets":
+				_ = json.NewEncoder(w).Encode([]honeycomb.Dataset{{Name: "API", Slug: "api"}})
+			case "GET /1/triggers/api":
+				_ = json.NewEncoder(w).Encode([]honeycomb.Trigger{
+					{ID: "t1", Name: "High error rate", Recipients: []honeycomb.NotificationRecipient{{ID: "r1"}}},
+					{ID: "t2", Name: "Slow requests", Recipients: []honeycomb.NotificationRecipient{{ID: "r2"}}},
+				})
+			case "GET /1/slos/api":
+				_ = json.NewEncoder(w).Encode([]honeycomb.SLO{{ID: "s1", Name: "Availability"}})
+			case "GET /1/burn_alerts/api":
+				_ = json.NewEncoder(w).Encode([]honeycomb.BurnAlert{
+					{ID: "ba1", AlertType: "exhaustion_time", Recipients: []honeycomb.NotificationRecipient{{ID: "r1"}}},
+				})
+			case "GET /1/triggers/__all__":
+				_, _ = w.Write([]byte(`[]`))
+			case "DELETE /1/recipients/r1":
+				deleted = true
+				w.WriteHeader(http.StatusNoContent)
+			default:
+				t.Errorf("unexpected request %v %v", r.Method, r.URL.Path)
+				http.Error(w, `{"error": "not found"}`, http.StatusNotFound)
+			}
+		}))
+		defer server.Close()
+
+		root := cmd.NewRootCommand()
+		root.SetOut(&bytes.Buffer{})
+		root.SetArgs([]string{"recipients", "delete", "r1", "--api-key", "test", "--api-url", server.URL})
+
+		err := root.Execute()
+		is.True(t, err != nil)
+		is.True(t, contains(err.Error(), "used by 2 trigger(s) and burn alert(s)"))
+		is.True(t, !deleted)
+	})
+
+	t.Run("refuses to delete a recipient used by an environment-wide trigger", func(t *testing.T) {
+		var deleted bool
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch r.Method + " " + r.URL.Path {
+			case "GET /1/datasets":
+				_ = json.NewEncoder(w).Encode([]honeycomb.Dataset{{Name: "API", Slug: "api"}})
+			case "GET /1/triggers/api", "GET /1/slos/api":
+				_, _ = w.Write([]byte(`[]`))
+			case "GET /1/triggers/__all__":
+				_ = json.NewEncoder(w).Encode([]honeycomb.Trigger{
+					{ID: "t3", Name: "Ingest volume", Recipients: []honeycomb.NotificationRecipient{{ID: "r1"}}},
+				})
+			case "DELETE /1/recipients/r1":
+				deleted = true
+				w.WriteHeader(http.StatusNoContent)
+			default:
+				t.Errorf("unexpected request %v %v", r.Method, r.URL.Path)
+				http.Error(w, `{"error": "not found"}`, http.StatusNotFound)
+			}
+		}))
+		defer server.Close()
+
+		root := cmd.NewRootCommand()
+		root.SetOut(&bytes.Buffer{})
+		root.SetArgs([]string{"recipients", "delete", "r1", "--api-key", "test", "--api-url", server.URL})
+
+		err := root.Execute()
+		is.True(t, err != nil)
+		is.True(t, contains(err.Error(), "used by 1 trigger(s) and burn alert(s)"))
+		is.True(t, !deleted)
+	})
+
+	t.Run("deletes an unused recipient", func(t *testing.T) {
+		var deleted bool
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch r.Method + " " + r.URL.Path {
+			case "GET /1/datasets":
+				_ = json.NewEncoder(w).Encode([]honeycomb.Dataset{{Name: "API", Slug: "api"}})
+			case "GET /1/triggers/api":
+				_ = json.NewEncoder(w).Encode([]honeycomb.Trigger{
+					{ID: "t1", Name: "High error rate", Recipients: []honeycomb.NotificationRecipient{{ID: "r1"}}},
+					{ID: "t2", Name: "Slow requests", Recipients: []honeycomb.NotificationRecipient{{ID: "r2"}}},
+				})
+			case "GET /1/slos/api":
+				_ = json.NewEncoder(w).Encode([]honeycomb.SLO{{ID: "s1", Name: "Availability"}})
+			case "GET /1/burn_alerts/api":
+				_ = json.NewEncoder(w).Encode([]honeycomb.BurnAlert{
+					{ID: "ba1", AlertType: "exhaustion_time", Recipients: []honeycomb.NotificationRecipient{{ID: "r1"}}},
+				})
+			case "GET /1/triggers/__all__":
+				_, _ = w.Write([]byte(`[]`))
+			case "DELETE /1/recipients/r3":
+				deleted = true
+				w.WriteHeader(http.StatusNoContent)
+			default:
+				t.Errorf("unexpected request %v %v", r.Method, r.URL.Path)
+				http.Error(w, `{"error": "not found"}`, http.StatusNotFound)
+			}
+		}))
+		defer server.Close()
+
+		var buf bytes.Buffer
+		root := cmd.NewRootCommand()
+		root.SetOut(&buf)
+		root.SetArgs([]string{"recipients", "delete", "r3", "--api-key", "test", "--api-url", server.URL})
+
+		err := root.Execute()
+		is.NotError(t, err)
+		is.True(t, deleted)
+		is.True(t, contains(buf.String(), "Deleted recipient r3"))
+	})
+}
@@ -0,0 +1,277 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"sync"
+	"time"
+
+	"github.com/spf13/cobra"
+	collogspb "go.opentelemetry.io/proto/otlp/collector/logs/v1"
+	coltracepb "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+
+	"github.com/maragudk/honeycomb-cli/honeycomb"
+)
+
+// relayRetryBackoff is the wait before the first retry of a failed batch, doubling for each retry after that.
+const relayRetryBackoff = 100 * time.Millisecond
+
+// relayBatcher collects events per dataset and sends them in batches, when a batch is full or on the flush interval.
+type relayBatcher struct {
+	c             *honeycomb.Client
+	batchSize     int
+	flushInterval time.Duration
+	maxRetries    int
+	events        chan relayEvent
+	out           io.Writer
+	errOut        io.Writer
+	mu            sync.Mutex
+
+	// done is closed when the batcher stops accepting events. queueMu guards sending on events
+	// against closing it, so enqueue never sends on a closed channel.
+	done    chan struct{}
+	queueMu sync.RWMutex
+	closed  bool
+}
+
+// errRelayStopped is returned by [relayBatcher.enqueue] once the relay is shutting down.
+var errRelayStopped = errors.New("relay is shutting down")
+
+// run until the events channel is closed, and then send the remaining events.
+func (b *relayBatcher) run(ctx context.Context) {
+	ticker := time.NewTicker(b.flushInterval)
+	defer ticker.Stop()
+
+	pending := map[string][]honeycomb.Event{}
+	for {
+		select {
+		case e, ok := <-b.events:
+			if !ok {
+				for dataset, events := range pending {
+					b.send(ctx, dataset, events)
+				}
+				return
+			}
+			pending[e.Dataset] = append(pending[e.Dataset], e.Event)
+			if len(pending[e.Dataset]) >= b.batchSize {
+				b.send(ctx, e.Dataset, pending[e.Dataset])
+				delete(pending, e.Dataset)
+			}
+		case <-ticker.C:
+			for dataset, events := range pending {
+				b.send(ctx, dataset, events)
+			}
+			clear(pending)
+		}
+	}
+}
+
+// send a batch of events, retrying with exponential backoff if the API is unavailable or rate limited.
+// Events the API rejects one by one aren't retried.
+func (b *relayBatcher) send(ctx context.Context, dataset string, events []honeycomb.Event) {
+	backoff := relayRetryBackoff
+	for attempt := 0; ; attempt++ {
+		results, err := b.c.SendEvents(honeycomb.WithAttempt(ctx, attempt), dataset, events)
+		if err == nil {
+			var failed int
+			for _, r := range results {
+				if r.Status != http.StatusAccepted {
+					failed++
+					b.logf(b.errOut, "Error sending event to %v: %v (status %v)\n", dataset, r.Error, r.Status)
+				}
+			}
+			b.logf(b.out, "Sent %v events to %v\n", len(events)-failed, dataset)
+			return
+		}
+
+		if attempt >= b.maxRetries || !retryableRelayError(err) {
+			b.logf(b.errOut, "Error sending %v events to %v, dropping them: %v\n", len(events), dataset, err)
+			return
+		}
+		b.logf(b.errOut, "Error sending %v events to %v, retrying in %v: %v\n", len(events), dataset, backoff, err)
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+}
+
+// retryableRelayError is true for rate limiting, server errors, and errors without an API response, like timeouts.
+func retryableRelayError(err error) bool {
+	var apiErr *honeycomb.APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.StatusCode == http.StatusTooManyRequests || apiErr.StatusCode >= 500
+	}
+	return true
+}
+
+func (b *relayBatcher) logf(w io.Writer, format string, args ...any) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	fmt.Fprintf(w, format, args...)
+}
+
+// enqueue the events, waiting for room in the queue for as long as the request is open
+// and the batcher accepts events.
+func (b *relayBatcher) enqueue(ctx context.Context, events []relayEvent) error {
+	b.queueMu.RLock()
+	defer b.queueMu.RUnlock()
+
+	if b.closed {
+		return errRelayStopped
+	}
+	for _, e := range events {
+		select {
+		case b.events <- e:
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-b.done:
+			return errRelayStopped
+		}
+	}
+	return nil
+}
+
+// close the events channel once no handler is sending on it, so run sends the remaining events and returns.
+func (b *relayBatcher) close() {
+	close(b.done)
+
+	b.queueMu.Lock()
+	defer b.queueMu.Unlock()
+	b.closed = true
+	close(b.events)
+}
+
+// newRelayHandler for OTLP/HTTP trace and log exports.
+func newRelayHandler(b *relayBatcher, dataset string) http.Handler {
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("POST /v1/traces", func(w http.ResponseWriter, r *http.Request) {
+		var req coltracepb.ExportTraceServiceRequest
+		if err := decodeOTLPRequest(r, &req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if err := b.enqueue(r.Context(), flattenTraces(&req, dataset)); err != nil {
+			http.Error(w, err.Error(), http.StatusServiceUnavailable)
+			return
+		}
+		encodeOTLPResponse(w, r, &coltracepb.ExportTraceServiceResponse{})
+	})
+
+	mux.HandleFunc("POST /v1/logs", func(w http.ResponseWriter, r *http.Request) {
+		var req collogspb.ExportLogsServiceRequest
+		if err := decodeOTLPRequest(r, &req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if err := b.enqueue(r.Context(), flattenLogs(&req, dataset)); err != nil {
+			http.Error(w, err.Error(), http.StatusServiceUnavailable)
+			return
+		}
+		encodeOTLPResponse(w, r, &collogspb.ExportLogsServiceResponse{})
+	})
+
+	return http.MaxBytesHandler(mux, maxOTLPRequestSize)
+}
+
+func newRelayCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "relay",
+		Short: "Receive OTLP traces and logs and forward them to Honeycomb as events",
+		Long: `Run an OTLP/HTTP receiver for traces and logs, in protobuf or JSON, and forward them to Honeycomb
+through the batch events API, so local apps can send telemetry without running a collector.
+
+Spans and log records become events with their resource, scope, and own attributes, and fields like
+trace.trace_id, trace.parent_id, name, and duration_ms. Span events and links become events annotating
+their span. Events go to the dataset named after the service.name resource attribute, unless --dataset is set.
+
+Events are sent when a batch is full or on the flush interval, and batches are retried if the API is
+unavailable or rate limiting. Sending events needs an ingest API key.
+
+Example:
+  honeycomb-cli relay --dataset dev
+  OTEL_EXPORTER_OTLP_ENDPOINT=http://localhost:4318 ./my-app`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			addr, _ := cmd.Flags().GetString("addr")
+			dataset, _ := cmd.Flags().GetString("dataset")
+			batchSize, _ := cmd.Flags().GetInt("batch-size")
+			flushInterval, _ := cmd.Flags().GetDuration("flush-interval")
+			maxRetries, _ := cmd.Flags().GetInt("max-retries")
+			if batchSize < 1 {
+				return fmt.Errorf("invalid --batch-size %v (must be 1 or more)", batchSize)
+			}
+			if flushInterval <= 0 {
+				return fmt.Errorf("invalid --flush-interval %v (must be more than 0)", flushInterval)
+			}
+
+			ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt)
+			defer stop()
+
+			listener, err := net.Listen("tcp", addr)
+			if err != nil {
+				return err
+			}
+
+			b := &relayBatcher{
+				c:             newClient(cmd),
+				batchSize:     batchSize,
+				flushInterval: flushInterval,
+				maxRetries:    maxRetries,
+				events:        make(chan relayEvent, 10*batchSize),
+				done:          make(chan struct{}),
+				out:           cmd.OutOrStdout(),
+				errOut:        cmd.ErrOrStderr(),
+			}
+			batcherDone := make(chan struct{})
+			go func() {
+				// Keep sending after interruption, so the remaining events are flushed
+				b.run(context.WithoutCancel(ctx))
+				close(batcherDone)
+			}()
+
+			server := &http.Server{
+				Handler:           newRelayHandler(b, dataset),
+				ReadHeaderTimeout: 10 * time.Second,
+			}
+
+			b.logf(cmd.ErrOrStderr(), "Listening for OTLP/HTTP traces and logs on http://%v\n", listener.Addr())
+
+			errs := make(chan error, 1)
+			go func() {
+				errs <- server.Serve(listener)
+			}()
+
+			select {
+			case err = <-errs:
+			case <-ctx.Done():
+				shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+				defer cancel()
+				err = server.Shutdown(shutdownCtx)
+				if serveErr := <-errs; err == nil && !errors.Is(serveErr, http.ErrServerClosed) {
+					err = serveErr
+				}
+			}
+
+			// Handlers still running after a shutdown timeout or a serve error get errRelayStopped
+			b.close()
+			<-batcherDone
+			return err
+		},
+	}
+	cmd.Flags().String("addr", "localhost:4318", "Address to listen on")
+	cmd.Flags().String("dataset", "", "Dataset to send all events to (default is the service.name of each resource)")
+	cmd.Flags().Int("batch-size", 100, "Maximum number of events to send at once")
+	cmd.Flags().Duration("flush-interval", time.Second, "Maximum time to wait before sending a batch that isn't full")
+	cmd.Flags().Int("max-retries", 3, "Number of times to retry a batch that fails to send")
+	return cmd
+}
@@ -0,0 +1,331 @@
+package cmd
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+	"net/http"
+	"strings"
+	"time"
+
+	collogspb "go.opentelemetry.io/proto/otlp/collector/logs/v1"
+	coltracepb "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+	commonpb "go.opentelemetry.io/proto/otlp/common/v1"
+	resourcepb "go.opentelemetry.io/proto/otlp/resource/v1"
+	tracepb "go.opentelemetry.io/proto/otlp/trace/v1"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/maragudk/honeycomb-cli/honeycomb"
+)
+
+// relayEvent is an event flattened from OTLP, with the dataset it's sent to.
+type relayEvent struct {
+	Dataset string
+	Event   honeycomb.Event
+}
+
+// maxOTLPRequestSize is the largest OTLP request body accepted, after decompression.
+const maxOTLPRequestSize = 32 << 20
+
+// decodeOTLPRequest from a protobuf or JSON request body, optionally gzipped, into msg.
+func decodeOTLPRequest(r *http.Request, msg proto.Message) error {
+	var body io.Reader = r.Body
+	if r.Header.Get("Content-Encoding") == "gzip" {
+		gz, err := gzip.NewReader(r.Body)
+		if err != nil {
+			return fmt.Errorf("reading gzip body: %w", err)
+		}
+		defer func() {
+			_ = gz.Close()
+		}()
+		body = gz
+	}
+
+	data, err := io.ReadAll(io.LimitReader(body, maxOTLPRequestSize+1))
+	if err != nil {
+		return err
+	}
+	if len(data) > maxOTLPRequestSize {
+		return fmt.Errorf("request body larger than %v bytes", maxOTLPRequestSize)
+	}
+
+	if isJSONContentType(r.Header.Get("Content-Type")) {
+		data, err = otlpJSONIDsToBase64(data)
+		if err != nil {
+			return err
+		}
+		return protojson.UnmarshalOptions{DiscardUnknown: true}.Unmarshal(data, msg)
+	}
+	return proto.Unmarshal(data, msg)
+}
+
+// encodeOTLPResponse in the same encoding as the request.
+func encodeOTLPResponse(w http.ResponseWriter, r *http.Request, msg proto.Message) {
+	var data []byte
+	var err error
+	if isJSONContentType(r.Header.Get("Content-Type")) {
+		w.Header().Set("Content-Type", "application/json")
+		data, err = protojson.Marshal(msg)
+	} else {
+		w.Header().Set("Content-Type", "application/x-protobuf")
+		data, err = proto.Marshal(msg)
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	_, _ = w.Write(data)
+}
+
+func isJSONContentType(contentType string) bool {
+	return strings.HasPrefix(contentType, "application/json")
+}
+
+// otlpIDKeys are the JSON keys of trace and span IDs, which OTLP/JSON encodes as hex instead of protojson's base64.
+var otlpIDKeys = map[string]bool{
+	"traceId": true, "spanId": true, "parentSpanId": true,
+	"trace_id": true, "span_id": true, "parent_span_id": true,
+}
+
+// otlpJSONIDsToBase64 rewrites the hex trace and span IDs in OTLP/JSON to base64, so protojson can decode them.
+func otlpJSONIDsToBase64(data []byte) ([]byte, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var v any
+	if err := decoder.Decode(&v); err != nil {
+		return nil, err
+	}
+
+	var convert func(v any) error
+	convert = func(v any) error {
+		switch v := v.(type) {
+		case map[string]any:
+			for k, child := range v {
+				if s, ok := child.(string); ok && otlpIDKeys[k] {
+					id, err := hex.DecodeString(s)
+					if err != nil {
+						return fmt.Errorf("invalid %v %q: %w", k, s, err)
+					}
+					v[k] = base64.StdEncoding.EncodeToString(id)
+					continue
+				}
+				if err := convert(child); err != nil {
+					return err
+				}
+			}
+		case []any:
+			for _, child := range v {
+				if err := convert(child); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	}
+	if err := convert(v); err != nil {
+		return nil, err
+	}
+	return json.Marshal(v)
+}
+
+// otlpDataset for the events of a resource: the dataset flag if set, or else the service name, like Honeycomb does.
+func otlpDataset(dataset string, resource *resourcepb.Resource) string {
+	if dataset != "" {
+		return dataset
+	}
+	for _, kv := range resource.GetAttributes() {
+		if kv.Key == "service.name" {
+			if name := strings.TrimSpace(kv.Value.GetStringValue()); name != "" && !strings.HasPrefix(name, "unknown_service") {
+				return name
+			}
+		}
+	}
+	return "unknown_service"
+}
+
+// otlpCommonFields are the resource and scope attributes that every event from them gets.
+func otlpCommonFields(signal string, resource *resourcepb.Resource, scope *commonpb.InstrumentationScope) map[string]any {
+	fields := map[string]any{"meta.signal_type": signal}
+	addOTLPAttributes(fields, resource.GetAttributes())
+	if scope.GetName() != "" {
+		fields["library.name"] = scope.GetName()
+	}
+	if scope.GetVersion() != "" {
+		fields["library.version"] = scope.GetVersion()
+	}
+	addOTLPAttributes(fields, scope.GetAttributes())
+	return fields
+}
+
+// flattenTraces into an event per span, and an event per span event and span link, annotating its span.
+func flattenTraces(req *coltracepb.ExportTraceServiceRequest, dataset string) []relayEvent {
+	var events []relayEvent
+	for _, rs := range req.GetResourceSpans() {
+		ds := otlpDataset(dataset, rs.GetResource())
+		for _, ss := range rs.GetScopeSpans() {
+			common := otlpCommonFields("trace", rs.GetResource(), ss.GetScope())
+			for _, span := range ss.GetSpans() {
+				events = append(events, flattenSpan(span, common, ds)...)
+			}
+		}
+	}
+	return events
+}
+
+func flattenSpan(span *tracepb.Span, common map[string]any, dataset string) []relayEvent {
+	start := unixNano(span.GetStartTimeUnixNano())
+	traceID := hex.EncodeToString(span.GetTraceId())
+	spanID := hex.EncodeToString(span.GetSpanId())
+
+	fields := maps.Clone(common)
+	fields["trace.trace_id"] = traceID
+	fields["trace.span_id"] = spanID
+	if len(span.GetParentSpanId()) > 0 {
+		fields["trace.parent_id"] = hex.EncodeToString(span.GetParentSpanId())
+	}
+	fields["name"] = span.GetName()
+	fields["span.kind"] = strings.ToLower(strings.TrimPrefix(span.GetKind().String(), "SPAN_KIND_"))
+	fields["duration_ms"] = float64(span.GetEndTimeUnixNano()-span.GetStartTimeUnixNano()) / float64(time.Millisecond)
+	fields["status_code"] = int(span.GetStatus().GetCode())
+	if span.GetStatus().GetMessage() != "" {
+		fields["status_message"] = span.GetStatus().GetMessage()
+	}
+	if span.GetStatus().GetCode() == tracepb.Status_STATUS_CODE_ERROR {
+		fields["error"] = true
+	}
+	if len(span.GetEvents()) > 0 {
+		fields["span.num_events"] = len(span.GetEvents())
+	}
+	if len(span.GetLinks()) > 0 {
+		fields["span.num_links"] = len(span.GetLinks())
+	}
+	addOTLPAttributes(fields, span.GetAttributes())
+
+	events := []relayEvent{{Dataset: dataset, Event: honeycomb.Event{Time: start, Data: fields}}}
+
+	for _, e := range span.GetEvents() {
+		eventFields := maps.Clone(common)
+		eventFields["meta.annotation_type"] = "span_event"
+		eventFields["trace.trace_id"] = traceID
+		eventFields["trace.parent_id"] = spanID
+		eventFields["name"] = e.GetName()
+		eventFields["parent_name"] = span.GetName()
+		addOTLPAttributes(eventFields, e.GetAttributes())
+		events = append(events, relayEvent{Dataset: dataset, Event: honeycomb.Event{Time: unixNano(e.GetTimeUnixNano()), Data: eventFields}})
+	}
+
+	for _, l := range span.GetLinks() {
+		linkFields := maps.Clone(common)
+		linkFields["meta.annotation_type"] = "link"
+		linkFields["trace.trace_id"] = traceID
+		linkFields["trace.parent_id"] = spanID
+		linkFields["trace.link.trace_id"] = hex.EncodeToString(l.GetTraceId())
+		linkFields["trace.link.span_id"] = hex.EncodeToString(l.GetSpanId())
+		linkFields["parent_name"] = span.GetName()
+		addOTLPAttributes(linkFields, l.GetAttributes())
+		events = append(events, relayEvent{Dataset: dataset, Event: honeycomb.Event{Time: start, Data: linkFields}})
+	}
+
+	return events
+}
+
+// flattenLogs into an event per log record. Log records in a span get the span as their parent.
+func flattenLogs(req *collogspb.ExportLogsServiceRequest, dataset string) []relayEvent {
+	var events []relayEvent
+	for _, rl := range req.GetResourceLogs() {
+		ds := otlpDataset(dataset, rl.GetResource())
+		for _, sl := range rl.GetScopeLogs() {
+			common := otlpCommonFields("log", rl.GetResource(), sl.GetScope())
+			for _, record := range sl.GetLogRecords() {
+				fields := maps.Clone(common)
+				if record.GetSeverityText() != "" {
+					fields["severity"] = record.GetSeverityText()
+				}
+				if record.GetSeverityNumber() != 0 {
+					fields["severity_code"] = int(record.GetSeverityNumber())
+				}
+				if record.GetBody() != nil {
+					fields["body"] = otlpValue(record.GetBody())
+				}
+				if record.GetEventName() != "" {
+					fields["name"] = record.GetEventName()
+				}
+				if len(record.GetTraceId()) > 0 {
+					fields["trace.trace_id"] = hex.EncodeToString(record.GetTraceId())
+				}
+				if len(record.GetSpanId()) > 0 {
+					fields["trace.parent_id"] = hex.EncodeToString(record.GetSpanId())
+				}
+				addOTLPAttributes(fields, record.GetAttributes())
+
+				t := record.GetTimeUnixNano()
+				if t == 0 {
+					t = record.GetObservedTimeUnixNano()
+				}
+				events = append(events, relayEvent{Dataset: ds, Event: honeycomb.Event{Time: unixNano(t), Data: fields}})
+			}
+		}
+	}
+	return events
+}
+
+func addOTLPAttributes(fields map[string]any, attrs []*commonpb.KeyValue) {
+	for _, kv := range attrs {
+		fields[kv.GetKey()] = otlpValue(kv.GetValue())
+	}
+}
+
+// otlpValue as an event field value. Arrays and maps are JSON-encoded, since event fields are flat.
+func otlpValue(v *commonpb.AnyValue) any {
+	switch v.GetValue().(type) {
+	case *commonpb.AnyValue_StringValue:
+		return v.GetStringValue()
+	case *commonpb.AnyValue_BoolValue:
+		return v.GetBoolValue()
+	case *commonpb.AnyValue_IntValue:
+		return v.GetIntValue()
+	case *commonpb.AnyValue_DoubleValue:
+		return v.GetDoubleValue()
+	case *commonpb.AnyValue_BytesValue:
+		return base64.StdEncoding.EncodeToString(v.GetBytesValue())
+	case *commonpb.AnyValue_ArrayValue, *commonpb.AnyValue_KvlistValue:
+		data, _ := json.Marshal(otlpNestedValue(v))
+		return string(data)
+	default:
+		return nil
+	}
+}
+
+// otlpNestedValue of arrays and maps, for JSON encoding.
+func otlpNestedValue(v *commonpb.AnyValue) any {
+	switch v.GetValue().(type) {
+	case *commonpb.AnyValue_ArrayValue:
+		values := []any{}
+		for _, child := range v.GetArrayValue().GetValues() {
+			values = append(values, otlpNestedValue(child))
+		}
+		return values
+	case *commonpb.AnyValue_KvlistValue:
+		values := map[string]any{}
+		for _, kv := range v.GetKvlistValue().GetValues() {
+			values[kv.GetKey()] = otlpNestedValue(kv.GetValue())
+		}
+		return values
+	default:
+		return otlpValue(v)
+	}
+}
+
+// unixNano as a time, or the zero time for zero, which the events API replaces with the time it's received.
+func unixNano(ns uint64) time.Time {
+	if ns == 0 {
+		return time.Time{}
+	}
+	return time.Unix(0, int64(ns)).UTC()
+}
@@ -0,0 +1,342 @@
+package cmd_test
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	coltracepb "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+	commonpb "go.opentelemetry.io/proto/otlp/common/v1"
+	resourcepb "go.opentelemetry.io/proto/otlp/resource/v1"
+	tracepb "go.opentelemetry.io/proto/otlp/trace/v1"
+	"google.golang.org/protobuf/proto"
+	"maragu.dev/is"
+
+	"github.com/maragudk/honeycomb-cli/cmd"
+)
+
+// relayBatch is a batch of events received by the fake batch events API.
+type relayBatch struct {
+	Dataset string
+	Events  []struct {
+		Time string         `json:"time"`
+		Data map[string]any `json:"data"`
+	}
+}
+
+// newBatchAPI is a fake batch events API that fails the first failures requests with 503,
+// and returns a function for the batches it accepted.
+func newBatchAPI(t *testing.T, failures int32) (*httptest.Server, func() []relayBatch) {
+	t.Helper()
+	var lock sync.Mutex
+	var batches []relayBatch
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if requests.Add(1) <= failures {
+			http.Error(w, `{"error": "unavailable"}`, http.StatusServiceUnavailable)
+			return
+		}
+
+		batch := relayBatch{Dataset: strings.TrimPrefix(r.URL.Path, "/1/batch/")}
+		is.NotError(t, json.NewDecoder(r.Body).Decode(&batch.Events))
+
+		lock.Lock()
+		batches = append(batches, batch)
+		lock.Unlock()
+
+		var results []map[string]any
+		for range batch.Events {
+			results = append(results, map[string]any{"status": 202})
+		}
+		_ = json.NewEncoder(w).Encode(results)
+	}))
+	t.Cleanup(server.Close)
+	return server, func() []relayBatch {
+		lock.Lock()
+		defer lock.Unlock()
+		return batches
+	}
+}
+
+// startRelay runs relay with the given flags until the returned function is called,
+// which returns its output and error output.
+func startRelay(t *testing.T, apiURL string, flags ...string) (string, func() (string, string)) {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	is.NotError(t, err)
+	addr := listener.Addr().String()
+	_ = listener.Close()
+
+	ctx, cancel := context.WithCancel(t.Context())
+	var out, errOut syncBuffer
+	root := cmd.NewRootCommand()
+	root.SetOut(&out)
+	root.SetErr(&errOut)
+	root.SetArgs(append([]string{"relay", "--addr", addr, "--api-key", "test", "--api-url", apiURL}, flags...))
+
+	done := make(chan error, 1)
+	go func() {
+		done <- root.ExecuteContext(ctx)
+	}()
+
+	url := "http://" + addr
+	for range 100 {
+		if res, err := http.Get(url); err == nil {
+			_ = res.Body.Close()
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	return url, func() (string, string) {
+		cancel()
+		is.NotError(t, <-done)
+		return out.String(), errOut.String()
+	}
+}
+
+// syncBuffer is a bytes.Buffer that's safe for concurrent use.
+type syncBuffer struct {
+	lock sync.Mutex
+	buf  bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	return b.buf.String()
+}
+
+func postOTLP(t *testing.T, url, contentType string, body []byte, gzipped bool) *http.Response {
+	t.Helper()
+	if gzipped {
+		var buf bytes.Buffer
+		gz := gzip.NewWriter(&buf)
+		_, _ = gz.Write(body)
+		_ = gz.Close()
+		body = buf.Bytes()
+	}
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(body))
+	is.NotError(t, err)
+	req.Header.Set("Content-Type", contentType)
+	if gzipped {
+		req.Header.Set("Content-Encoding", "gzip")
+	}
+	res, err := http.DefaultClient.Do(req)
+	is.NotError(t, err)
+	_ = res.Body.Close()
+	return res
+}
+
+func stringAttr(key, value string) *commonpb.KeyValue {
+	return &commonpb.KeyValue{Key: key, Value: &commonpb.AnyValue{Value: &commonpb.AnyValue_StringValue{StringValue: value}}}
+}
+
+func TestRelayCommand(t *testing.T) {
+	traceID := []byte{0x5b, 0x8e, 0xfc, 0xf5, 0x26, 0x10, 0x4c, 0x3c, 0x8a, 0x27, 0xfa, 0xc6, 0x4d, 0x2c, 0x0b, 0x1a}
+	start := time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+
+	t.Run("forwards protobuf spans as events to the service's dataset", func(t *testing.T) {
+		api, batches := newBatchAPI(t, 0)
+		url, stop := startRelay(t, api.URL, "--flush-interval", "10ms")
+
+		body, err := proto.Marshal(&coltracepb.ExportTraceServiceRequest{ResourceSpans: []*tracepb.ResourceSpans{{
+			Resource: &resourcepb.Resource{Attributes: []*commonpb.KeyValue{stringAttr("service.name", "checkout")}},
+			ScopeSpans: []*tracepb.ScopeSpans{{
+				Scope: &commonpb.InstrumentationScope{Name: "net/http", Version: "1.0.0"},
+				Spans: []*tracepb.Span{{
+					TraceId:           traceID,
+					SpanId:            []byte{1, 2, 3, 4, 5, 6, 7, 8},
+					ParentSpanId:      []byte{8, 7, 6, 5, 4, 3, 2, 1},
+					Name:              "GET /cart",
+					Kind:              tracepb.Span_SPAN_KIND_SERVER,
+					StartTimeUnixNano: uint64(start.UnixNano()),
+					EndTimeUnixNano:   uint64(start.Add(1500 * time.Microsecond).UnixNano()),
+					Attributes: []*commonpb.KeyValue{
+						stringAttr("http.route", "/cart"),
+						{Key: "http.response.status_code", Value: &commonpb.AnyValue{Value: &commonpb.AnyValue_IntValue{IntValue: 500}}},
+					},
+					Status: &tracepb.Status{Code: tracepb.Status_STATUS_CODE_ERROR, Message: "boom"},
+					Events: []*tracepb.Span_Event{{TimeUnixNano: uint64(start.UnixNano()), Name: "exception"}},
+				}},
+			}},
+		}}})
+		is.NotError(t, err)
+
+		res := postOTLP(t, url+"/v1/traces", "application/x-protobuf", body, false)
+		is.Equal(t, http.StatusOK, res.StatusCode)
+		is.Equal(t, "application/x-protobuf", res.Header.Get("Content-Type"))
+
+		out, _ := stop()
+		is.True(t, contains(out, "Sent 2 events to checkout"))
+
+		received := batches()
+		is.Equal(t, 1, len(received))
+		is.Equal(t, "checkout", received[0].Dataset)
+		is.Equal(t, 2, len(received[0].Events))
+
+		span := received[0].Events[0]
+		is.Equal(t, "2026-10-19T12:00:00Z", span.Time)
+		is.Equal(t, "5b8efcf526104c3c8a27fac64d2c0b1a", span.Data["trace.trace_id"].(string))
+		is.Equal(t, "0102030405060708", span.Data["trace.span_id"].(string))
+		is.Equal(t, "0807060504030201", span.Data["trace.parent_id"].(string))
+		is.Equal(t, "GET /cart", span.Data["name"].(string))
+		is.Equal(t, "server", span.Data["span.kind"].(string))
+		is.Equal(t, 1.5, span.Data["duration_ms"].(float64))
+		is.Equal(t, true, span.Data["error"].(bool))
+		is.Equal(t, "boom", span.Data["status_message"].(string))
+		is.Equal(t, "checkout", span.Data["service.name"].(string))
+		is.Equal(t, "net/http", span.Data["library.name"].(string))
+		is.Equal(t, "/cart", span.Data["http.route"].(string))
+		is.Equal(t, float64(500), span.Data["http.response.status_code"].(float64))
+		is.Equal(t, "trace", span.Data["meta.signal_type"].(string))
+
+		event := received[0].Events[1]
+		is.Equal(t, "span_event", event.Data["meta.annotation_type"].(string))
+		is.Equal(t, "exception", event.Data["name"].(string))
+		is.Equal(t, "0102030405060708", event.Data["trace.parent_id"].(string))
+		is.Equal(t, "GET /cart", event.Data["parent_name"].(string))
+	})
+
+	t.Run("forwards gzipped JSON logs to the given dataset", func(t *testing.T) {
+		api, batches := newBatchAPI(t, 0)
+		url, stop := startRelay(t, api.URL, "--dataset", "dev", "--flush-interval", "10ms")
+
+		body := `{"resourceLogs": [{
+			"resource": {"attributes": [{"key": "service.name", "value": {"stringValue": "checkout"}}]},
+			"scopeLogs": [{"logRecords": [{
+				"timeUnixNano": "1792411200000000000",
+				"severityNumber": 17,
+				"severityText": "ERROR",
+				"body": {"stringValue": "payment failed"},
+				"traceId": "5b8efcf526104c3c8a27fac64d2c0b1a",
+				"spanId": "0102030405060708",
+				"attributes": [{"key": "order", "value": {"kvlistValue": {"values": [{"key": "id", "value": {"intValue": "42"}}]}}}]
+			}]}]
+		}]}`
+		res := postOTLP(t, url+"/v1/logs", "application/json", []byte(body), true)
+		is.Equal(t, http.StatusOK, res.StatusCode)
+		is.Equal(t, "application/json", res.Header.Get("Content-Type"))
+
+		stop()
+
+		received := batches()
+		is.Equal(t, 1, len(received))
+		is.Equal(t, "dev", received[0].Dataset)
+
+		log := received[0].Events[0]
+		is.Equal(t, "2026-10-19T12:00:00Z", log.Time)
+		is.Equal(t, "payment failed", log.Data["body"].(string))
+		is.Equal(t, "ERROR", log.Data["severity"].(string))
+		is.Equal(t, float64(17), log.Data["severity_code"].(float64))
+		is.Equal(t, "5b8efcf526104c3c8a27fac64d2c0b1a", log.Data["trace.trace_id"].(string))
+		is.Equal(t, "0102030405060708", log.Data["trace.parent_id"].(string))
+		is.Equal(t, `{"id":42}`, log.Data["order"].(string))
+		is.Equal(t, "log", log.Data["meta.signal_type"].(string))
+	})
+
+	t.Run("sends full batches without waiting for the flush interval", func(t *testing.T) {
+		api, batches := newBatchAPI(t, 0)
+		url, stop := startRelay(t, api.URL, "--batch-size", "2", "--flush-interval", "1h")
+
+		var spans []*tracepb.Span
+		for _, name := range []string{"a", "b", "c"} {
+			spans = append(spans, &tracepb.Span{TraceId: traceID, SpanId: []byte{1, 2, 3, 4, 5, 6, 7, 8}, Name: name})
+		}
+		body, err := proto.Marshal(&coltracepb.ExportTraceServiceRequest{ResourceSpans: []*tracepb.ResourceSpans{{
+			ScopeSpans: []*tracepb.ScopeSpans{{Spans: spans}},
+		}}})
+		is.NotError(t, err)
+		postOTLP(t, url+"/v1/traces", "application/x-protobuf", body, false)
+
+		for range 100 {
+			if len(batches()) > 0 {
+				break
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+		is.Equal(t, 1, len(batches()))
+		is.Equal(t, 2, len(batches()[0].Events))
+		is.Equal(t, "unknown_service", batches()[0].Dataset)
+
+		// The rest is sent on shutdown
+		stop()
+		is.Equal(t, 2, len(batches()))
+		is.Equal(t, 1, len(batches()[1].Events))
+	})
+
+	t.Run("retries batches when the API is unavailable", func(t *testing.T) {
+		api, batches := newBatchAPI(t, 2)
+		url, stop := startRelay(t, api.URL, "--flush-interval", "10ms")
+
+		body, err := proto.Marshal(&coltracepb.ExportTraceServiceRequest{ResourceSpans: []*tracepb.ResourceSpans{{
+			ScopeSpans: []*tracepb.ScopeSpans{{Spans: []*tracepb.Span{{TraceId: traceID, Name: "a"}}}},
+		}}})
+		is.NotError(t, err)
+		postOTLP(t, url+"/v1/traces", "application/x-protobuf", body, false)
+
+		for range 100 {
+			if len(batches()) > 0 {
+				break
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+		_, errOut := stop()
+
+		is.Equal(t, 1, len(batches()))
+		is.Equal(t, 2, strings.Count(errOut, "Error sending 1 events to unknown_service, retrying"))
+	})
+
+	t.Run("drops batches the API rejects without retrying", func(t *testing.T) {
+		var requests atomic.Int32
+		api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requests.Add(1)
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+		}))
+		defer api.Close()
+		url, stop := startRelay(t, api.URL, "--flush-interval", "10ms")
+
+		body, err := proto.Marshal(&coltracepb.ExportTraceServiceRequest{ResourceSpans: []*tracepb.ResourceSpans{{
+			ScopeSpans: []*tracepb.ScopeSpans{{Spans: []*tracepb.Span{{TraceId: traceID, Name: "a"}}}},
+		}}})
+		is.NotError(t, err)
+		postOTLP(t, url+"/v1/traces", "application/x-protobuf", body, false)
+
+		for range 100 {
+			if requests.Load() > 0 {
+				break
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+		_, errOut := stop()
+
+		is.Equal(t, int32(1), requests.Load())
+		is.True(t, contains(errOut, "Error sending 1 events to unknown_service, dropping them"))
+		is.True(t, !contains(errOut, "retrying"))
+	})
+
+	t.Run("rejects invalid requests", func(t *testing.T) {
+		api, _ := newBatchAPI(t, 0)
+		url, stop := startRelay(t, api.URL)
+		defer stop()
+
+		res := postOTLP(t, url+"/v1/traces", "application/json", []byte(`{"resourceSpans": [{"scopeSpans": [{"spans": [{"traceId": "xyz"}]}]}]}`), false)
+		is.Equal(t, http.StatusBadRequest, res.StatusCode)
+	})
+}
@@ -1,10 +1,14 @@
 package cmd
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"os"
+	"time"
 
 	"github.com/spf13/cobra"
+	"go.opentelemetry.io/otel/trace"
 
 	"github.com/maragudk/honeycomb-cli/honeycomb"
 )
@@ -17,10 +21,32 @@ func NewRootCommand() *cobra.Command {
 		Long:          "A command-line interface for interacting with the Honeycomb.io observability platform.",
 		SilenceUsage:  true,
 		SilenceErrors: true,
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			rps, _ := cmd.Flags().GetFloat64("rps")
+			if rps < 0 {
+				return fmt.Errorf("invalid --rps %v (must be 0 or more)", rps)
+			}
+			if rps > 0 {
+				// One limiter for the whole command, shared by all its clients
+				l := honeycomb.NewRateLimiter(honeycomb.RateLimits{Default: honeycomb.RateLimit{RequestsPerSecond: rps}})
+				cmd.SetContext(context.WithValue(cmd.Context(), rateLimiterContextKey{}, l))
+			}
+			if tracingEnabled(cmd) {
+				return startTracing(cmd)
+			}
+			return nil
+		},
 	}
 
 	root.PersistentFlags().String("api-key", "", "Honeycomb API key (or set HONEYCOMB_API_KEY)")
 	root.PersistentFlags().String("api-url", "https://api.honeycomb.io", "Honeycomb API URL (or set HONEYCOMB_API_URL)")
+	root.PersistentFlags().Duration("query-timeout", 10*time.Minute, "Maximum time to wait for query results (0 for no limit)")
+	root.PersistentFlags().Float64("rps", 0, "Maximum API requests per second (0 for no limit)")
+	root.PersistentFlags().Bool("otel", false, "Export traces of the command and its API requests over OTLP/HTTP (or set OTEL_* variables)")
+	root.PersistentFlags().String("otel-endpoint", "", "OTLP/HTTP endpoint URL for traces (default is OTEL_EXPORTER_OTLP_ENDPOINT or localhost:4318)")
+	root.PersistentFlags().Bool("cache", false, "Cache query results on disk (or set HONEYCOMB_CACHE=true)")
+	root.PersistentFlags().Bool("no-cache", false, "Don't use cached query results, even with --cache or HONEYCOMB_CACHE")
+	root.PersistentFlags().Duration("cache-ttl", 5*time.Minute, "How long cached query results are used")
 
 	root.AddCommand(newVersionCommand())
 	root.AddCommand(newAuthCommand())
@@ -28,8 +54,20 @@ func NewRootCommand() *cobra.Command {
 	root.AddCommand(newMarkersCommand())
 	root.AddCommand(newColumnsCommand())
 	root.AddCommand(newQueryCommand())
+	root.AddCommand(newExploreCommand())
 	root.AddCommand(newSLOsCommand())
 	root.AddCommand(newTriggersCommand())
+	root.AddCommand(newBoardsCommand())
+	root.AddCommand(newDerivedColumnsCommand())
+	root.AddCommand(newRecipientsCommand())
+	root.AddCommand(newWebhookCommand())
+	root.AddCommand(newRelayCommand())
+	root.AddCommand(newExportCommand())
+	root.AddCommand(newImportCommand())
+	root.AddCommand(newPlanCommand())
+	root.AddCommand(newApplyCommand())
+	root.AddCommand(newLintCommand())
+	root.AddCommand(newCacheCommand())
 
 	return root
 }
@@ -38,11 +76,29 @@ func NewRootCommand() *cobra.Command {
 func Execute() int {
 	if err := NewRootCommand().Execute(); err != nil {
 		fmt.Fprintln(os.Stderr, err)
+		var exitErr *ExitError
+		if errors.As(err, &exitErr) {
+			return exitErr.Code
+		}
 		return 1
 	}
 	return 0
 }
 
+// ExitError makes [Execute] exit with Code instead of 1, for commands whose failures scripts tell apart.
+type ExitError struct {
+	Code int
+	Err  error
+}
+
+func (e *ExitError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *ExitError) Unwrap() error {
+	return e.Err
+}
+
 // apiKey returns the API key from the flag or environment variable.
 func apiKey(cmd *cobra.Command) string {
 	key, _ := cmd.Flags().GetString("api-key")
@@ -64,7 +120,27 @@ func apiURL(cmd *cobra.Command) string {
 	return url
 }
 
-// newClient creates a new Honeycomb API client from the command's flags.
-func newClient(cmd *cobra.Command) *honeycomb.Client {
-	return honeycomb.NewClient(apiKey(cmd), honeycomb.WithBaseURL(apiURL(cmd)))
+// newClient creates a new Honeycomb API client from the command's flags, and the given options.
+func newClient(cmd *cobra.Command, opts ...honeycomb.Option) *honeycomb.Client {
+	opts = append([]honeycomb.Option{
+		honeycomb.WithBaseURL(apiURL(cmd)),
+		honeycomb.WithRunQueryOptions(runQueryOptions(cmd, nil)),
+		honeycomb.WithQueryCache(queryCache(cmd)),
+	}, opts...)
+	if l, ok := cmd.Context().Value(rateLimiterContextKey{}).(*honeycomb.RateLimiter); ok {
+		opts = append(opts, honeycomb.WithRateLimiter(l))
+	}
+	if tp, ok := cmd.Context().Value(tracerProviderContextKey{}).(trace.TracerProvider); ok {
+		opts = append(opts, honeycomb.WithTracerProvider(tp))
+	}
+	return honeycomb.NewClient(apiKey(cmd), opts...)
+}
+
+// rateLimiterContextKey is the context key for the command's [honeycomb.RateLimiter], set with --rps.
+type rateLimiterContextKey struct{}
+
+// runQueryOptions from the command's flags, with an optional progress callback.
+func runQueryOptions(cmd *cobra.Command, progress func(honeycomb.QueryProgress)) honeycomb.RunQueryOptions {
+	timeout, _ := cmd.Flags().GetDuration("query-timeout")
+	return honeycomb.RunQueryOptions{Timeout: timeout, Progress: progress}
 }
@@ -1,11 +1,17 @@
 package cmd_test
 
 import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
 	"testing"
+	"time"
 
 	"maragu.dev/is"
 
 	"github.com/maragudk/honeycomb-cli/cmd"
+	"github.com/maragudk/honeycomb-cli/honeycomb"
 )
 
 func TestExecute(t *testing.T) {
@@ -14,3 +20,43 @@ func TestExecute(t *testing.T) {
 		is.Equal(t, 0, code)
 	})
 }
+
+func TestRootCommand_RPS(t *testing.T) {
+	t.Run("limits API requests per second across the command", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch r.Method {
+			case http.MethodPost:
+				if r.URL.Path == "/1/queries/api" || r.URL.Path == "/1/queries/web" || r.URL.Path == "/1/queries/worker" {
+					_ = json.NewEncoder(w).Encode(honeycomb.QueryResponse{ID: "q1"})
+					return
+				}
+				_ = json.NewEncoder(w).Encode(honeycomb.QueryResult{ID: "r1", Complete: true})
+			default:
+				http.NotFound(w, r)
+			}
+		}))
+		defer server.Close()
+
+		root := cmd.NewRootCommand()
+		root.SetOut(&bytes.Buffer{})
+		root.SetErr(&bytes.Buffer{})
+		root.SetArgs([]string{"query", "--dataset", "api,web,worker", "--rps", "4",
+			"--api-key", "test", "--api-url", server.URL})
+
+		// 6 requests with a burst of 4, so the last 2 wait a quarter of a second each
+		start := time.Now()
+		err := root.Execute()
+		is.NotError(t, err)
+		is.True(t, time.Since(start) >= 400*time.Millisecond)
+	})
+
+	t.Run("errors on a negative rate", func(t *testing.T) {
+		root := cmd.NewRootCommand()
+		root.SetOut(&bytes.Buffer{})
+		root.SetArgs([]string{"datasets", "list", "--rps", "-1", "--api-key", "test"})
+
+		err := root.Execute()
+		is.True(t, err != nil)
+		is.Equal(t, "invalid --rps -1 (must be 0 or more)", err.Error())
+	})
+}
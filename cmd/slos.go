@@ -6,6 +6,8 @@ import (
 	"text/tabwriter"
 
 	"github.com/spf13/cobra"
+
+	"github.com/maragudk/honeycomb-cli/honeycomb"
 )
 
 func newSLOsCommand() *cobra.Command {
@@ -69,18 +71,24 @@ func newSLOsGetCommand() *cobra.Command {
 
 			asJSON, _ := cmd.Flags().GetBool("json")
 			if asJSON {
-				return json.NewEncoder(cmd.OutOrStdout()).Encode(slo)
+				if err := json.NewEncoder(cmd.OutOrStdout()).Encode(slo); err != nil {
+					return err
+				}
+			} else {
+				fmt.Fprintf(cmd.OutOrStdout(), "ID:          %v\n", slo.ID)
+				fmt.Fprintf(cmd.OutOrStdout(), "Name:        %v\n", slo.Name)
+				fmt.Fprintf(cmd.OutOrStdout(), "Description: %v\n", slo.Description)
+				fmt.Fprintf(cmd.OutOrStdout(), "Target:      %.2f%%\n", slo.TargetPercent())
+				fmt.Fprintf(cmd.OutOrStdout(), "Period:      %v days\n", slo.TimePeriodDays)
+				fmt.Fprintf(cmd.OutOrStdout(), "SLI:         %v\n", slo.SLI.Alias)
 			}
 
-			fmt.Fprintf(cmd.OutOrStdout(), "ID:          %v\n", slo.ID)
-			fmt.Fprintf(cmd.OutOrStdout(), "Name:        %v\n", slo.Name)
-			fmt.Fprintf(cmd.OutOrStdout(), "Description: %v\n", slo.Description)
-			fmt.Fprintf(cmd.OutOrStdout(), "Target:      %.2f%%\n", slo.TargetPercent())
-			fmt.Fprintf(cmd.OutOrStdout(), "Period:      %v days\n", slo.TimePeriodDays)
-			fmt.Fprintf(cmd.OutOrStdout(), "SLI:         %v\n", slo.SLI.Alias)
-			return nil
+			return showLinks(cmd, c, func(l *honeycomb.UILinks) ([]uiLink, error) {
+				return []uiLink{{Label: "SLO", URL: l.SLO(dataset, slo.ID)}}, nil
+			})
 		},
 	}
 	cmd.Flags().Bool("json", false, "Output as JSON")
+	addLinkFlags(cmd)
 	return cmd
 }
@@ -0,0 +1,116 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/maragudk/honeycomb-cli/honeycomb"
+)
+
+// isTerminal reports whether w is an interactive terminal.
+func isTerminal(w io.Writer) bool {
+	f, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+	info, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
+
+// highlighter makes text stand out in terminal output with ANSI escape codes, if enabled.
+type highlighter bool
+
+// newHighlighter that is enabled when w is a terminal and NO_COLOR isn't set.
+func newHighlighter(w io.Writer) highlighter {
+	return highlighter(isTerminal(w) && os.Getenv("NO_COLOR") == "")
+}
+
+// highlight s in bold. With tabwriter, apply it to whole lines, since escape codes throw off its column widths.
+func (h highlighter) highlight(s string) string {
+	if !h {
+		return s
+	}
+	return "\x1b[1m" + s + "\x1b[0m"
+}
+
+// spinner shows that queries are running, with the elapsed time, on a terminal.
+// It starts on the first progress update and must be stopped before writing other output.
+type spinner struct {
+	w       io.Writer
+	enabled bool
+	start   time.Time
+
+	mu        sync.Mutex
+	resultIDs map[string]bool
+	done      chan struct{}
+	stopped   chan struct{}
+}
+
+// newSpinner that is enabled when w is a terminal.
+func newSpinner(w io.Writer) *spinner {
+	return &spinner{w: w, enabled: isTerminal(w), start: time.Now(), resultIDs: map[string]bool{}}
+}
+
+// progress of a query, for [honeycomb.RunQueryOptions]. Safe for concurrent use.
+func (s *spinner) progress(p honeycomb.QueryProgress) {
+	if !s.enabled {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.resultIDs[p.ResultID] = true
+	if s.done == nil {
+		s.done, s.stopped = make(chan struct{}), make(chan struct{})
+		go s.spin(s.done, s.stopped)
+	}
+}
+
+func (s *spinner) spin(done, stopped chan struct{}) {
+	defer close(stopped)
+
+	ticker := time.NewTicker(100 * time.Millisecond)
+	defer ticker.Stop()
+
+	frames := []rune("⠋⠙⠹⠸⠼⠴⠦⠧⠇⠏")
+	for i := 0; ; i++ {
+		s.mu.Lock()
+		what := fmt.Sprintf("%v queries", len(s.resultIDs))
+		if len(s.resultIDs) == 1 {
+			for id := range s.resultIDs {
+				what = "query result " + id
+			}
+		}
+		fmt.Fprintf(s.w, "\r\x1b[K%c Waiting for %v (%v)", frames[i%len(frames)], what,
+			time.Since(s.start).Round(100*time.Millisecond))
+		s.mu.Unlock()
+
+		select {
+		case <-done:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// stop the spinner and clear its line, if it's running.
+func (s *spinner) stop() {
+	s.mu.Lock()
+	done, stopped := s.done, s.stopped
+	s.done, s.stopped = nil, nil
+	s.resultIDs = map[string]bool{}
+	s.mu.Unlock()
+
+	if done == nil {
+		return
+	}
+	close(done)
+	<-stopped
+	fmt.Fprint(s.w, "\r\x1b[K")
+}
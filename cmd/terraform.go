@@ -0,0 +1,360 @@
+package cmd
+
+import (
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/maragudk/honeycomb-cli/honeycomb"
+)
+
+// hclBlock is a block in a Terraform configuration, such as a resource or import block.
+type hclBlock struct {
+	Type   string
+	Labels []string
+	Attrs  []hclAttr
+	Blocks []hclBlock
+}
+
+// hclAttr is an attribute with a raw HCL expression as its value.
+type hclAttr struct {
+	Name  string
+	Value string
+}
+
+func (b *hclBlock) attr(name, value string) {
+	b.Attrs = append(b.Attrs, hclAttr{Name: name, Value: value})
+}
+
+// optionalString adds a string attribute unless it's empty.
+func (b *hclBlock) optionalString(name, value string) {
+	if value != "" {
+		b.attr(name, hclString(value))
+	}
+}
+
+// optionalInt adds a number attribute unless it's zero.
+func (b *hclBlock) optionalInt(name string, value int) {
+	if value != 0 {
+		b.attr(name, strconv.Itoa(value))
+	}
+}
+
+// write the block at the given indentation level, aligning the attributes like terraform fmt does.
+func (b *hclBlock) write(sb *strings.Builder, level int) {
+	indent := strings.Repeat("  ", level)
+
+	sb.WriteString(indent + b.Type)
+	for _, l := range b.Labels {
+		sb.WriteString(" " + hclString(l))
+	}
+	sb.WriteString(" {\n")
+
+	width := 0
+	for _, a := range b.Attrs {
+		width = max(width, len(a.Name))
+	}
+	for _, a := range b.Attrs {
+		value := strings.ReplaceAll(a.Value, "\n", "\n"+indent+"  ")
+		fmt.Fprintf(sb, "%v  %-*v = %v\n", indent, width, a.Name, value)
+	}
+
+	for _, child := range b.Blocks {
+		sb.WriteString("\n")
+		child.write(sb, level+1)
+	}
+
+	sb.WriteString(indent + "}\n")
+}
+
+// hclString quotes s as an HCL string literal, escaping template sequences.
+// Only the escapes HCL knows are used, so not [strconv.Quote].
+func hclString(s string) string {
+	var sb strings.Builder
+	sb.WriteByte('"')
+	for i, r := range s {
+		switch {
+		case r == '\\':
+			sb.WriteString(`\\`)
+		case r == '"':
+			sb.WriteString(`\"`)
+		case r == '\n':
+			sb.WriteString(`\n`)
+		case r == '\r':
+			sb.WriteString(`\r`)
+		case r == '\t':
+			sb.WriteString(`\t`)
+		case (r == '$' || r == '%') && strings.HasPrefix(s[i+1:], "{"):
+			sb.WriteRune(r)
+			sb.WriteRune(r)
+		case unicode.IsControl(r):
+			fmt.Fprintf(&sb, `\u%04X`, r)
+		default:
+			sb.WriteRune(r)
+		}
+	}
+	sb.WriteByte('"')
+	return sb.String()
+}
+
+var hclIdentifierRegexp = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_-]*$`)
+
+// hclValue renders a JSON value (as decoded into any) as an HCL expression.
+func hclValue(v any) string {
+	switch v := v.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return strconv.FormatBool(v)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case string:
+		return hclString(v)
+	case []any:
+		if len(v) == 0 {
+			return "[]"
+		}
+		var sb strings.Builder
+		sb.WriteString("[\n")
+		for _, e := range v {
+			sb.WriteString("  " + strings.ReplaceAll(hclValue(e), "\n", "\n  ") + ",\n")
+		}
+		sb.WriteString("]")
+		return sb.String()
+	case map[string]any:
+		if len(v) == 0 {
+			return "{}"
+		}
+		keys := slices.Sorted(maps.Keys(v))
+		width := 0
+		for _, k := range keys {
+			width = max(width, len(hclKey(k)))
+		}
+		var sb strings.Builder
+		sb.WriteString("{\n")
+		for _, k := range keys {
+			fmt.Fprintf(&sb, "  %-*v = %v\n", width, hclKey(k), strings.ReplaceAll(hclValue(v[k]), "\n", "\n  "))
+		}
+		sb.WriteString("}")
+		return sb.String()
+	default:
+		return hclString(fmt.Sprint(v))
+	}
+}
+
+func hclKey(k string) string {
+	if hclIdentifierRegexp.MatchString(k) {
+		return k
+	}
+	return hclString(k)
+}
+
+var nonIdentifierRegexp = regexp.MustCompile(`[^a-z0-9_]+`)
+
+// terraformNames hands out unique Terraform resource names per resource type.
+type terraformNames map[string]bool
+
+// name for a resource of the given type, derived from the given parts.
+func (n terraformNames) name(resourceType string, parts ...string) string {
+	name := strings.Trim(nonIdentifierRegexp.ReplaceAllString(strings.ToLower(strings.Join(parts, "_")), "_"), "_")
+	if name == "" || (name[0] >= '0' && name[0] <= '9') {
+		name = "_" + name
+	}
+
+	unique := name
+	for i := 2; n[resourceType+"."+unique]; i++ {
+		unique = fmt.Sprintf("%v_%v", name, i)
+	}
+	n[resourceType+"."+unique] = true
+	return unique
+}
+
+// terraformConfig holds the resource and import blocks generated from an [environmentSnapshot].
+type terraformConfig struct {
+	Resources []hclBlock
+	Imports   []hclBlock
+	names     terraformNames
+}
+
+// resource returns a new resource block with a unique name, along with its address, like "honeycombio_slo.api_latency".
+// Call [terraformConfig.add] once the block is complete.
+func (t *terraformConfig) resource(resourceType string, nameParts ...string) (*hclBlock, string) {
+	name := t.names.name(resourceType, nameParts...)
+	return &hclBlock{Type: "resource", Labels: []string{resourceType, name}}, resourceType + "." + name
+}
+
+// add a resource block from [terraformConfig.resource]. If importID is set, an import block for the resource is added too.
+func (t *terraformConfig) add(b *hclBlock, importID string) {
+	t.Resources = append(t.Resources, *b)
+
+	if importID != "" {
+		t.Imports = append(t.Imports, hclBlock{Type: "import", Attrs: []hclAttr{
+			{Name: "to", Value: b.Labels[0] + "." + b.Labels[1]},
+			{Name: "id", Value: hclString(importID)},
+		}})
+	}
+}
+
+// query adds a honeycombio_query resource for the spec and returns a reference to its ID.
+// Queries are immutable and can't be imported, so Terraform creates them.
+func (t *terraformConfig) query(dataset string, spec honeycomb.QuerySpec, nameParts ...string) (string, error) {
+	value, err := toJSONValue(spec)
+	if err != nil {
+		return "", err
+	}
+
+	b, address := t.resource("honeycombio_query", nameParts...)
+	b.attr("dataset", hclString(dataset))
+	b.attr("query_json", "jsonencode("+hclValue(value)+")")
+	t.add(b, "")
+	return address + ".id", nil
+}
+
+func recipientBlocks(recipients []honeycomb.NotificationRecipient) []hclBlock {
+	var blocks []hclBlock
+	for _, r := range recipients {
+		blocks = append(blocks, hclBlock{Type: "recipient", Attrs: []hclAttr{{Name: "id", Value: hclString(r.ID)}}})
+	}
+	return blocks
+}
+
+// newTerraformConfig for the columns, derived columns, triggers, SLOs with burn alerts, and boards
+// in the snapshot, for use with the Honeycomb Terraform provider.
+func newTerraformConfig(snap *environmentSnapshot) (*terraformConfig, error) {
+	t := &terraformConfig{names: terraformNames{}}
+
+	for _, ds := range snap.Datasets {
+		slug := ds.Dataset.Slug
+
+		for _, col := range ds.Columns {
+			b, _ := t.resource("honeycombio_column", slug, col.KeyName)
+			b.attr("dataset", hclString(slug))
+			b.attr("name", hclString(col.KeyName))
+			b.optionalString("type", col.Type)
+			b.optionalString("description", col.Description)
+			if col.Hidden {
+				b.attr("hidden", "true")
+			}
+			t.add(b, slug+"/"+col.KeyName)
+		}
+
+		for _, dc := range ds.DerivedColumns {
+			b, _ := t.resource("honeycombio_derived_column", slug, dc.Alias)
+			b.attr("dataset", hclString(slug))
+			b.attr("alias", hclString(dc.Alias))
+			b.attr("expression", hclString(dc.Expression))
+			b.optionalString("description", dc.Description)
+			t.add(b, slug+"/"+dc.Alias)
+		}
+
+		for _, tr := range ds.Triggers {
+			var queryRef string
+			if tr.Query != nil {
+				var err error
+				if queryRef, err = t.query(slug, *tr.Query, slug, tr.Name); err != nil {
+					return nil, err
+				}
+			}
+
+			b, _ := t.resource("honeycombio_trigger", slug, tr.Name)
+			b.attr("dataset", hclString(slug))
+			b.attr("name", hclString(tr.Name))
+			b.optionalString("description", tr.Description)
+			if queryRef != "" {
+				b.attr("query_id", queryRef)
+			}
+			b.optionalInt("frequency", tr.Frequency)
+			b.optionalString("alert_type", tr.AlertType)
+			if tr.Disabled {
+				b.attr("disabled", "true")
+			}
+			b.Blocks = append(b.Blocks, hclBlock{Type: "threshold", Attrs: []hclAttr{
+				{Name: "op", Value: hclString(tr.Threshold.Op)},
+				{Name: "value", Value: strconv.FormatFloat(tr.Threshold.Value, 'f', -1, 64)},
+			}})
+			b.Blocks = append(b.Blocks, recipientBlocks(tr.Recipients)...)
+			t.add(b, slug+"/"+tr.ID)
+		}
+
+		for _, slo := range ds.SLOs {
+			b, sloAddress := t.resource("honeycombio_slo", slug, slo.Name)
+			b.attr("dataset", hclString(slug))
+			b.attr("name", hclString(slo.Name))
+			b.optionalString("description", slo.Description)
+			b.attr("sli", hclString(slo.SLI.Alias))
+			b.attr("target_percentage", strconv.FormatFloat(slo.TargetPercent(), 'f', -1, 64))
+			b.attr("time_period", strconv.Itoa(slo.TimePeriodDays))
+			t.add(b, slug+"/"+slo.ID)
+
+			for _, a := range ds.BurnAlerts[slo.ID] {
+				b, _ := t.resource("honeycombio_burn_alert", slug, slo.Name, a.AlertType)
+				b.attr("dataset", hclString(slug))
+				b.attr("slo_id", sloAddress+".id")
+				b.optionalString("alert_type", a.AlertType)
+				b.optionalString("description", a.Description)
+				b.optionalInt("exhaustion_minutes", a.ExhaustionMinutes)
+				b.optionalInt("budget_rate_window_minutes", a.BudgetRateWindowMinutes)
+				if a.BudgetRateDecreaseThresholdPerMillion != 0 {
+					b.attr("budget_rate_decrease_percentage",
+						strconv.FormatFloat(float64(a.BudgetRateDecreaseThresholdPerMillion)/10000, 'f', -1, 64))
+				}
+				b.Blocks = append(b.Blocks, recipientBlocks(a.Recipients)...)
+				t.add(b, slug+"/"+a.ID)
+			}
+		}
+	}
+
+	for _, bs := range snap.Boards {
+		var queries []hclBlock
+		for i, q := range bs.Board.Queries {
+			queryRef, err := t.query(q.Dataset, bs.Specs[i], bs.Board.Name, strconv.Itoa(i+1))
+			if err != nil {
+				return nil, err
+			}
+			qb := hclBlock{Type: "query"}
+			qb.optionalString("caption", q.Caption)
+			qb.optionalString("query_style", q.QueryStyle)
+			qb.attr("dataset", hclString(q.Dataset))
+			qb.attr("query_id", queryRef)
+			queries = append(queries, qb)
+		}
+
+		b, _ := t.resource("honeycombio_board", bs.Board.Name)
+		b.attr("name", hclString(bs.Board.Name))
+		b.optionalString("description", bs.Board.Description)
+		b.optionalString("style", bs.Board.Style)
+		b.optionalString("column_layout", bs.Board.ColumnLayout)
+		b.Blocks = queries
+		t.add(b, bs.Board.ID)
+	}
+
+	return t, nil
+}
+
+func renderHCL(blocks []hclBlock) []byte {
+	var sb strings.Builder
+	for i, b := range blocks {
+		if i > 0 {
+			sb.WriteString("\n")
+		}
+		b.write(&sb, 0)
+	}
+	return []byte(sb.String())
+}
+
+// writeTerraform writes resources.tf and imports.tf to the directory, creating it if needed.
+func writeTerraform(dir string, t *terraformConfig) error {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	if err := os.WriteFile(filepath.Join(dir, "resources.tf"), renderHCL(t.Resources), 0600); err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(dir, "imports.tf"), renderHCL(t.Imports), 0600)
+}
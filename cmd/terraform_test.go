@@ -0,0 +1,103 @@
+package cmd_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"maragu.dev/is"
+
+	"github.com/maragudk/honeycomb-cli/cmd"
+	"github.com/maragudk/honeycomb-cli/honeycomb"
+)
+
+func TestExportTerraform(t *testing.T) {
+	t.Run("writes resource and import blocks", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch r.URL.Path {
+			case "/1/datasets":
+				_ = json.NewEncoder(w).Encode([]honeycomb.Dataset{{Name: "API", Slug: "api"}})
+			case "/1/dataset_definitions/api":
+				_ = json.NewEncoder(w).Encode(honeycomb.DatasetDefinitions{})
+			case "/1/columns/api":
+				_ = json.NewEncoder(w).Encode([]honeycomb.Column{{ID: "c1", KeyName: "duration_ms", Type: "float", Description: "Request duration\a in ${unit}, \"quoted\"\n"}})
+			case "/1/derived_columns/api":
+				_ = json.NewEncoder(w).Encode([]honeycomb.DerivedColumn{{ID: "d1", Alias: "is_error", Expression: "GTE($status, 500)"}})
+			case "/1/triggers/api":
+				_ = json.NewEncoder(w).Encode([]honeycomb.Trigger{{
+					ID:         "t1",
+					Name:       "High error rate",
+					QueryID:    "q1",
+					Recipients: []honeycomb.NotificationRecipient{{ID: "r1"}},
+				}})
+			case "/1/queries/api/q1":
+				_ = json.NewEncoder(w).Encode(honeycomb.QuerySpec{Calculations: []honeycomb.Calculation{{Op: "COUNT"}}})
+			case "/1/slos/api":
+				_ = json.NewEncoder(w).Encode([]honeycomb.SLO{{ID: "s1", Name: "Availability", TargetPerMillion: 999000}})
+			case "/1/burn_alerts/api":
+				is.Equal(t, "s1", r.URL.Query().Get("slo_id"))
+				_ = json.NewEncoder(w).Encode([]honeycomb.BurnAlert{{ID: "ba1", AlertType: "exhaustion_time", ExhaustionMinutes: 60}})
+			case "/1/marker_settings/api":
+				_ = json.NewEncoder(w).Encode([]honeycomb.MarkerSetting{{ID: "m1", Type: "deploy", Color: "#00ff00"}})
+			case "/1/recipients":
+				_ = json.NewEncoder(w).Encode([]honeycomb.Recipient{
+					{ID: "r1", Type: "email", Details: honeycomb.RecipientDetails{EmailAddress: "oncall@example.com"}},
+					{ID: "r2", Type: "webhook", Details: honeycomb.RecipientDetails{WebhookName: "Deploys", WebhookURL: "https://example.com/hook", WebhookSecret: "s3cret"}},
+				})
+			case "/1/boards":
+				_ = json.NewEncoder(w).Encode([]honeycomb.Board{
+					{ID: "b1", Name: "API overview", Queries: []honeycomb.BoardQuery{{Dataset: "api", QueryID: "q1"}}},
+					{ID: "b2", Name: "Other", Queries: []honeycomb.BoardQuery{{Dataset: "other", QueryID: "q2"}}},
+				})
+			default:
+				t.Errorf("unexpected path %v", r.URL.Path)
+				http.Error(w, `{"error": "not found"}`, http.StatusNotFound)
+			}
+		}))
+		defer server.Close()
+
+		dir := t.TempDir()
+
+		var buf bytes.Buffer
+		root := cmd.NewRootCommand()
+		root.SetOut(&buf)
+		root.SetArgs([]string{"export", "--format", "terraform", "--output", dir, "--api-key", "test", "--api-url", server.URL})
+
+		err := root.Execute()
+		is.NotError(t, err)
+		is.True(t, contains(buf.String(), "Wrote 8 resource(s) and 6 import block(s)"))
+
+		resources, err := os.ReadFile(filepath.Join(dir, "resources.tf"))
+		is.NotError(t, err)
+		is.True(t, contains(string(resources), `resource "honeycombio_trigger" "api_high_error_rate" {`))
+		is.True(t, contains(string(resources), `query_id = honeycombio_query.api_high_error_rate.id`))
+		is.True(t, contains(string(resources), `query_json = jsonencode({`))
+		is.True(t, contains(string(resources), `op = "COUNT"`))
+		is.True(t, contains(string(resources), `target_percentage = 99.9`))
+		is.True(t, contains(string(resources), `slo_id             = honeycombio_slo.api_availability.id`))
+		is.True(t, contains(string(resources), `expression = "GTE($status, 500)"`))
+		is.True(t, contains(string(resources), `"Request duration\u0007 in $${unit}, \"quoted\"\n"`))
+		is.True(t, contains(string(resources), `resource "honeycombio_query" "api_overview_1" {`))
+
+		imports, err := os.ReadFile(filepath.Join(dir, "imports.tf"))
+		is.NotError(t, err)
+		is.True(t, contains(string(imports), "to = honeycombio_trigger.api_high_error_rate\n  id = \"api/t1\""))
+		is.True(t, contains(string(imports), "to = honeycombio_column.api_duration_ms\n  id = \"api/duration_ms\""))
+		is.True(t, contains(string(imports), "to = honeycombio_board.api_overview\n  id = \"b1\""))
+		is.True(t, !contains(string(imports), "honeycombio_query"))
+	})
+
+	t.Run("errors on an unknown format", func(t *testing.T) {
+		root := cmd.NewRootCommand()
+		root.SetOut(&bytes.Buffer{})
+		root.SetArgs([]string{"export", "--format", "pulumi", "--output", t.TempDir(), "--api-key", "test", "--api-url", "http://localhost"})
+
+		err := root.Execute()
+		is.True(t, err != nil)
+		is.True(t, contains(err.Error(), `invalid format "pulumi"`))
+	})
+}
@@ -6,6 +6,8 @@ import (
 	"text/tabwriter"
 
 	"github.com/spf13/cobra"
+
+	"github.com/maragudk/honeycomb-cli/honeycomb"
 )
 
 func newTriggersCommand() *cobra.Command {
@@ -19,6 +21,7 @@ func newTriggersCommand() *cobra.Command {
 
 	triggersCmd.AddCommand(newTriggersListCommand())
 	triggersCmd.AddCommand(newTriggersGetCommand())
+	triggersCmd.AddCommand(newTriggersBacktestCommand())
 
 	return triggersCmd
 }
@@ -74,22 +77,28 @@ func newTriggersGetCommand() *cobra.Command {
 
 			asJSON, _ := cmd.Flags().GetBool("json")
 			if asJSON {
-				return json.NewEncoder(cmd.OutOrStdout()).Encode(trigger)
+				if err := json.NewEncoder(cmd.OutOrStdout()).Encode(trigger); err != nil {
+					return err
+				}
+			} else {
+				fmt.Fprintf(cmd.OutOrStdout(), "ID:          %v\n", trigger.ID)
+				fmt.Fprintf(cmd.OutOrStdout(), "Name:        %v\n", trigger.Name)
+				fmt.Fprintf(cmd.OutOrStdout(), "Description: %v\n", trigger.Description)
+				fmt.Fprintf(cmd.OutOrStdout(), "Threshold:   %v %v\n", trigger.Threshold.Op, trigger.Threshold.Value)
+				fmt.Fprintf(cmd.OutOrStdout(), "Frequency:   %vs\n", trigger.Frequency)
+				disabled := "no"
+				if trigger.Disabled {
+					disabled = "yes"
+				}
+				fmt.Fprintf(cmd.OutOrStdout(), "Disabled:    %v\n", disabled)
 			}
 
-			fmt.Fprintf(cmd.OutOrStdout(), "ID:          %v\n", trigger.ID)
-			fmt.Fprintf(cmd.OutOrStdout(), "Name:        %v\n", trigger.Name)
-			fmt.Fprintf(cmd.OutOrStdout(), "Description: %v\n", trigger.Description)
-			fmt.Fprintf(cmd.OutOrStdout(), "Threshold:   %v %v\n", trigger.Threshold.Op, trigger.Threshold.Value)
-			fmt.Fprintf(cmd.OutOrStdout(), "Frequency:   %vs\n", trigger.Frequency)
-			disabled := "no"
-			if trigger.Disabled {
-				disabled = "yes"
-			}
-			fmt.Fprintf(cmd.OutOrStdout(), "Disabled:    %v\n", disabled)
-			return nil
+			return showLinks(cmd, c, func(l *honeycomb.UILinks) ([]uiLink, error) {
+				return []uiLink{{Label: "Trigger", URL: l.Trigger(dataset, trigger.ID)}}, nil
+			})
 		},
 	}
 	cmd.Flags().Bool("json", false, "Output as JSON")
+	addLinkFlags(cmd)
 	return cmd
 }
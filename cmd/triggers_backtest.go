@@ -0,0 +1,248 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/maragudk/honeycomb-cli/honeycomb"
+)
+
+// maxSeriesBuckets is the most time buckets requested per query. Longer windows are split into several queries.
+const maxSeriesBuckets = 1000
+
+// parseLookback parses durations like "1w", "7d", "36h", or "90m".
+func parseLookback(s string) (time.Duration, error) {
+	for suffix, unit := range map[string]time.Duration{"d": 24 * time.Hour, "w": 7 * 24 * time.Hour} {
+		if n, ok := strings.CutSuffix(s, suffix); ok {
+			count, err := strconv.Atoi(n)
+			if err != nil || count <= 0 {
+				return 0, fmt.Errorf("invalid duration %q", s)
+			}
+			return time.Duration(count) * unit, nil
+		}
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil || d <= 0 {
+		return 0, fmt.Errorf("invalid duration %q (use e.g. 1w, 7d, 36h, or 90m)", s)
+	}
+	return d, nil
+}
+
+// firingInterval is a period during which a trigger would have been firing.
+type firingInterval struct {
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"`
+	// Peak is the value furthest past the threshold during the interval.
+	Peak float64 `json:"peak"`
+}
+
+// backtestResult for one threshold.
+type backtestResult struct {
+	Threshold honeycomb.TriggerThreshold `json:"threshold"`
+	Alerts    int                        `json:"alerts"`
+	// FiringSeconds is the total time the trigger would have been firing.
+	FiringSeconds int64            `json:"firing_seconds"`
+	Intervals     []firingInterval `json:"intervals"`
+}
+
+// evaluateThreshold at each time bucket in the series and merge consecutive firing buckets into intervals.
+// With breakdowns there's a point per group per bucket, and the trigger fires if any group crosses the threshold.
+// Notifications are counted per interval, or per firing bucket for the on_true alert type.
+func evaluateThreshold(series []honeycomb.SeriesPoint, key string, threshold honeycomb.TriggerThreshold,
+	granularity time.Duration, alertType string) backtestResult {
+	firing := map[time.Time]float64{}
+	for _, p := range series {
+		value, ok := p.Data[key].(float64)
+		if !ok || !threshold.Exceeded(value) {
+			continue
+		}
+		if peak, seen := firing[p.Time]; !seen || morePast(threshold, value, peak) {
+			firing[p.Time] = value
+		}
+	}
+
+	var times []time.Time
+	for t := range firing {
+		times = append(times, t)
+	}
+	sort.Slice(times, func(i, j int) bool { return times[i].Before(times[j]) })
+
+	result := backtestResult{Threshold: threshold}
+	for _, t := range times {
+		value := firing[t]
+		if n := len(result.Intervals); n > 0 && !t.After(result.Intervals[n-1].End) {
+			last := &result.Intervals[n-1]
+			last.End = t.Add(granularity)
+			if morePast(threshold, value, last.Peak) {
+				last.Peak = value
+			}
+			continue
+		}
+		result.Intervals = append(result.Intervals, firingInterval{Start: t, End: t.Add(granularity), Peak: value})
+	}
+
+	for _, in := range result.Intervals {
+		result.FiringSeconds += int64(in.End.Sub(in.Start).Seconds())
+	}
+	result.Alerts = len(result.Intervals)
+	if alertType == "on_true" {
+		result.Alerts = len(times)
+	}
+	return result
+}
+
+// morePast reports whether a is further past the threshold than b.
+func morePast(threshold honeycomb.TriggerThreshold, a, b float64) bool {
+	if strings.HasPrefix(threshold.Op, "<") {
+		return a < b
+	}
+	return a > b
+}
+
+func newTriggersBacktestCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "backtest <id>",
+		Short: "Show how often a trigger would have fired in the past",
+		Long: `Run a trigger's query over a historical window, with a time bucket per trigger evaluation,
+and report every interval the trigger would have been firing and how many alerts it would have sent.
+
+Use --threshold to compare alternative thresholds, with or without an operator
+(e.g. --threshold 50 --threshold ">= 100"). Without an operator, the trigger's operator is used.
+
+Each bucket covers the trigger frequency, so the result is an approximation when the
+trigger query's time range differs from its frequency.
+
+Example:
+  honeycomb-cli triggers backtest abc123 --dataset api --since 7d --threshold 50 --threshold 100`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c := newClient(cmd)
+			dataset, _ := cmd.Flags().GetString("dataset")
+			since, _ := cmd.Flags().GetString("since")
+			overrides, _ := cmd.Flags().GetStringArray("threshold")
+
+			lookback, err := parseLookback(since)
+			if err != nil {
+				return err
+			}
+
+			trigger, err := c.GetTrigger(cmd.Context(), dataset, args[0])
+			if err != nil {
+				return err
+			}
+			if trigger.Query == nil {
+				if trigger.Query, err = c.GetQuery(cmd.Context(), dataset, trigger.QueryID); err != nil {
+					return fmt.Errorf("getting trigger query: %w", err)
+				}
+			}
+			if len(trigger.Query.Calculations) != 1 {
+				return fmt.Errorf("trigger query must have exactly one calculation, it has %v", len(trigger.Query.Calculations))
+			}
+
+			thresholds := []honeycomb.TriggerThreshold{trigger.Threshold}
+			if len(overrides) > 0 {
+				thresholds = nil
+				for _, o := range overrides {
+					t, err := parseThreshold(o, trigger.Threshold.Op)
+					if err != nil {
+						return err
+					}
+					thresholds = append(thresholds, t)
+				}
+			}
+
+			frequency := trigger.Frequency
+			if frequency == 0 {
+				frequency = 900
+			}
+			granularity := time.Duration(frequency) * time.Second
+
+			end := time.Now().Truncate(granularity)
+			start := end.Add(-lookback)
+
+			spin := newSpinner(cmd.ErrOrStderr())
+			defer spin.stop()
+			opts := runQueryOptions(cmd, spin.progress)
+
+			var series []honeycomb.SeriesPoint
+			chunk := maxSeriesBuckets * granularity
+			for from := start; from.Before(end); from = from.Add(chunk) {
+				to := from.Add(chunk)
+				if to.After(end) {
+					to = end
+				}
+
+				spec := *trigger.Query
+				spec.TimeRange = 0
+				spec.StartTime = from.Unix()
+				spec.EndTime = to.Unix()
+				spec.Granularity = frequency
+				spec.Orders = nil
+				spec.Limit = 0
+
+				result, err := c.RunQueryWithOptions(cmd.Context(), dataset, spec, opts)
+				if err != nil {
+					return err
+				}
+				series = append(series, result.Data.Series...)
+			}
+			spin.stop()
+
+			key := trigger.Query.Calculations[0].Name()
+			var results []backtestResult
+			for _, t := range thresholds {
+				results = append(results, evaluateThreshold(series, key, t, granularity, trigger.AlertType))
+			}
+
+			asJSON, _ := cmd.Flags().GetBool("json")
+			if asJSON {
+				return json.NewEncoder(cmd.OutOrStdout()).Encode(results)
+			}
+
+			out := cmd.OutOrStdout()
+			fmt.Fprintf(out, "Backtest of %q (%v, evaluated every %v) from %v to %v\n",
+				trigger.Name, key, granularity, start.Format(time.RFC3339), end.Format(time.RFC3339))
+			for _, r := range results {
+				printBacktestResult(out, r)
+			}
+			return nil
+		},
+	}
+	cmd.Flags().String("since", "7d", "How far back to evaluate (e.g. 7d, 36h)")
+	cmd.Flags().StringArray("threshold", nil, "Threshold to evaluate instead of the trigger's (repeatable)")
+	cmd.Flags().Bool("json", false, "Output as JSON")
+	return cmd
+}
+
+// parseThreshold like "> 100", ">=100", or "100", which uses the default operator.
+func parseThreshold(s, defaultOp string) (honeycomb.TriggerThreshold, error) {
+	s = strings.TrimSpace(s)
+	op := defaultOp
+	for _, candidate := range []string{">=", "<=", ">", "<"} {
+		if rest, ok := strings.CutPrefix(s, candidate); ok {
+			op, s = candidate, strings.TrimSpace(rest)
+			break
+		}
+	}
+	value, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return honeycomb.TriggerThreshold{}, fmt.Errorf("invalid threshold %q", s)
+	}
+	return honeycomb.TriggerThreshold{Op: op, Value: value}, nil
+}
+
+func printBacktestResult(w io.Writer, r backtestResult) {
+	fmt.Fprintf(w, "\nThreshold %v %v: %v alert(s), firing for %v\n", r.Threshold.Op, r.Threshold.Value, r.Alerts,
+		time.Duration(r.FiringSeconds)*time.Second)
+	for _, in := range r.Intervals {
+		fmt.Fprintf(w, "  %v - %v (%v, peak %v)\n",
+			in.Start.Format(time.RFC3339), in.End.Format(time.RFC3339), in.End.Sub(in.Start), in.Peak)
+	}
+}
@@ -0,0 +1,139 @@
+package cmd_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"maragu.dev/is"
+
+	"github.com/maragudk/honeycomb-cli/cmd"
+	"github.com/maragudk/honeycomb-cli/honeycomb"
+)
+
+func TestTriggersBacktestCommand(t *testing.T) {
+	t.Run("reports intervals the trigger would have fired", func(t *testing.T) {
+		// The trigger fires above 10 COUNT every 15 minutes,
+		// and the count is above 10 from 00:15 to 00:45 and from 01:00 to 01:15
+		t0 := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+		point := func(minutes int, count float64) honeycomb.SeriesPoint {
+			return honeycomb.SeriesPoint{Time: t0.Add(time.Duration(minutes) * time.Minute), Data: map[string]any{"COUNT": count}}
+		}
+
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch r.Method + " " + r.URL.Path {
+			case "GET /1/triggers/api/t1":
+				_ = json.NewEncoder(w).Encode(honeycomb.Trigger{
+					ID:        "t1",
+					Name:      "High error rate",
+					Frequency: 900,
+					Threshold: honeycomb.TriggerThreshold{Op: ">", Value: 10},
+					Query:     &honeycomb.QuerySpec{Calculations: []honeycomb.Calculation{{Op: "COUNT"}}, TimeRange: 900},
+				})
+			case "POST /1/queries/api":
+				var spec honeycomb.QuerySpec
+				_ = json.NewDecoder(r.Body).Decode(&spec)
+				is.Equal(t, 900, spec.Granularity)
+				is.Equal(t, 0, spec.TimeRange)
+				is.Equal(t, int64(7*24*60*60), spec.EndTime-spec.StartTime)
+				_ = json.NewEncoder(w).Encode(honeycomb.QueryResponse{ID: "q1"})
+			case "POST /1/query_results/api":
+				result := honeycomb.QueryResult{ID: "r1", Complete: true}
+				result.Data.Series = []honeycomb.SeriesPoint{
+					point(0, 5), point(15, 12), point(15, 3), point(30, 15), point(45, 2), point(60, 20),
+				}
+				_ = json.NewEncoder(w).Encode(result)
+			default:
+				t.Errorf("unexpected request %v %v", r.Method, r.URL.Path)
+				http.Error(w, `{"error": "not found"}`, http.StatusNotFound)
+			}
+		}))
+		defer server.Close()
+
+		var buf bytes.Buffer
+		root := cmd.NewRootCommand()
+		root.SetOut(&buf)
+		root.SetArgs([]string{"triggers", "backtest", "t1", "--dataset", "api", "--api-key", "test", "--api-url", server.URL})
+
+		err := root.Execute()
+		is.NotError(t, err)
+
+		output := buf.String()
+		is.True(t, contains(output, `Backtest of "High error rate" (COUNT, evaluated every 15m0s)`))
+		is.True(t, contains(output, "Threshold > 10: 2 alert(s), firing for 45m0s"))
+		is.True(t, contains(output, "2026-10-01T00:15:00Z - 2026-10-01T00:45:00Z (30m0s, peak 15)"))
+		is.True(t, contains(output, "2026-10-01T01:00:00Z - 2026-10-01T01:15:00Z (15m0s, peak 20)"))
+	})
+
+	t.Run("compares threshold overrides", func(t *testing.T) {
+		t0 := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+		point := func(minutes int, count float64) honeycomb.SeriesPoint {
+			return honeycomb.SeriesPoint{Time: t0.Add(time.Duration(minutes) * time.Minute), Data: map[string]any{"COUNT": count}}
+		}
+
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch r.Method + " " + r.URL.Path {
+			case "GET /1/triggers/api/t1":
+				_ = json.NewEncoder(w).Encode(honeycomb.Trigger{
+					ID:        "t1",
+					Name:      "High error rate",
+					Frequency: 900,
+					Threshold: honeycomb.TriggerThreshold{Op: ">", Value: 10},
+					Query:     &honeycomb.QuerySpec{Calculations: []honeycomb.Calculation{{Op: "COUNT"}}, TimeRange: 900},
+				})
+			case "POST /1/queries/api":
+				var spec honeycomb.QuerySpec
+				_ = json.NewDecoder(r.Body).Decode(&spec)
+				is.Equal(t, 900, spec.Granularity)
+				is.Equal(t, 0, spec.TimeRange)
+				is.Equal(t, int64(7*24*60*60), spec.EndTime-spec.StartTime)
+				_ = json.NewEncoder(w).Encode(honeycomb.QueryResponse{ID: "q1"})
+			case "POST /1/query_results/api":
+				result := honeycomb.QueryResult{ID: "r1", Complete: true}
+				result.Data.Series = []honeycomb.SeriesPoint{
+					point(0, 5), point(15, 12), point(15, 3), point(30, 15), point(45, 2), point(60, 20),
+				}
+				_ = json.NewEncoder(w).Encode(result)
+			default:
+				t.Errorf("unexpected request %v %v", r.Method, r.URL.Path)
+				http.Error(w, `{"error": "not found"}`, http.StatusNotFound)
+			}
+		}))
+		defer server.Close()
+
+		var buf bytes.Buffer
+		root := cmd.NewRootCommand()
+		root.SetOut(&buf)
+		root.SetArgs([]string{"triggers", "backtest", "t1", "--dataset", "api", "--threshold", "14", "--threshold", ">= 20",
+			"--json", "--api-key", "test", "--api-url", server.URL})
+
+		err := root.Execute()
+		is.NotError(t, err)
+
+		var results []struct {
+			Threshold     honeycomb.TriggerThreshold
+			Alerts        int
+			FiringSeconds int64 `json:"firing_seconds"`
+		}
+		is.NotError(t, json.Unmarshal(buf.Bytes(), &results))
+		is.Equal(t, 2, len(results))
+		is.Equal(t, ">", results[0].Threshold.Op)
+		is.Equal(t, 2, results[0].Alerts)
+		is.Equal(t, int64(1800), results[0].FiringSeconds)
+		is.Equal(t, ">=", results[1].Threshold.Op)
+		is.Equal(t, 1, results[1].Alerts)
+	})
+
+	t.Run("errors on an invalid duration", func(t *testing.T) {
+		root := cmd.NewRootCommand()
+		root.SetOut(&bytes.Buffer{})
+		root.SetArgs([]string{"triggers", "backtest", "t1", "--dataset", "api", "--since", "a week", "--api-key", "test", "--api-url", "http://localhost"})
+
+		err := root.Execute()
+		is.True(t, err != nil)
+		is.True(t, contains(err.Error(), `invalid duration "a week"`))
+	})
+}
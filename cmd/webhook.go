@@ -0,0 +1,280 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"os/exec"
+	"os/signal"
+	"sync"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/maragudk/honeycomb-cli/honeycomb"
+)
+
+func newWebhookCommand() *cobra.Command {
+	webhookCmd := &cobra.Command{
+		Use:   "webhook",
+		Short: "Receive trigger and burn alert notifications from webhook recipients",
+	}
+
+	webhookCmd.AddCommand(newWebhookListenCommand())
+
+	return webhookCmd
+}
+
+// webhookCommandTimeout is how long a command run with --exec may take.
+const webhookCommandTimeout = 5 * time.Minute
+
+// webhookHandler receives webhook notifications and prints, appends, and forwards them.
+type webhookHandler struct {
+	secret   string
+	command  string
+	file     string
+	out      io.Writer
+	errOut   io.Writer
+	mu       sync.Mutex
+	commands sync.WaitGroup
+}
+
+func (h *webhookHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	body, err := io.ReadAll(io.LimitReader(r.Body, 1<<20))
+	if err != nil {
+		http.Error(w, "error reading body", http.StatusBadRequest)
+		return
+	}
+
+	// Check the secret before parsing, so unauthenticated callers learn nothing about the payload format
+	if h.secret != "" {
+		secret := r.Header.Get("X-Honeycomb-Webhook-Token")
+		if secret == "" {
+			var p struct {
+				SharedSecret string `json:"shared_secret"`
+			}
+			_ = json.Unmarshal(body, &p)
+			secret = p.SharedSecret
+		}
+		if subtle.ConstantTimeCompare([]byte(secret), []byte(h.secret)) != 1 {
+			h.logf("Rejected notification from %v: invalid shared secret\n", r.RemoteAddr)
+			http.Error(w, "invalid shared secret", http.StatusUnauthorized)
+			return
+		}
+	}
+
+	n, err := honeycomb.ParseWebhookNotification(body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	data, err := json.Marshal(n)
+	if err != nil {
+		http.Error(w, "error encoding notification", http.StatusInternalServerError)
+		return
+	}
+
+	if err := h.write(n, data); err != nil {
+		h.logf("Error handling %v notification: %v\n", n.Kind, err)
+		http.Error(w, "error handling notification", http.StatusInternalServerError)
+		return
+	}
+
+	// Run the command after replying, so a slow command doesn't make Honeycomb time out and send the notification again
+	if h.command != "" {
+		h.commands.Add(1)
+		go func() {
+			defer h.commands.Done()
+			if err := h.run(n, data); err != nil {
+				h.logf("Error running command for %v notification: %v\n", n.Kind, err)
+			}
+		}()
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// run the command with the notification on stdin.
+func (h *webhookHandler) run(n *honeycomb.WebhookNotification, data []byte) error {
+	ctx, cancel := context.WithTimeout(context.Background(), webhookCommandTimeout)
+	defer cancel()
+
+	c := exec.CommandContext(ctx, "sh", "-c", h.command)
+	c.Stdin = bytes.NewReader(data)
+	c.Stdout = &lockedWriter{mu: &h.mu, w: h.out}
+	c.Stderr = &lockedWriter{mu: &h.mu, w: h.errOut}
+	c.Env = append(os.Environ(), "HONEYCOMB_NOTIFICATION_KIND="+n.Kind)
+	return c.Run()
+}
+
+// write the notification to the output and the file, one at a time.
+func (h *webhookHandler) write(n *honeycomb.WebhookNotification, data []byte) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	fmt.Fprintln(h.out, formatWebhookNotification(n))
+
+	if h.file == "" {
+		return nil
+	}
+	f, err := os.OpenFile(h.file, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(append(data, '\n')); err != nil {
+		_ = f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+func (h *webhookHandler) logf(format string, args ...any) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	fmt.Fprintf(h.errOut, format, args...)
+}
+
+// lockedWriter serializes writes from concurrent commands to a shared writer.
+type lockedWriter struct {
+	mu *sync.Mutex
+	w  io.Writer
+}
+
+func (l *lockedWriter) Write(p []byte) (int, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.w.Write(p)
+}
+
+// formatWebhookNotification as a single human-readable line.
+func formatWebhookNotification(n *honeycomb.WebhookNotification) string {
+	var kind, name, summary, url string
+	var fired, test bool
+	switch n.Kind {
+	case honeycomb.WebhookKindTrigger:
+		kind, name, summary, url = "trigger", n.Trigger.Name, n.Trigger.Summary, n.Trigger.ResultURL
+		fired, test = n.Trigger.Fired(), n.Trigger.IsTest
+	case honeycomb.WebhookKindBurnAlert:
+		kind, name, summary, url = "burn alert", n.BurnAlert.Name, n.BurnAlert.Summary, n.BurnAlert.ResultURL
+		fired, test = n.BurnAlert.Fired(), n.BurnAlert.IsTest
+	}
+
+	state := "resolved"
+	if fired {
+		state = "fired"
+	}
+	line := fmt.Sprintf("[%v %v] %v", kind, state, name)
+	if test {
+		line += " (test)"
+	}
+	if summary != "" {
+		line += ": " + summary
+	}
+	if url != "" {
+		line += " " + url
+	}
+	return line
+}
+
+func newWebhookListenCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "listen",
+		Short: "Run an HTTP server that receives webhook notifications",
+		Long: `Run an HTTP server that receives notifications from Honeycomb webhook recipients,
+for triggers firing and resolving and SLO burn alerts.
+
+Each notification is printed. With --output, it's also appended as a JSON line to a file.
+With --exec, a shell command is run with the notification as JSON on stdin and the kind
+(trigger or burn_alert) in the HONEYCOMB_NOTIFICATION_KIND environment variable. The command
+runs after the notification is acknowledged, for up to 5 minutes, and the server waits for
+running commands before it exits.
+
+If a shared secret is set with --secret (or HONEYCOMB_WEBHOOK_SECRET), notifications
+without a matching X-Honeycomb-Webhook-Token header or shared_secret field are rejected.
+--exec requires a shared secret, so anyone who can reach the server can't run the command.
+
+Example:
+  honeycomb-cli webhook listen --addr :8080 --secret $WEBHOOK_SECRET --exec ./create-incident-channel.sh
+
+Test it locally by posting a sample payload:
+  curl -X POST localhost:8080 -d '{"id": "abc", "name": "High error rate", "status": "TRIGGERED"}'`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			addr, _ := cmd.Flags().GetString("addr")
+			secret, _ := cmd.Flags().GetString("secret")
+			if secret == "" {
+				secret = os.Getenv("HONEYCOMB_WEBHOOK_SECRET")
+			}
+			command, _ := cmd.Flags().GetString("exec")
+			file, _ := cmd.Flags().GetString("output")
+			if command != "" && secret == "" {
+				return errors.New("--exec requires a shared secret with --secret or HONEYCOMB_WEBHOOK_SECRET")
+			}
+
+			ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt)
+			defer stop()
+
+			listener, err := net.Listen("tcp", addr)
+			if err != nil {
+				return err
+			}
+
+			handler := &webhookHandler{
+				secret:  secret,
+				command: command,
+				file:    file,
+				out:     cmd.OutOrStdout(),
+				errOut:  cmd.ErrOrStderr(),
+			}
+			server := &http.Server{
+				Handler:           handler,
+				ReadHeaderTimeout: 10 * time.Second,
+			}
+
+			fmt.Fprintf(cmd.ErrOrStderr(), "Listening for webhook notifications on %v\n", listener.Addr())
+
+			errs := make(chan error, 1)
+			go func() {
+				errs <- server.Serve(listener)
+			}()
+
+			select {
+			case err := <-errs:
+				return err
+			case <-ctx.Done():
+			}
+
+			shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+			defer cancel()
+			err = server.Shutdown(shutdownCtx)
+
+			// Let commands for notifications that were already received finish
+			handler.commands.Wait()
+
+			if err != nil {
+				return err
+			}
+			if err := <-errs; !errors.Is(err, http.ErrServerClosed) {
+				return err
+			}
+			return nil
+		},
+	}
+	cmd.Flags().String("addr", ":8080", "Address to listen on")
+	cmd.Flags().String("secret", "", "Shared secret configured on the webhook recipient (or set HONEYCOMB_WEBHOOK_SECRET)")
+	cmd.Flags().String("exec", "", "Shell command to run for each notification, with the notification as JSON on stdin")
+	cmd.Flags().StringP("output", "o", "", "File to append notifications to as JSON lines")
+	return cmd
+}
@@ -0,0 +1,153 @@
+package cmd_test
+
+import (
+	"bytes"
+	"context"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"maragu.dev/is"
+
+	"github.com/maragudk/honeycomb-cli/cmd"
+)
+
+// startWebhookListener runs webhook listen with the given flags until the test ends,
+// and returns the server URL and a function that stops it and returns its output.
+func startWebhookListener(t *testing.T, flags ...string) (string, func() string) {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	is.NotError(t, err)
+	addr := listener.Addr().String()
+	_ = listener.Close()
+
+	ctx, cancel := context.WithCancel(t.Context())
+	var out bytes.Buffer
+	root := cmd.NewRootCommand()
+	root.SetOut(&out)
+	root.SetErr(&bytes.Buffer{})
+	root.SetArgs(append([]string{"webhook", "listen", "--addr", addr}, flags...))
+
+	done := make(chan error, 1)
+	go func() {
+		done <- root.ExecuteContext(ctx)
+	}()
+
+	url := "http://" + addr
+	for range 100 {
+		if res, err := http.Get(url); err == nil {
+			_ = res.Body.Close()
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	return url, func() string {
+		cancel()
+		is.NotError(t, <-done)
+		return out.String()
+	}
+}
+
+func postWebhook(t *testing.T, url, token, body string) int {
+	t.Helper()
+	req, err := http.NewRequest(http.MethodPost, url, strings.NewReader(body))
+	is.NotError(t, err)
+	if token != "" {
+		req.Header.Set("X-Honeycomb-Webhook-Token", token)
+	}
+	res, err := http.DefaultClient.Do(req)
+	is.NotError(t, err)
+	_ = res.Body.Close()
+	return res.StatusCode
+}
+
+func TestWebhookListenCommand(t *testing.T) {
+	t.Run("prints notifications and appends them to a file", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "notifications.jsonl")
+		url, stop := startWebhookListener(t, "--output", path)
+
+		status := postWebhook(t, url, "", `{"id": "t1", "name": "High error rate", "status": "TRIGGERED", "summary": "Triggered"}`)
+		is.Equal(t, http.StatusNoContent, status)
+		status = postWebhook(t, url, "", `{"id": "s1", "name": "Availability", "type": "exhaustion_time", "status": "OK"}`)
+		is.Equal(t, http.StatusNoContent, status)
+
+		output := stop()
+		is.True(t, contains(output, "[trigger fired] High error rate: Triggered"))
+		is.True(t, contains(output, "[burn alert resolved] Availability"))
+
+		data, err := os.ReadFile(path)
+		is.NotError(t, err)
+		lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+		is.Equal(t, 2, len(lines))
+		is.True(t, contains(lines[0], `"kind":"trigger"`))
+		is.True(t, contains(lines[1], `"kind":"burn_alert"`))
+	})
+
+	t.Run("rejects notifications with a wrong shared secret", func(t *testing.T) {
+		url, stop := startWebhookListener(t, "--secret", "s3cret")
+
+		body := `{"id": "t1", "name": "High error rate", "status": "TRIGGERED"}`
+		is.Equal(t, http.StatusUnauthorized, postWebhook(t, url, "wrong", body))
+		is.Equal(t, http.StatusNoContent, postWebhook(t, url, "s3cret", body))
+		is.Equal(t, http.StatusNoContent, postWebhook(t, url, "", `{"id": "t1", "name": "x", "status": "OK", "shared_secret": "s3cret"}`))
+		is.Equal(t, http.StatusUnauthorized, postWebhook(t, url, "", `not json`))
+
+		_ = stop()
+	})
+
+	t.Run("forwards notifications to a command on stdin", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "forwarded.json")
+		url, stop := startWebhookListener(t, "--secret", "s3cret", "--exec", "cat > "+path+" && echo $HONEYCOMB_NOTIFICATION_KIND")
+
+		is.Equal(t, http.StatusNoContent, postWebhook(t, url, "s3cret", `{"id": "t1", "name": "High error rate", "status": "TRIGGERED"}`))
+
+		output := stop()
+		is.True(t, contains(output, "trigger\n"))
+
+		data, err := os.ReadFile(path)
+		is.NotError(t, err)
+		is.True(t, contains(string(data), `"name":"High error rate"`))
+	})
+
+	t.Run("replies before the command finishes and waits for it on shutdown", func(t *testing.T) {
+		dir := t.TempDir()
+		release, path := filepath.Join(dir, "release"), filepath.Join(dir, "forwarded.json")
+		url, stop := startWebhookListener(t, "--secret", "s3cret",
+			"--exec", "while [ ! -e "+release+" ]; do sleep 0.01; done; cat > "+path)
+
+		is.Equal(t, http.StatusNoContent, postWebhook(t, url, "s3cret", `{"id": "t1", "name": "High error rate", "status": "TRIGGERED"}`))
+		_, err := os.Stat(path)
+		is.True(t, os.IsNotExist(err))
+
+		is.NotError(t, os.WriteFile(release, nil, 0600))
+		_ = stop()
+
+		data, err := os.ReadFile(path)
+		is.NotError(t, err)
+		is.True(t, contains(string(data), `"name":"High error rate"`))
+	})
+
+	t.Run("rejects invalid payloads", func(t *testing.T) {
+		url, stop := startWebhookListener(t)
+		is.Equal(t, http.StatusBadRequest, postWebhook(t, url, "", `not json`))
+		_ = stop()
+	})
+	t.Run("requires a shared secret with exec", func(t *testing.T) {
+		t.Setenv("HONEYCOMB_WEBHOOK_SECRET", "")
+
+		root := cmd.NewRootCommand()
+		root.SetOut(&bytes.Buffer{})
+		root.SetErr(&bytes.Buffer{})
+		root.SetArgs([]string{"webhook", "listen", "--addr", "127.0.0.1:0", "--exec", "cat"})
+
+		err := root.Execute()
+		is.True(t, err != nil)
+		is.True(t, contains(err.Error(), "--exec requires a shared secret"))
+	})
+}
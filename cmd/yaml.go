@@ -0,0 +1,77 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+
+	"gopkg.in/yaml.v3"
+)
+
+// decodeYAML into v. The YAML is converted to JSON first, so v's json struct tags apply,
+// and unknown fields are reported as errors to catch typos in hand-written files.
+func decodeYAML(data []byte, v any) error {
+	var raw any
+	if err := yaml.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	return decodeYAMLValue(raw, v)
+}
+
+// decodeYAMLDocuments calls fn with each non-empty document in a multi-document YAML stream.
+func decodeYAMLDocuments(data []byte, fn func(raw any) error) error {
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	for {
+		var raw any
+		if err := dec.Decode(&raw); err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			return err
+		}
+		if raw == nil {
+			continue
+		}
+		if err := fn(raw); err != nil {
+			return err
+		}
+	}
+}
+
+// decodeYAMLValue decodes an already parsed YAML value into v like [decodeYAML].
+func decodeYAMLValue(raw, v any) error {
+	b, err := json.Marshal(raw)
+	if err != nil {
+		return err
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.DisallowUnknownFields()
+	return dec.Decode(v)
+}
+
+// encodeYAML from v. v is converted to JSON first, so its json struct tags apply,
+// and map keys are sorted, so the output is stable.
+func encodeYAML(v any) ([]byte, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	var raw any
+	if err := json.Unmarshal(b, &raw); err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(raw); err != nil {
+		return nil, err
+	}
+	if err := enc.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
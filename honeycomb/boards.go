@@ -0,0 +1,144 @@
+package honeycomb
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+)
+
+// Board in Honeycomb. Boards collect saved queries into a dashboard.
+type Board struct {
+	ID           string       `json:"id,omitempty"`
+	Name         string       `json:"name"`
+	Description  string       `json:"description,omitempty"`
+	Style        string       `json:"style,omitempty"`
+	ColumnLayout string       `json:"column_layout,omitempty"`
+	Queries      []BoardQuery `json:"queries,omitempty"`
+	Links        struct {
+		BoardURL string `json:"board_url,omitempty"`
+	} `json:"links,omitempty"`
+}
+
+// BoardQuery is a panel on a [Board] that displays a saved query.
+type BoardQuery struct {
+	Caption           string `json:"caption,omitempty"`
+	QueryStyle        string `json:"query_style,omitempty"`
+	Dataset           string `json:"dataset,omitempty"`
+	QueryID           string `json:"query_id"`
+	QueryAnnotationID string `json:"query_annotation_id,omitempty"`
+}
+
+// ListBoards in the environment.
+func (c *Client) ListBoards(ctx context.Context) ([]Board, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+"/1/boards", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := c.do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = res.Body.Close()
+	}()
+
+	var boards []Board
+	if err := json.NewDecoder(res.Body).Decode(&boards); err != nil {
+		return nil, err
+	}
+	return boards, nil
+}
+
+// GetBoard by ID.
+func (c *Client) GetBoard(ctx context.Context, id string) (*Board, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+"/1/boards/"+id, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := c.do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = res.Body.Close()
+	}()
+
+	var board Board
+	if err := json.NewDecoder(res.Body).Decode(&board); err != nil {
+		return nil, err
+	}
+	return &board, nil
+}
+
+// CreateBoard from the given board. The ID is ignored.
+func (c *Client) CreateBoard(ctx context.Context, board Board) (*Board, error) {
+	board.ID = ""
+	body, err := json.Marshal(board)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+"/1/boards", bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := c.do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = res.Body.Close()
+	}()
+
+	var created Board
+	if err := json.NewDecoder(res.Body).Decode(&created); err != nil {
+		return nil, err
+	}
+	return &created, nil
+}
+
+// UpdateBoard replaces the board with the given board's ID.
+func (c *Client) UpdateBoard(ctx context.Context, board Board) (*Board, error) {
+	body, err := json.Marshal(board)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, c.baseURL+"/1/boards/"+board.ID, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := c.do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = res.Body.Close()
+	}()
+
+	var updated Board
+	if err := json.NewDecoder(res.Body).Decode(&updated); err != nil {
+		return nil, err
+	}
+	return &updated, nil
+}
+
+// DeleteBoard by ID.
+func (c *Client) DeleteBoard(ctx context.Context, id string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodDelete, c.baseURL+"/1/boards/"+id, nil)
+	if err != nil {
+		return err
+	}
+
+	res, err := c.do(req)
+	if err != nil {
+		return err
+	}
+	_ = res.Body.Close()
+	return nil
+}
@@ -0,0 +1,116 @@
+package honeycomb_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"maragu.dev/is"
+
+	"github.com/maragudk/honeycomb-cli/honeycomb"
+)
+
+func TestClient_ListBoards(t *testing.T) {
+	t.Run("returns list of boards", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			is.Equal(t, "/1/boards", r.URL.Path)
+			is.Equal(t, http.MethodGet, r.Method)
+
+			_ = json.NewEncoder(w).Encode([]honeycomb.Board{
+				{ID: "b1", Name: "API overview"},
+				{ID: "b2", Name: "Database"},
+			})
+		}))
+		defer server.Close()
+
+		c := honeycomb.NewClient("test-key", honeycomb.WithBaseURL(server.URL))
+		boards, err := c.ListBoards(t.Context())
+		is.NotError(t, err)
+		is.Equal(t, 2, len(boards))
+		is.Equal(t, "API overview", boards[0].Name)
+	})
+}
+
+func TestClient_GetBoard(t *testing.T) {
+	t.Run("returns board with its queries", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			is.Equal(t, "/1/boards/b1", r.URL.Path)
+			is.Equal(t, http.MethodGet, r.Method)
+
+			_ = json.NewEncoder(w).Encode(honeycomb.Board{
+				ID:   "b1",
+				Name: "API overview",
+				Queries: []honeycomb.BoardQuery{
+					{Caption: "Requests", Dataset: "requests", QueryID: "q1"},
+				},
+			})
+		}))
+		defer server.Close()
+
+		c := honeycomb.NewClient("test-key", honeycomb.WithBaseURL(server.URL))
+		board, err := c.GetBoard(t.Context(), "b1")
+		is.NotError(t, err)
+		is.Equal(t, "API overview", board.Name)
+		is.Equal(t, 1, len(board.Queries))
+		is.Equal(t, "q1", board.Queries[0].QueryID)
+	})
+}
+
+func TestClient_CreateBoard(t *testing.T) {
+	t.Run("creates a board", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			is.Equal(t, "/1/boards", r.URL.Path)
+			is.Equal(t, http.MethodPost, r.Method)
+
+			var board honeycomb.Board
+			_ = json.NewDecoder(r.Body).Decode(&board)
+			is.Equal(t, "", board.ID)
+			is.Equal(t, "API overview", board.Name)
+
+			board.ID = "b1"
+			w.WriteHeader(http.StatusCreated)
+			_ = json.NewEncoder(w).Encode(board)
+		}))
+		defer server.Close()
+
+		c := honeycomb.NewClient("test-key", honeycomb.WithBaseURL(server.URL))
+		board, err := c.CreateBoard(t.Context(), honeycomb.Board{ID: "ignored", Name: "API overview"})
+		is.NotError(t, err)
+		is.Equal(t, "b1", board.ID)
+	})
+}
+
+func TestClient_UpdateBoard(t *testing.T) {
+	t.Run("updates a board", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			is.Equal(t, "/1/boards/b1", r.URL.Path)
+			is.Equal(t, http.MethodPut, r.Method)
+
+			var board honeycomb.Board
+			_ = json.NewDecoder(r.Body).Decode(&board)
+			_ = json.NewEncoder(w).Encode(board)
+		}))
+		defer server.Close()
+
+		c := honeycomb.NewClient("test-key", honeycomb.WithBaseURL(server.URL))
+		board, err := c.UpdateBoard(t.Context(), honeycomb.Board{ID: "b1", Name: "Renamed"})
+		is.NotError(t, err)
+		is.Equal(t, "Renamed", board.Name)
+	})
+}
+
+func TestClient_DeleteBoard(t *testing.T) {
+	t.Run("deletes a board", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			is.Equal(t, "/1/boards/b1", r.URL.Path)
+			is.Equal(t, http.MethodDelete, r.Method)
+			w.WriteHeader(http.StatusNoContent)
+		}))
+		defer server.Close()
+
+		c := honeycomb.NewClient("test-key", honeycomb.WithBaseURL(server.URL))
+		err := c.DeleteBoard(t.Context(), "b1")
+		is.NotError(t, err)
+	})
+}
@@ -0,0 +1,143 @@
+package honeycomb
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// BurnAlert notifies recipients when an [SLO]'s error budget is burning down too fast.
+type BurnAlert struct {
+	ID                                    string                  `json:"id,omitempty"`
+	AlertType                             string                  `json:"alert_type"`
+	Description                           string                  `json:"description,omitempty"`
+	ExhaustionMinutes                     int                     `json:"exhaustion_minutes,omitempty"`
+	BudgetRateWindowMinutes               int                     `json:"budget_rate_window_minutes,omitempty"`
+	BudgetRateDecreaseThresholdPerMillion int                     `json:"budget_rate_decrease_threshold_per_million,omitempty"`
+	SLO                                   BurnAlertSLO            `json:"slo,omitzero"`
+	Recipients                            []NotificationRecipient `json:"recipients,omitempty"`
+	CreatedAt                             string                  `json:"created_at,omitempty"`
+	UpdatedAt                             string                  `json:"updated_at,omitempty"`
+}
+
+// BurnAlertSLO references the [SLO] a [BurnAlert] belongs to.
+type BurnAlertSLO struct {
+	ID string `json:"id"`
+}
+
+// ListBurnAlerts for an SLO in a dataset.
+func (c *Client) ListBurnAlerts(ctx context.Context, dataset, sloID string) ([]BurnAlert, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+"/1/burn_alerts/"+dataset+"?slo_id="+url.QueryEscape(sloID), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := c.do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = res.Body.Close()
+	}()
+
+	var alerts []BurnAlert
+	if err := json.NewDecoder(res.Body).Decode(&alerts); err != nil {
+		return nil, err
+	}
+	return alerts, nil
+}
+
+// GetBurnAlert by ID for a dataset.
+func (c *Client) GetBurnAlert(ctx context.Context, dataset, id string) (*BurnAlert, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("%v/1/burn_alerts/%v/%v", c.baseURL, dataset, id), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := c.do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = res.Body.Close()
+	}()
+
+	var alert BurnAlert
+	if err := json.NewDecoder(res.Body).Decode(&alert); err != nil {
+		return nil, err
+	}
+	return &alert, nil
+}
+
+// CreateBurnAlert for a dataset. The ID is ignored.
+func (c *Client) CreateBurnAlert(ctx context.Context, dataset string, alert BurnAlert) (*BurnAlert, error) {
+	alert.ID = ""
+	body, err := json.Marshal(alert)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+"/1/burn_alerts/"+dataset, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := c.do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = res.Body.Close()
+	}()
+
+	var created BurnAlert
+	if err := json.NewDecoder(res.Body).Decode(&created); err != nil {
+		return nil, err
+	}
+	return &created, nil
+}
+
+// UpdateBurnAlert replaces the burn alert with the given alert's ID.
+func (c *Client) UpdateBurnAlert(ctx context.Context, dataset string, alert BurnAlert) (*BurnAlert, error) {
+	body, err := json.Marshal(alert)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, fmt.Sprintf("%v/1/burn_alerts/%v/%v", c.baseURL, dataset, alert.ID), bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := c.do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = res.Body.Close()
+	}()
+
+	var updated BurnAlert
+	if err := json.NewDecoder(res.Body).Decode(&updated); err != nil {
+		return nil, err
+	}
+	return &updated, nil
+}
+
+// DeleteBurnAlert by ID for a dataset.
+func (c *Client) DeleteBurnAlert(ctx context.Context, dataset, id string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodDelete, fmt.Sprintf("%v/1/burn_alerts/%v/%v", c.baseURL, dataset, id), nil)
+	if err != nil {
+		return err
+	}
+
+	res, err := c.do(req)
+	if err != nil {
+		return err
+	}
+	_ = res.Body.Close()
+	return nil
+}
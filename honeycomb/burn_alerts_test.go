@@ -0,0 +1,109 @@
+package honeycomb_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"maragu.dev/is"
+
+	"github.com/maragudk/honeycomb-cli/honeycomb"
+)
+
+func TestClient_ListBurnAlerts(t *testing.T) {
+	t.Run("returns burn alerts for an SLO", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			is.Equal(t, "/1/burn_alerts/requests", r.URL.Path)
+			is.Equal(t, "s1", r.URL.Query().Get("slo_id"))
+			is.Equal(t, http.MethodGet, r.Method)
+
+			_ = json.NewEncoder(w).Encode([]honeycomb.BurnAlert{
+				{ID: "ba1", AlertType: "exhaustion_time", ExhaustionMinutes: 240, SLO: honeycomb.BurnAlertSLO{ID: "s1"}},
+			})
+		}))
+		defer server.Close()
+
+		c := honeycomb.NewClient("test-key", honeycomb.WithBaseURL(server.URL))
+		alerts, err := c.ListBurnAlerts(t.Context(), "requests", "s1")
+		is.NotError(t, err)
+		is.Equal(t, 1, len(alerts))
+		is.Equal(t, 240, alerts[0].ExhaustionMinutes)
+	})
+}
+
+func TestClient_GetBurnAlert(t *testing.T) {
+	t.Run("returns a burn alert by ID", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			is.Equal(t, "/1/burn_alerts/requests/ba1", r.URL.Path)
+			_ = json.NewEncoder(w).Encode(honeycomb.BurnAlert{ID: "ba1", AlertType: "budget_rate"})
+		}))
+		defer server.Close()
+
+		c := honeycomb.NewClient("test-key", honeycomb.WithBaseURL(server.URL))
+		alert, err := c.GetBurnAlert(t.Context(), "requests", "ba1")
+		is.NotError(t, err)
+		is.Equal(t, "budget_rate", alert.AlertType)
+	})
+}
+
+func TestClient_CreateBurnAlert(t *testing.T) {
+	t.Run("creates a burn alert", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			is.Equal(t, "/1/burn_alerts/requests", r.URL.Path)
+			is.Equal(t, http.MethodPost, r.Method)
+
+			var alert honeycomb.BurnAlert
+			_ = json.NewDecoder(r.Body).Decode(&alert)
+			is.Equal(t, "s1", alert.SLO.ID)
+
+			alert.ID = "ba1"
+			w.WriteHeader(http.StatusCreated)
+			_ = json.NewEncoder(w).Encode(alert)
+		}))
+		defer server.Close()
+
+		c := honeycomb.NewClient("test-key", honeycomb.WithBaseURL(server.URL))
+		alert, err := c.CreateBurnAlert(t.Context(), "requests", honeycomb.BurnAlert{
+			AlertType:         "exhaustion_time",
+			ExhaustionMinutes: 60,
+			SLO:               honeycomb.BurnAlertSLO{ID: "s1"},
+		})
+		is.NotError(t, err)
+		is.Equal(t, "ba1", alert.ID)
+	})
+}
+
+func TestClient_UpdateBurnAlert(t *testing.T) {
+	t.Run("updates a burn alert", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			is.Equal(t, "/1/burn_alerts/requests/ba1", r.URL.Path)
+			is.Equal(t, http.MethodPut, r.Method)
+
+			var alert honeycomb.BurnAlert
+			_ = json.NewDecoder(r.Body).Decode(&alert)
+			_ = json.NewEncoder(w).Encode(alert)
+		}))
+		defer server.Close()
+
+		c := honeycomb.NewClient("test-key", honeycomb.WithBaseURL(server.URL))
+		alert, err := c.UpdateBurnAlert(t.Context(), "requests", honeycomb.BurnAlert{ID: "ba1", AlertType: "exhaustion_time", ExhaustionMinutes: 30})
+		is.NotError(t, err)
+		is.Equal(t, 30, alert.ExhaustionMinutes)
+	})
+}
+
+func TestClient_DeleteBurnAlert(t *testing.T) {
+	t.Run("deletes a burn alert", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			is.Equal(t, "/1/burn_alerts/requests/ba1", r.URL.Path)
+			is.Equal(t, http.MethodDelete, r.Method)
+			w.WriteHeader(http.StatusNoContent)
+		}))
+		defer server.Close()
+
+		c := honeycomb.NewClient("test-key", honeycomb.WithBaseURL(server.URL))
+		err := c.DeleteBurnAlert(t.Context(), "requests", "ba1")
+		is.NotError(t, err)
+	})
+}
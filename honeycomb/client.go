@@ -6,14 +6,21 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"sync"
 	"time"
+
+	"go.opentelemetry.io/otel/trace"
 )
 
 // Client for the Honeycomb API.
 type Client struct {
-	apiKey  string
-	baseURL string
-	http    *http.Client
+	apiKey          string
+	baseURL         string
+	http            *http.Client
+	runQueryOptions RunQueryOptions
+	queryCache      *QueryCache
+	rateLimiter     *RateLimiter
+	tracer          trace.Tracer
 }
 
 // NewClient with the given API key and options.
@@ -24,6 +31,7 @@ func NewClient(apiKey string, opts ...Option) *Client {
 		http: &http.Client{
 			Timeout: 30 * time.Second,
 		},
+		tracer: noopTracer,
 	}
 	for _, opt := range opts {
 		opt(c)
@@ -72,10 +80,31 @@ func (c *Client) do(req *http.Request) (*http.Response, error) {
 	req.Header.Set("X-Honeycomb-Team", c.apiKey)
 	req.Header.Set("Content-Type", "application/json")
 
+	req, span := c.startSpan(req)
+
+	release := func() {}
+	if c.rateLimiter != nil {
+		start := time.Now()
+		var err error
+		release, err = c.rateLimiter.wait(req.Context(), req)
+		span.SetAttributes(rateLimitWaitKey.Int64(time.Since(start).Milliseconds()))
+		if err != nil {
+			endSpan(span, 0, err)
+			return nil, err
+		}
+	}
+
 	res, err := c.http.Do(req)
 	if err != nil {
+		release()
+		endSpan(span, 0, err)
 		return nil, err
 	}
+	// The request is done, for the rate limiter and the span, when the caller closes the body
+	res.Body = &releasingBody{ReadCloser: res.Body, release: func() {
+		release()
+		endSpan(span, res.StatusCode, nil)
+	}}
 
 	if res.StatusCode >= 400 {
 		defer func() {
@@ -89,10 +118,23 @@ func (c *Client) do(req *http.Request) (*http.Response, error) {
 
 		apiErr := &APIError{StatusCode: res.StatusCode}
 		if err := json.Unmarshal(body, apiErr); err != nil {
-			return nil, fmt.Errorf("honeycomb API error (%d): %v", res.StatusCode, string(body))
+			// Keep the status code for callers that check it, even when the body isn't JSON
+			apiErr = &APIError{StatusCode: res.StatusCode, Err: string(body)}
 		}
 		return nil, apiErr
 	}
 
 	return res, nil
 }
+
+// releasingBody calls release once when the response body is closed.
+type releasingBody struct {
+	io.ReadCloser
+	release func()
+	once    sync.Once
+}
+
+func (b *releasingBody) Close() error {
+	defer b.once.Do(b.release)
+	return b.ReadCloser.Close()
+}
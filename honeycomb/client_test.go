@@ -2,6 +2,7 @@ package honeycomb_test
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -73,4 +74,19 @@ func TestClient_Auth(t *testing.T) {
 		_, err := c.Auth(t.Context())
 		is.True(t, err != nil)
 	})
+
+	t.Run("returns an API error with the status code for an error response that isn't JSON", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			http.Error(w, "bad gateway", http.StatusBadGateway)
+		}))
+		defer server.Close()
+
+		c := honeycomb.NewClient("test-key", honeycomb.WithBaseURL(server.URL))
+		_, err := c.Auth(t.Context())
+
+		var apiErr *honeycomb.APIError
+		is.True(t, errors.As(err, &apiErr))
+		is.Equal(t, http.StatusBadGateway, apiErr.StatusCode)
+		is.Equal(t, "honeycomb API error (502): bad gateway\n", err.Error())
+	})
 }
@@ -1,9 +1,12 @@
 package honeycomb
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
+	"fmt"
 	"net/http"
+	"net/url"
 )
 
 // Column in a Honeycomb dataset.
@@ -39,3 +42,131 @@ func (c *Client) ListColumns(ctx context.Context, dataset string) ([]Column, err
 	}
 	return columns, nil
 }
+
+// GetColumn by ID for a dataset.
+func (c *Client) GetColumn(ctx context.Context, dataset, id string) (*Column, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("%v/1/columns/%v/%v", c.baseURL, dataset, id), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := c.do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = res.Body.Close()
+	}()
+
+	var column Column
+	if err := json.NewDecoder(res.Body).Decode(&column); err != nil {
+		return nil, err
+	}
+	return &column, nil
+}
+
+// GetColumnByKeyName for a dataset.
+func (c *Client) GetColumnByKeyName(ctx context.Context, dataset, keyName string) (*Column, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+"/1/columns/"+dataset+"?key_name="+url.QueryEscape(keyName), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := c.do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = res.Body.Close()
+	}()
+
+	var column Column
+	if err := json.NewDecoder(res.Body).Decode(&column); err != nil {
+		return nil, err
+	}
+	return &column, nil
+}
+
+// CreateColumnRequest for creating a column.
+type CreateColumnRequest struct {
+	KeyName     string `json:"key_name"`
+	Type        string `json:"type,omitempty"`
+	Description string `json:"description,omitempty"`
+	Hidden      bool   `json:"hidden,omitempty"`
+}
+
+// CreateColumn in a dataset.
+func (c *Client) CreateColumn(ctx context.Context, dataset string, create CreateColumnRequest) (*Column, error) {
+	body, err := json.Marshal(create)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+"/1/columns/"+dataset, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := c.do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = res.Body.Close()
+	}()
+
+	var column Column
+	if err := json.NewDecoder(res.Body).Decode(&column); err != nil {
+		return nil, err
+	}
+	return &column, nil
+}
+
+// UpdateColumnRequest for updating a column. Nil fields are left unchanged.
+type UpdateColumnRequest struct {
+	Description *string `json:"description,omitempty"`
+	Type        *string `json:"type,omitempty"`
+	Hidden      *bool   `json:"hidden,omitempty"`
+}
+
+// UpdateColumn by ID for a dataset.
+func (c *Client) UpdateColumn(ctx context.Context, dataset, id string, update UpdateColumnRequest) (*Column, error) {
+	body, err := json.Marshal(update)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, fmt.Sprintf("%v/1/columns/%v/%v", c.baseURL, dataset, id), bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := c.do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = res.Body.Close()
+	}()
+
+	var column Column
+	if err := json.NewDecoder(res.Body).Decode(&column); err != nil {
+		return nil, err
+	}
+	return &column, nil
+}
+
+// DeleteColumn by ID for a dataset.
+func (c *Client) DeleteColumn(ctx context.Context, dataset, id string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodDelete, fmt.Sprintf("%v/1/columns/%v/%v", c.baseURL, dataset, id), nil)
+	if err != nil {
+		return err
+	}
+
+	res, err := c.do(req)
+	if err != nil {
+		return err
+	}
+	_ = res.Body.Close()
+	return nil
+}
@@ -35,3 +35,98 @@ func TestClient_ListColumns(t *testing.T) {
 		is.True(t, columns[2].Hidden)
 	})
 }
+
+func TestClient_GetColumn(t *testing.T) {
+	t.Run("returns a column by ID", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			is.Equal(t, "/1/columns/requests/1", r.URL.Path)
+			is.Equal(t, http.MethodGet, r.Method)
+
+			_ = json.NewEncoder(w).Encode(honeycomb.Column{ID: "1", KeyName: "duration_ms"})
+		}))
+		defer server.Close()
+
+		c := honeycomb.NewClient("test-key", honeycomb.WithBaseURL(server.URL))
+		column, err := c.GetColumn(t.Context(), "requests", "1")
+		is.NotError(t, err)
+		is.Equal(t, "duration_ms", column.KeyName)
+	})
+}
+
+func TestClient_GetColumnByKeyName(t *testing.T) {
+	t.Run("returns a column by key name", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			is.Equal(t, "/1/columns/requests", r.URL.Path)
+			is.Equal(t, "http.status code", r.URL.Query().Get("key_name"))
+
+			_ = json.NewEncoder(w).Encode(honeycomb.Column{ID: "2", KeyName: "http.status code"})
+		}))
+		defer server.Close()
+
+		c := honeycomb.NewClient("test-key", honeycomb.WithBaseURL(server.URL))
+		column, err := c.GetColumnByKeyName(t.Context(), "requests", "http.status code")
+		is.NotError(t, err)
+		is.Equal(t, "2", column.ID)
+	})
+}
+
+func TestClient_CreateColumn(t *testing.T) {
+	t.Run("creates a column", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			is.Equal(t, "/1/columns/requests", r.URL.Path)
+			is.Equal(t, http.MethodPost, r.Method)
+
+			var req honeycomb.CreateColumnRequest
+			_ = json.NewDecoder(r.Body).Decode(&req)
+			is.Equal(t, "user_id", req.KeyName)
+			is.Equal(t, "string", req.Type)
+
+			w.WriteHeader(http.StatusCreated)
+			_ = json.NewEncoder(w).Encode(honeycomb.Column{ID: "3", KeyName: req.KeyName, Type: req.Type})
+		}))
+		defer server.Close()
+
+		c := honeycomb.NewClient("test-key", honeycomb.WithBaseURL(server.URL))
+		column, err := c.CreateColumn(t.Context(), "requests", honeycomb.CreateColumnRequest{KeyName: "user_id", Type: "string"})
+		is.NotError(t, err)
+		is.Equal(t, "3", column.ID)
+	})
+}
+
+func TestClient_UpdateColumn(t *testing.T) {
+	t.Run("sends only the fields that are set", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			is.Equal(t, "/1/columns/requests/1", r.URL.Path)
+			is.Equal(t, http.MethodPut, r.Method)
+
+			var body map[string]any
+			_ = json.NewDecoder(r.Body).Decode(&body)
+			is.Equal(t, 1, len(body))
+			is.Equal(t, false, body["hidden"].(bool))
+
+			_ = json.NewEncoder(w).Encode(honeycomb.Column{ID: "1", KeyName: "duration_ms"})
+		}))
+		defer server.Close()
+
+		hidden := false
+		c := honeycomb.NewClient("test-key", honeycomb.WithBaseURL(server.URL))
+		column, err := c.UpdateColumn(t.Context(), "requests", "1", honeycomb.UpdateColumnRequest{Hidden: &hidden})
+		is.NotError(t, err)
+		is.True(t, !column.Hidden)
+	})
+}
+
+func TestClient_DeleteColumn(t *testing.T) {
+	t.Run("deletes a column", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			is.Equal(t, "/1/columns/requests/1", r.URL.Path)
+			is.Equal(t, http.MethodDelete, r.Method)
+			w.WriteHeader(http.StatusNoContent)
+		}))
+		defer server.Close()
+
+		c := honeycomb.NewClient("test-key", honeycomb.WithBaseURL(server.URL))
+		err := c.DeleteColumn(t.Context(), "requests", "1")
+		is.NotError(t, err)
+	})
+}
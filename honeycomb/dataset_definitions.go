@@ -0,0 +1,85 @@
+package honeycomb
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+)
+
+// DatasetDefinitions map the semantic fields Honeycomb uses for tracing and logs to columns in a dataset.
+// Nil fields are not defined.
+type DatasetDefinitions struct {
+	SpanID         *DatasetDefinition `json:"span_id,omitempty"`
+	TraceID        *DatasetDefinition `json:"trace_id,omitempty"`
+	ParentID       *DatasetDefinition `json:"parent_id,omitempty"`
+	Name           *DatasetDefinition `json:"name,omitempty"`
+	ServiceName    *DatasetDefinition `json:"service_name,omitempty"`
+	DurationMS     *DatasetDefinition `json:"duration_ms,omitempty"`
+	SpanKind       *DatasetDefinition `json:"span_kind,omitempty"`
+	AnnotationType *DatasetDefinition `json:"annotation_type,omitempty"`
+	LinkSpanID     *DatasetDefinition `json:"link_span_id,omitempty"`
+	LinkTraceID    *DatasetDefinition `json:"link_trace_id,omitempty"`
+	Error          *DatasetDefinition `json:"error,omitempty"`
+	Status         *DatasetDefinition `json:"status,omitempty"`
+	Route          *DatasetDefinition `json:"route,omitempty"`
+	User           *DatasetDefinition `json:"user,omitempty"`
+	Body           *DatasetDefinition `json:"body,omitempty"`
+	LogSeverity    *DatasetDefinition `json:"log_severity,omitempty"`
+}
+
+// DatasetDefinition points a semantic field at a column or derived column.
+type DatasetDefinition struct {
+	Name       string `json:"name"`
+	ColumnType string `json:"column_type,omitempty"`
+}
+
+// GetDatasetDefinitions for a dataset.
+func (c *Client) GetDatasetDefinitions(ctx context.Context, dataset string) (*DatasetDefinitions, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+"/1/dataset_definitions/"+dataset, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := c.do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = res.Body.Close()
+	}()
+
+	var definitions DatasetDefinitions
+	if err := json.NewDecoder(res.Body).Decode(&definitions); err != nil {
+		return nil, err
+	}
+	return &definitions, nil
+}
+
+// UpdateDatasetDefinitions for a dataset. Only non-nil definitions are changed.
+// A definition with an empty name removes it.
+func (c *Client) UpdateDatasetDefinitions(ctx context.Context, dataset string, definitions DatasetDefinitions) (*DatasetDefinitions, error) {
+	body, err := json.Marshal(definitions)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPatch, c.baseURL+"/1/dataset_definitions/"+dataset, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := c.do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = res.Body.Close()
+	}()
+
+	var updated DatasetDefinitions
+	if err := json.NewDecoder(res.Body).Decode(&updated); err != nil {
+		return nil, err
+	}
+	return &updated, nil
+}
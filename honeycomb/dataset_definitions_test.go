@@ -0,0 +1,53 @@
+package honeycomb_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"maragu.dev/is"
+
+	"github.com/maragudk/honeycomb-cli/honeycomb"
+)
+
+func TestClient_GetDatasetDefinitions(t *testing.T) {
+	t.Run("returns definitions for a dataset", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			is.Equal(t, "/1/dataset_definitions/requests", r.URL.Path)
+			is.Equal(t, http.MethodGet, r.Method)
+
+			_, _ = w.Write([]byte(`{"trace_id": {"name": "trace.trace_id", "column_type": "column"}}`))
+		}))
+		defer server.Close()
+
+		c := honeycomb.NewClient("test-key", honeycomb.WithBaseURL(server.URL))
+		definitions, err := c.GetDatasetDefinitions(t.Context(), "requests")
+		is.NotError(t, err)
+		is.Equal(t, "trace.trace_id", definitions.TraceID.Name)
+		is.Nil(t, definitions.DurationMS)
+	})
+}
+
+func TestClient_UpdateDatasetDefinitions(t *testing.T) {
+	t.Run("patches only the given definitions", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			is.Equal(t, "/1/dataset_definitions/requests", r.URL.Path)
+			is.Equal(t, http.MethodPatch, r.Method)
+
+			var body map[string]any
+			_ = json.NewDecoder(r.Body).Decode(&body)
+			is.Equal(t, 1, len(body))
+
+			_, _ = w.Write([]byte(`{"duration_ms": {"name": "duration_ms", "column_type": "column"}}`))
+		}))
+		defer server.Close()
+
+		c := honeycomb.NewClient("test-key", honeycomb.WithBaseURL(server.URL))
+		definitions, err := c.UpdateDatasetDefinitions(t.Context(), "requests", honeycomb.DatasetDefinitions{
+			DurationMS: &honeycomb.DatasetDefinition{Name: "duration_ms"},
+		})
+		is.NotError(t, err)
+		is.Equal(t, "duration_ms", definitions.DurationMS.Name)
+	})
+}
@@ -9,13 +9,19 @@ import (
 
 // Dataset in Honeycomb.
 type Dataset struct {
-	Name            string `json:"name"`
-	Slug            string `json:"slug"`
-	Description     string `json:"description,omitempty"`
-	CreatedAt       string `json:"created_at,omitempty"`
-	LastWrittenAt   string `json:"last_written_at,omitempty"`
-	RegularColumns  int    `json:"regular_columns,omitempty"`
-	ExpandJSONDepth int    `json:"expand_json_depth,omitempty"`
+	Name            string          `json:"name"`
+	Slug            string          `json:"slug"`
+	Description     string          `json:"description,omitempty"`
+	CreatedAt       string          `json:"created_at,omitempty"`
+	LastWrittenAt   string          `json:"last_written_at,omitempty"`
+	RegularColumns  int             `json:"regular_columns,omitempty"`
+	ExpandJSONDepth int             `json:"expand_json_depth,omitempty"`
+	Settings        DatasetSettings `json:"settings,omitempty"`
+}
+
+// DatasetSettings for a [Dataset].
+type DatasetSettings struct {
+	DeleteProtected bool `json:"delete_protected"`
 }
 
 // ListDatasets in the environment.
@@ -96,6 +102,45 @@ func (c *Client) CreateDataset(ctx context.Context, create CreateDatasetRequest)
 	return &dataset, nil
 }
 
+// UpdateDatasetRequest for updating a dataset. Nil fields are left unchanged.
+type UpdateDatasetRequest struct {
+	Description     *string                       `json:"description,omitempty"`
+	ExpandJSONDepth *int                          `json:"expand_json_depth,omitempty"`
+	Settings        *UpdateDatasetSettingsRequest `json:"settings,omitempty"`
+}
+
+// UpdateDatasetSettingsRequest for updating [DatasetSettings].
+type UpdateDatasetSettingsRequest struct {
+	DeleteProtected *bool `json:"delete_protected,omitempty"`
+}
+
+// UpdateDataset by slug.
+func (c *Client) UpdateDataset(ctx context.Context, slug string, update UpdateDatasetRequest) (*Dataset, error) {
+	body, err := json.Marshal(update)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, c.baseURL+"/1/datasets/"+slug, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := c.do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = res.Body.Close()
+	}()
+
+	var dataset Dataset
+	if err := json.NewDecoder(res.Body).Decode(&dataset); err != nil {
+		return nil, err
+	}
+	return &dataset, nil
+}
+
 // DeleteDataset by slug.
 func (c *Client) DeleteDataset(ctx context.Context, slug string) error {
 	req, err := http.NewRequestWithContext(ctx, http.MethodDelete, c.baseURL+"/1/datasets/"+slug, nil)
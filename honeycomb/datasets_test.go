@@ -94,3 +94,29 @@ func TestClient_DeleteDataset(t *testing.T) {
 		is.NotError(t, err)
 	})
 }
+
+func TestClient_UpdateDataset(t *testing.T) {
+	t.Run("sends only the fields that are set", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			is.Equal(t, "/1/datasets/requests", r.URL.Path)
+			is.Equal(t, http.MethodPut, r.Method)
+
+			var body map[string]any
+			_ = json.NewDecoder(r.Body).Decode(&body)
+			is.Equal(t, 1, len(body))
+			settings := body["settings"].(map[string]any)
+			is.Equal(t, false, settings["delete_protected"].(bool))
+
+			_ = json.NewEncoder(w).Encode(honeycomb.Dataset{Name: "requests", Slug: "requests"})
+		}))
+		defer server.Close()
+
+		deleteProtected := false
+		c := honeycomb.NewClient("test-key", honeycomb.WithBaseURL(server.URL))
+		dataset, err := c.UpdateDataset(t.Context(), "requests", honeycomb.UpdateDatasetRequest{
+			Settings: &honeycomb.UpdateDatasetSettingsRequest{DeleteProtected: &deleteProtected},
+		})
+		is.NotError(t, err)
+		is.True(t, !dataset.Settings.DeleteProtected)
+	})
+}
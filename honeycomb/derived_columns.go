@@ -0,0 +1,133 @@
+package honeycomb
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// DerivedColumn in a Honeycomb dataset. Derived columns compute values from other columns at query time.
+type DerivedColumn struct {
+	ID          string `json:"id,omitempty"`
+	Alias       string `json:"alias"`
+	Expression  string `json:"expression"`
+	Description string `json:"description,omitempty"`
+	CreatedAt   string `json:"created_at,omitempty"`
+	UpdatedAt   string `json:"updated_at,omitempty"`
+}
+
+// ListDerivedColumns for a dataset. Use "__all__" for environment-wide derived columns.
+func (c *Client) ListDerivedColumns(ctx context.Context, dataset string) ([]DerivedColumn, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+"/1/derived_columns/"+dataset, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := c.do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = res.Body.Close()
+	}()
+
+	var columns []DerivedColumn
+	if err := json.NewDecoder(res.Body).Decode(&columns); err != nil {
+		return nil, err
+	}
+	return columns, nil
+}
+
+// GetDerivedColumn by ID for a dataset.
+func (c *Client) GetDerivedColumn(ctx context.Context, dataset, id string) (*DerivedColumn, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("%v/1/derived_columns/%v/%v", c.baseURL, dataset, id), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := c.do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = res.Body.Close()
+	}()
+
+	var column DerivedColumn
+	if err := json.NewDecoder(res.Body).Decode(&column); err != nil {
+		return nil, err
+	}
+	return &column, nil
+}
+
+// CreateDerivedColumn in a dataset. The ID is ignored.
+func (c *Client) CreateDerivedColumn(ctx context.Context, dataset string, column DerivedColumn) (*DerivedColumn, error) {
+	column.ID = ""
+	body, err := json.Marshal(column)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+"/1/derived_columns/"+dataset, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := c.do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = res.Body.Close()
+	}()
+
+	var created DerivedColumn
+	if err := json.NewDecoder(res.Body).Decode(&created); err != nil {
+		return nil, err
+	}
+	return &created, nil
+}
+
+// UpdateDerivedColumn replaces the derived column with the given column's ID.
+func (c *Client) UpdateDerivedColumn(ctx context.Context, dataset string, column DerivedColumn) (*DerivedColumn, error) {
+	body, err := json.Marshal(column)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, fmt.Sprintf("%v/1/derived_columns/%v/%v", c.baseURL, dataset, column.ID), bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := c.do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = res.Body.Close()
+	}()
+
+	var updated DerivedColumn
+	if err := json.NewDecoder(res.Body).Decode(&updated); err != nil {
+		return nil, err
+	}
+	return &updated, nil
+}
+
+// DeleteDerivedColumn by ID for a dataset.
+func (c *Client) DeleteDerivedColumn(ctx context.Context, dataset, id string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodDelete, fmt.Sprintf("%v/1/derived_columns/%v/%v", c.baseURL, dataset, id), nil)
+	if err != nil {
+		return err
+	}
+
+	res, err := c.do(req)
+	if err != nil {
+		return err
+	}
+	_ = res.Body.Close()
+	return nil
+}
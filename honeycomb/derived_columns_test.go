@@ -0,0 +1,114 @@
+package honeycomb_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"maragu.dev/is"
+
+	"github.com/maragudk/honeycomb-cli/honeycomb"
+)
+
+func TestClient_ListDerivedColumns(t *testing.T) {
+	t.Run("returns derived columns for a dataset", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			is.Equal(t, "/1/derived_columns/requests", r.URL.Path)
+			is.Equal(t, http.MethodGet, r.Method)
+
+			_ = json.NewEncoder(w).Encode([]honeycomb.DerivedColumn{
+				{ID: "dc1", Alias: "is_error", Expression: "GTE($status_code, 500)"},
+			})
+		}))
+		defer server.Close()
+
+		c := honeycomb.NewClient("test-key", honeycomb.WithBaseURL(server.URL))
+		columns, err := c.ListDerivedColumns(t.Context(), "requests")
+		is.NotError(t, err)
+		is.Equal(t, 1, len(columns))
+		is.Equal(t, "is_error", columns[0].Alias)
+	})
+}
+
+func TestClient_GetDerivedColumn(t *testing.T) {
+	t.Run("returns a derived column by ID", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			is.Equal(t, "/1/derived_columns/requests/dc1", r.URL.Path)
+			is.Equal(t, http.MethodGet, r.Method)
+
+			_ = json.NewEncoder(w).Encode(honeycomb.DerivedColumn{ID: "dc1", Alias: "is_error"})
+		}))
+		defer server.Close()
+
+		c := honeycomb.NewClient("test-key", honeycomb.WithBaseURL(server.URL))
+		column, err := c.GetDerivedColumn(t.Context(), "requests", "dc1")
+		is.NotError(t, err)
+		is.Equal(t, "is_error", column.Alias)
+	})
+}
+
+func TestClient_CreateDerivedColumn(t *testing.T) {
+	t.Run("creates a derived column", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			is.Equal(t, "/1/derived_columns/requests", r.URL.Path)
+			is.Equal(t, http.MethodPost, r.Method)
+
+			var column honeycomb.DerivedColumn
+			_ = json.NewDecoder(r.Body).Decode(&column)
+			is.Equal(t, "is_error", column.Alias)
+			is.Equal(t, "GTE($status_code, 500)", column.Expression)
+
+			column.ID = "dc1"
+			w.WriteHeader(http.StatusCreated)
+			_ = json.NewEncoder(w).Encode(column)
+		}))
+		defer server.Close()
+
+		c := honeycomb.NewClient("test-key", honeycomb.WithBaseURL(server.URL))
+		column, err := c.CreateDerivedColumn(t.Context(), "requests", honeycomb.DerivedColumn{
+			Alias:      "is_error",
+			Expression: "GTE($status_code, 500)",
+		})
+		is.NotError(t, err)
+		is.Equal(t, "dc1", column.ID)
+	})
+}
+
+func TestClient_UpdateDerivedColumn(t *testing.T) {
+	t.Run("updates a derived column", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			is.Equal(t, "/1/derived_columns/requests/dc1", r.URL.Path)
+			is.Equal(t, http.MethodPut, r.Method)
+
+			var column honeycomb.DerivedColumn
+			_ = json.NewDecoder(r.Body).Decode(&column)
+			_ = json.NewEncoder(w).Encode(column)
+		}))
+		defer server.Close()
+
+		c := honeycomb.NewClient("test-key", honeycomb.WithBaseURL(server.URL))
+		column, err := c.UpdateDerivedColumn(t.Context(), "requests", honeycomb.DerivedColumn{
+			ID:         "dc1",
+			Alias:      "is_error",
+			Expression: "GTE($status_code, 400)",
+		})
+		is.NotError(t, err)
+		is.Equal(t, "GTE($status_code, 400)", column.Expression)
+	})
+}
+
+func TestClient_DeleteDerivedColumn(t *testing.T) {
+	t.Run("deletes a derived column", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			is.Equal(t, "/1/derived_columns/requests/dc1", r.URL.Path)
+			is.Equal(t, http.MethodDelete, r.Method)
+			w.WriteHeader(http.StatusNoContent)
+		}))
+		defer server.Close()
+
+		c := honeycomb.NewClient("test-key", honeycomb.WithBaseURL(server.URL))
+		err := c.DeleteDerivedColumn(t.Context(), "requests", "dc1")
+		is.NotError(t, err)
+	})
+}
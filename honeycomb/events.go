@@ -0,0 +1,56 @@
+package honeycomb
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// Event to send to a dataset with [Client.SendEvents].
+type Event struct {
+	Time time.Time `json:"time,omitzero"`
+	// SampleRate of the event, where 1 in SampleRate events was sent. Zero means 1.
+	SampleRate int            `json:"samplerate,omitempty"`
+	Data       map[string]any `json:"data"`
+}
+
+// EventResult for an event sent with [Client.SendEvents]. The status is 202 for accepted events.
+type EventResult struct {
+	Status int    `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
+
+// SendEvents to a dataset with the batch events API, which creates the dataset if it doesn't exist.
+// The results are in the same order as the events. Sending events needs an ingest key.
+func (c *Client) SendEvents(ctx context.Context, dataset string, events []Event) ([]EventResult, error) {
+	body, err := json.Marshal(events)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+"/1/batch/"+url.PathEscape(dataset), bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := c.do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = res.Body.Close()
+	}()
+
+	var results []EventResult
+	if err := json.NewDecoder(res.Body).Decode(&results); err != nil {
+		return nil, err
+	}
+	if len(results) != len(events) {
+		return nil, fmt.Errorf("got %v results for %v events", len(results), len(events))
+	}
+	return results, nil
+}
@@ -0,0 +1,57 @@
+package honeycomb_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"maragu.dev/is"
+
+	"github.com/maragudk/honeycomb-cli/honeycomb"
+)
+
+func TestClient_SendEvents(t *testing.T) {
+	t.Run("sends events to the batch API", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			is.Equal(t, http.MethodPost, r.Method)
+			is.Equal(t, "/1/batch/my service", r.URL.Path)
+			is.Equal(t, "test-key", r.Header.Get("X-Honeycomb-Team"))
+
+			var events []map[string]any
+			_ = json.NewDecoder(r.Body).Decode(&events)
+			is.Equal(t, 2, len(events))
+			is.Equal(t, "2026-10-19T12:00:00.5Z", events[0]["time"].(string))
+			is.Equal(t, "GET /", events[0]["data"].(map[string]any)["name"].(string))
+			_, ok := events[1]["time"]
+			is.True(t, !ok)
+			is.Equal(t, float64(10), events[1]["samplerate"].(float64))
+
+			_ = json.NewEncoder(w).Encode([]honeycomb.EventResult{{Status: 202}, {Status: 400, Error: "bad event"}})
+		}))
+		defer server.Close()
+
+		c := honeycomb.NewClient("test-key", honeycomb.WithBaseURL(server.URL))
+		results, err := c.SendEvents(t.Context(), "my service", []honeycomb.Event{
+			{Time: time.Date(2026, 10, 19, 12, 0, 0, 500_000_000, time.UTC), Data: map[string]any{"name": "GET /"}},
+			{SampleRate: 10, Data: map[string]any{"name": "GET /health"}},
+		})
+		is.NotError(t, err)
+		is.Equal(t, 2, len(results))
+		is.Equal(t, 202, results[0].Status)
+		is.Equal(t, "bad event", results[1].Error)
+	})
+
+	t.Run("errors on a mismatched number of results", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_ = json.NewEncoder(w).Encode([]honeycomb.EventResult{})
+		}))
+		defer server.Close()
+
+		c := honeycomb.NewClient("test-key", honeycomb.WithBaseURL(server.URL))
+		_, err := c.SendEvents(t.Context(), "requests", []honeycomb.Event{{Data: map[string]any{}}})
+		is.True(t, err != nil)
+		is.Equal(t, "got 0 results for 1 events", err.Error())
+	})
+}
@@ -0,0 +1,287 @@
+package honeycomb
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// ExpressionError is a syntax or semantic error in a derived column expression.
+type ExpressionError struct {
+	// Pos is the character offset in the expression where the error was found.
+	Pos int
+	Msg string
+}
+
+func (e *ExpressionError) Error() string {
+	return fmt.Sprintf("position %v: %v", e.Pos+1, e.Msg)
+}
+
+// expressionFunctions maps function names to their minimum and maximum number of arguments.
+// A maximum of -1 means the function is variadic.
+var expressionFunctions = map[string][2]int{
+	// Conditionals
+	"IF":       {2, -1},
+	"SWITCH":   {2, -1},
+	"COALESCE": {1, -1},
+	"LT":       {2, 2},
+	"LTE":      {2, 2},
+	"GT":       {2, 2},
+	"GTE":      {2, 2},
+	"EQUALS":   {2, 2},
+	"IN":       {2, -1},
+	"EXISTS":   {1, 1},
+	"NOT":      {1, 1},
+	"AND":      {1, -1},
+	"OR":       {1, -1},
+
+	// Math
+	"MIN":    {1, -1},
+	"MAX":    {1, -1},
+	"SUM":    {1, -1},
+	"SUB":    {2, 2},
+	"MUL":    {1, -1},
+	"DIV":    {2, 2},
+	"MOD":    {2, 2},
+	"LOG10":  {1, 1},
+	"BUCKET": {2, 4},
+
+	// Casts
+	"INT":    {1, 1},
+	"FLOAT":  {1, 1},
+	"BOOL":   {1, 1},
+	"STRING": {1, 1},
+
+	// Strings
+	"CONCAT":      {1, -1},
+	"STARTS_WITH": {2, 2},
+	"CONTAINS":    {2, 2},
+	"REG_MATCH":   {2, 2},
+	"REG_VALUE":   {2, 2},
+	"REG_COUNT":   {2, 2},
+	"LENGTH":      {1, 2},
+	"TO_LOWER":    {1, 1},
+
+	// Time
+	"UNIX_TIMESTAMP":   {1, 2},
+	"EVENT_TIMESTAMP":  {0, 0},
+	"INGEST_TIMESTAMP": {0, 0},
+	"FORMAT_TIME":      {2, 2},
+}
+
+// ValidateExpression parses a derived column expression and returns the columns it references,
+// in order of first appearance. Errors are of type [*ExpressionError].
+func ValidateExpression(expr string) ([]string, error) {
+	p := &expressionParser{s: []rune(expr), seen: map[string]bool{}}
+	p.skipSpace()
+	if p.pos == len(p.s) {
+		return nil, &ExpressionError{Pos: 0, Msg: "expression is empty"}
+	}
+	if _, _, err := p.parseValue(); err != nil {
+		return nil, err
+	}
+	p.skipSpace()
+	if p.pos < len(p.s) {
+		return nil, p.errorf("unexpected %q after end of expression", string(p.s[p.pos]))
+	}
+	return p.columns, nil
+}
+
+type expressionParser struct {
+	s       []rune
+	pos     int
+	columns []string
+	seen    map[string]bool
+}
+
+func (p *expressionParser) errorf(format string, args ...any) error {
+	return &ExpressionError{Pos: p.pos, Msg: fmt.Sprintf(format, args...)}
+}
+
+func (p *expressionParser) skipSpace() {
+	for p.pos < len(p.s) && unicode.IsSpace(p.s[p.pos]) {
+		p.pos++
+	}
+}
+
+// parseValue parses a single value. For string literals, it also returns the string contents,
+// so that function arguments such as regular expressions can be checked.
+func (p *expressionParser) parseValue() (bool, string, error) {
+	p.skipSpace()
+	if p.pos == len(p.s) {
+		return false, "", p.errorf("unexpected end of expression")
+	}
+
+	switch ch := p.s[p.pos]; {
+	case ch == '$':
+		return false, "", p.parseColumn()
+	case ch == '"' || ch == '`':
+		s, err := p.parseString()
+		return true, s, err
+	case ch == '-' || ch == '.' || (ch >= '0' && ch <= '9'):
+		return false, "", p.parseNumber()
+	case ch == '_' || unicode.IsLetter(ch):
+		return false, "", p.parseIdentifier()
+	default:
+		return false, "", p.errorf("unexpected %q", string(ch))
+	}
+}
+
+func (p *expressionParser) parseColumn() error {
+	start := p.pos
+	p.pos++ // $
+
+	if p.pos < len(p.s) && p.s[p.pos] == '"' {
+		name, err := p.parseString()
+		if err != nil {
+			return err
+		}
+		if name == "" {
+			return &ExpressionError{Pos: start, Msg: "empty column name"}
+		}
+		p.addColumn(name)
+		return nil
+	}
+
+	nameStart := p.pos
+	for p.pos < len(p.s) && isColumnChar(p.s[p.pos]) {
+		p.pos++
+	}
+	if p.pos == nameStart {
+		return &ExpressionError{Pos: start, Msg: `expected column name after "$"`}
+	}
+	p.addColumn(string(p.s[nameStart:p.pos]))
+	return nil
+}
+
+func (p *expressionParser) addColumn(name string) {
+	if !p.seen[name] {
+		p.seen[name] = true
+		p.columns = append(p.columns, name)
+	}
+}
+
+func isColumnChar(ch rune) bool {
+	return ch == '_' || ch == '.' || ch == '-' || ch == '/' ||
+		(ch >= 'a' && ch <= 'z') || (ch >= 'A' && ch <= 'Z') || (ch >= '0' && ch <= '9')
+}
+
+func (p *expressionParser) parseString() (string, error) {
+	start := p.pos
+	quote := p.s[p.pos]
+	p.pos++
+
+	var b strings.Builder
+	for p.pos < len(p.s) {
+		ch := p.s[p.pos]
+		switch {
+		case ch == quote:
+			p.pos++
+			return b.String(), nil
+		case ch == '\\' && quote == '"':
+			if p.pos+1 == len(p.s) {
+				return "", &ExpressionError{Pos: start, Msg: "unterminated string"}
+			}
+			b.WriteRune(p.s[p.pos+1])
+			p.pos += 2
+		default:
+			b.WriteRune(ch)
+			p.pos++
+		}
+	}
+	return "", &ExpressionError{Pos: start, Msg: "unterminated string"}
+}
+
+var numberRegexp = regexp.MustCompile(`^-?(\d+\.?\d*|\.\d+)([eE][+-]?\d+)?$`)
+
+// parseNumber consumes everything that looks like part of a number, and then checks that it is one,
+// so that input like "1.2.3" and "1e" is reported as a single invalid number.
+func (p *expressionParser) parseNumber() error {
+	start := p.pos
+	if p.s[p.pos] == '-' {
+		p.pos++
+	}
+	for p.pos < len(p.s) {
+		ch := p.s[p.pos]
+		isExponentSign := (ch == '+' || ch == '-') && (p.s[p.pos-1] == 'e' || p.s[p.pos-1] == 'E')
+		if !(ch >= '0' && ch <= '9' || ch == '.' || ch == 'e' || ch == 'E' || isExponentSign) {
+			break
+		}
+		p.pos++
+	}
+	if number := string(p.s[start:p.pos]); !numberRegexp.MatchString(number) {
+		return &ExpressionError{Pos: start, Msg: fmt.Sprintf("invalid number %q", number)}
+	}
+	return nil
+}
+
+func (p *expressionParser) parseIdentifier() error {
+	start := p.pos
+	for p.pos < len(p.s) && (p.s[p.pos] == '_' || unicode.IsLetter(p.s[p.pos]) || unicode.IsDigit(p.s[p.pos])) {
+		p.pos++
+	}
+	name := string(p.s[start:p.pos])
+
+	p.skipSpace()
+	if p.pos == len(p.s) || p.s[p.pos] != '(' {
+		switch name {
+		case "true", "false", "null":
+			return nil
+		}
+		return &ExpressionError{Pos: start, Msg: fmt.Sprintf("unexpected identifier %q (column references start with $)", name)}
+	}
+
+	arity, ok := expressionFunctions[name]
+	if !ok {
+		if _, ok := expressionFunctions[strings.ToUpper(name)]; ok {
+			return &ExpressionError{Pos: start, Msg: fmt.Sprintf("unknown function %v (did you mean %v?)", name, strings.ToUpper(name))}
+		}
+		return &ExpressionError{Pos: start, Msg: fmt.Sprintf("unknown function %v", name)}
+	}
+
+	p.pos++ // (
+	var args int
+	p.skipSpace()
+	if p.pos < len(p.s) && p.s[p.pos] == ')' {
+		p.pos++
+	} else {
+		for {
+			p.skipSpace()
+			argStart := p.pos
+			isString, value, err := p.parseValue()
+			if err != nil {
+				return err
+			}
+			args++
+
+			if args == 2 && strings.HasPrefix(name, "REG_") && isString {
+				if _, err := regexp.Compile(value); err != nil {
+					return &ExpressionError{Pos: argStart, Msg: fmt.Sprintf("invalid regular expression in %v: %v", name, err)}
+				}
+			}
+
+			p.skipSpace()
+			if p.pos == len(p.s) {
+				return &ExpressionError{Pos: start, Msg: fmt.Sprintf("missing closing parenthesis for %v", name)}
+			}
+			if p.s[p.pos] == ')' {
+				p.pos++
+				break
+			}
+			if p.s[p.pos] != ',' {
+				return p.errorf("expected \",\" or \")\" in arguments to %v, got %q", name, string(p.s[p.pos]))
+			}
+			p.pos++
+		}
+	}
+
+	minArgs, maxArgs := arity[0], arity[1]
+	switch {
+	case args < minArgs:
+		return &ExpressionError{Pos: start, Msg: fmt.Sprintf("%v takes at least %v argument(s), got %v", name, minArgs, args)}
+	case maxArgs >= 0 && args > maxArgs:
+		return &ExpressionError{Pos: start, Msg: fmt.Sprintf("%v takes at most %v argument(s), got %v", name, maxArgs, args)}
+	}
+	return nil
+}
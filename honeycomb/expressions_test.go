@@ -0,0 +1,59 @@
+package honeycomb_test
+
+import (
+	"errors"
+	"testing"
+
+	"maragu.dev/is"
+
+	"github.com/maragudk/honeycomb-cli/honeycomb"
+)
+
+func TestValidateExpression(t *testing.T) {
+	tests := []struct {
+		name        string
+		expr        string
+		wantColumns []string
+		wantErr     bool
+		wantErrPos  int
+	}{
+		{name: "column reference", expr: "$duration_ms", wantColumns: []string{"duration_ms"}},
+		{name: "quoted column reference", expr: `$"http status"`, wantColumns: []string{"http status"}},
+		{name: "dotted column reference", expr: "$http.status_code", wantColumns: []string{"http.status_code"}},
+		{name: "nested functions", expr: `IF(GTE($status_code, 500), "error", COALESCE($level, "ok"))`, wantColumns: []string{"status_code", "level"}},
+		{name: "deduplicates columns", expr: "SUM($a, $b, $a)", wantColumns: []string{"a", "b"}},
+		{name: "regex match", expr: `REG_MATCH($path, "^/api/v[0-9]+")`, wantColumns: []string{"path"}},
+		{name: "bucket with optional arguments", expr: "BUCKET($duration_ms, 100, 0, 1000)", wantColumns: []string{"duration_ms"}},
+		{name: "no-argument function", expr: "EVENT_TIMESTAMP()"},
+		{name: "literals", expr: `IF(true, -1.5, null)`},
+		{name: "number with exponent", expr: "MUL($a, 1.5e-3, .5, 2E10)", wantColumns: []string{"a"}},
+		{name: "empty expression", expr: "  ", wantErr: true, wantErrPos: 0},
+		{name: "unknown function", expr: "FOO($a)", wantErr: true, wantErrPos: 0},
+		{name: "lowercase function", expr: "if($a, 1)", wantErr: true, wantErrPos: 0},
+		{name: "too few arguments", expr: "DIV($a)", wantErr: true, wantErrPos: 0},
+		{name: "too many arguments", expr: "NOT($a, $b)", wantErr: true, wantErrPos: 0},
+		{name: "missing closing parenthesis", expr: "COALESCE($a, $b", wantErr: true, wantErrPos: 0},
+		{name: "unterminated string", expr: `CONCAT($a, "foo)`, wantErr: true, wantErrPos: 11},
+		{name: "invalid regex", expr: `REG_VALUE($path, "(unclosed")`, wantErr: true, wantErrPos: 17},
+		{name: "bare identifier", expr: "CONCAT($a, name)", wantErr: true, wantErrPos: 11},
+		{name: "trailing input", expr: "$a $b", wantErr: true, wantErrPos: 3},
+		{name: "empty column name", expr: "EXISTS($)", wantErr: true, wantErrPos: 7},
+		{name: "second decimal point", expr: "SUM($a, 1.2.3)", wantErr: true, wantErrPos: 8},
+		{name: "exponent without digits", expr: "SUM($a, 1e)", wantErr: true, wantErrPos: 8},
+		{name: "position after non-ASCII characters", expr: `CONCAT("héllo", name)`, wantErr: true, wantErrPos: 16},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			columns, err := honeycomb.ValidateExpression(test.expr)
+			if test.wantErr {
+				var exprErr *honeycomb.ExpressionError
+				is.True(t, errors.As(err, &exprErr))
+				is.Equal(t, test.wantErrPos, exprErr.Pos)
+				return
+			}
+			is.NotError(t, err)
+			is.EqualSlice(t, test.wantColumns, columns)
+		})
+	}
+}
@@ -0,0 +1,72 @@
+package honeycomb
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// GraphImageOptions for [Client.DownloadGraphImage].
+type GraphImageOptions struct {
+	// Width of the image in pixels, or zero for the default width.
+	Width int
+	// Height of the image in pixels, or zero for the default height.
+	Height int
+}
+
+// DownloadGraphImage of a completed query result, and write it to w.
+// The API key is only sent along if the image is served from the API itself.
+func (c *Client) DownloadGraphImage(ctx context.Context, result *QueryResult, w io.Writer, opts GraphImageOptions) error {
+	if result.Links.GraphURL == "" {
+		return fmt.Errorf("query result %v has no graph image", result.ID)
+	}
+
+	u, err := url.Parse(result.Links.GraphURL)
+	if err != nil {
+		return fmt.Errorf("parsing graph image URL: %w", err)
+	}
+	q := u.Query()
+	if opts.Width > 0 {
+		q.Set("width", strconv.Itoa(opts.Width))
+	}
+	if opts.Height > 0 {
+		q.Set("height", strconv.Itoa(opts.Height))
+	}
+	u.RawQuery = q.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return err
+	}
+
+	var res *http.Response
+	if api, err := url.Parse(c.baseURL); err == nil && api.Host == u.Host {
+		res, err = c.do(req)
+		if err != nil {
+			return err
+		}
+	} else {
+		res, err = c.http.Do(req)
+		if err != nil {
+			return err
+		}
+		if res.StatusCode >= 400 {
+			_ = res.Body.Close()
+			return fmt.Errorf("downloading graph image for query result %v: %v", result.ID, res.Status)
+		}
+	}
+	defer func() {
+		_ = res.Body.Close()
+	}()
+
+	if contentType := res.Header.Get("Content-Type"); contentType != "" && !strings.HasPrefix(contentType, "image/") {
+		return fmt.Errorf("graph image for query result %v is %v, not an image", result.ID, contentType)
+	}
+
+	_, err = io.Copy(w, res.Body)
+	return err
+}
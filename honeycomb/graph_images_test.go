@@ -0,0 +1,94 @@
+package honeycomb_test
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"maragu.dev/is"
+
+	"github.com/maragudk/honeycomb-cli/honeycomb"
+)
+
+func TestClient_DownloadGraphImage(t *testing.T) {
+	t.Run("downloads the graph image with the given size", func(t *testing.T) {
+		images := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			is.Equal(t, "/graph/r1.png", r.URL.Path)
+			is.Equal(t, "abc", r.URL.Query().Get("sig"))
+			is.Equal(t, "1200", r.URL.Query().Get("width"))
+			is.Equal(t, "600", r.URL.Query().Get("height"))
+			is.Equal(t, "", r.Header.Get("X-Honeycomb-Team"))
+			w.Header().Set("Content-Type", "image/png")
+			_, _ = w.Write([]byte("png data"))
+		}))
+		defer images.Close()
+
+		result := &honeycomb.QueryResult{ID: "r1"}
+		result.Links.GraphURL = images.URL + "/graph/r1.png?sig=abc"
+
+		c := honeycomb.NewClient("test-key", honeycomb.WithBaseURL("https://api.honeycomb.io"))
+		var buf bytes.Buffer
+		err := c.DownloadGraphImage(t.Context(), result, &buf, honeycomb.GraphImageOptions{Width: 1200, Height: 600})
+		is.NotError(t, err)
+		is.Equal(t, "png data", buf.String())
+	})
+
+	t.Run("sends the API key when the image is served from the API", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			is.Equal(t, "test-key", r.Header.Get("X-Honeycomb-Team"))
+			is.Equal(t, "", r.URL.RawQuery)
+			w.Header().Set("Content-Type", "image/png")
+			_, _ = w.Write([]byte("png data"))
+		}))
+		defer server.Close()
+
+		result := &honeycomb.QueryResult{ID: "r1"}
+		result.Links.GraphURL = server.URL + "/graph/r1.png"
+
+		c := honeycomb.NewClient("test-key", honeycomb.WithBaseURL(server.URL))
+		var buf bytes.Buffer
+		err := c.DownloadGraphImage(t.Context(), result, &buf, honeycomb.GraphImageOptions{})
+		is.NotError(t, err)
+		is.Equal(t, "png data", buf.String())
+	})
+
+	t.Run("errors if the result has no graph image", func(t *testing.T) {
+		c := honeycomb.NewClient("test-key")
+		err := c.DownloadGraphImage(t.Context(), &honeycomb.QueryResult{ID: "r1"}, &bytes.Buffer{}, honeycomb.GraphImageOptions{})
+		is.True(t, err != nil)
+		is.Equal(t, "query result r1 has no graph image", err.Error())
+	})
+
+	t.Run("errors if the response isn't an image", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "text/html")
+			_, _ = w.Write([]byte("<html>Log in</html>"))
+		}))
+		defer server.Close()
+
+		result := &honeycomb.QueryResult{ID: "r1"}
+		result.Links.GraphURL = server.URL + "/graph/r1.png"
+
+		c := honeycomb.NewClient("test-key")
+		err := c.DownloadGraphImage(t.Context(), result, &bytes.Buffer{}, honeycomb.GraphImageOptions{})
+		is.True(t, err != nil)
+		is.True(t, strings.Contains(err.Error(), "not an image"))
+	})
+
+	t.Run("errors if the download fails", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			http.Error(w, "gone", http.StatusNotFound)
+		}))
+		defer server.Close()
+
+		result := &honeycomb.QueryResult{ID: "r1"}
+		result.Links.GraphURL = server.URL + "/graph/r1.png"
+
+		c := honeycomb.NewClient("test-key")
+		err := c.DownloadGraphImage(t.Context(), result, &bytes.Buffer{}, honeycomb.GraphImageOptions{})
+		is.True(t, err != nil)
+		is.True(t, strings.Contains(err.Error(), "404"))
+	})
+}
@@ -0,0 +1,81 @@
+package honeycomb
+
+import (
+	"context"
+	"encoding/json"
+	"net/url"
+	"strings"
+)
+
+// UILinks builds URLs to resources in the Honeycomb UI, for a team and environment.
+type UILinks struct {
+	// BaseURL of the UI, like https://ui.honeycomb.io.
+	BaseURL string
+	Team    string
+	// Environment is empty for classic environments.
+	Environment string
+}
+
+// UILinks for the team and environment of the client's API key, in the UI of the client's API region.
+func (c *Client) UILinks(ctx context.Context) (*UILinks, error) {
+	auth, err := c.Auth(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &UILinks{BaseURL: uiBaseURL(c.baseURL), Team: auth.Team.Slug, Environment: auth.Environment.Slug}, nil
+}
+
+// uiBaseURL for an API URL, like https://ui.eu1.honeycomb.io for https://api.eu1.honeycomb.io.
+// API URLs that don't start with "api." get the default UI.
+func uiBaseURL(apiURL string) string {
+	u, err := url.Parse(apiURL)
+	if err != nil || !strings.HasPrefix(u.Host, "api.") {
+		return "https://ui.honeycomb.io"
+	}
+	return "https://ui." + strings.TrimPrefix(u.Host, "api.")
+}
+
+// QueryResult URL for a query result in a dataset.
+func (l UILinks) QueryResult(dataset, resultID string) string {
+	return l.datasetURL(dataset) + "/result/" + url.PathEscape(resultID)
+}
+
+// Query URL that opens the query builder with the given query in a dataset.
+func (l UILinks) Query(dataset string, spec QuerySpec) (string, error) {
+	data, err := json.Marshal(spec)
+	if err != nil {
+		return "", err
+	}
+	return l.datasetURL(dataset) + "?query=" + url.QueryEscape(string(data)), nil
+}
+
+// Board URL.
+func (l UILinks) Board(id string) string {
+	return l.environmentURL() + "/board/" + url.PathEscape(id)
+}
+
+// Trigger URL for a trigger in a dataset.
+func (l UILinks) Trigger(dataset, id string) string {
+	return l.datasetURL(dataset) + "/triggers/" + url.PathEscape(id)
+}
+
+// SLO URL for an SLO in a dataset.
+func (l UILinks) SLO(dataset, id string) string {
+	return l.datasetURL(dataset) + "/slos/" + url.PathEscape(id)
+}
+
+func (l UILinks) environmentURL() string {
+	u := strings.TrimSuffix(l.BaseURL, "/") + "/" + url.PathEscape(l.Team)
+	if l.Environment != "" {
+		u += "/environments/" + url.PathEscape(l.Environment)
+	}
+	return u
+}
+
+// datasetURL is the environment URL for environment-wide queries with the __all__ dataset.
+func (l UILinks) datasetURL(dataset string) string {
+	if dataset == "__all__" {
+		return l.environmentURL()
+	}
+	return l.environmentURL() + "/datasets/" + url.PathEscape(dataset)
+}
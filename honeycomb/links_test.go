@@ -0,0 +1,67 @@
+package honeycomb_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"maragu.dev/is"
+
+	"github.com/maragudk/honeycomb-cli/honeycomb"
+)
+
+func TestClient_UILinks(t *testing.T) {
+	t.Run("gets the team and environment from auth", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			is.Equal(t, "/1/auth", r.URL.Path)
+			_ = json.NewEncoder(w).Encode(honeycomb.AuthResponse{
+				Team:        honeycomb.AuthTeam{Slug: "acme"},
+				Environment: honeycomb.AuthEnvironment{Slug: "production"},
+			})
+		}))
+		defer server.Close()
+
+		c := honeycomb.NewClient("test-key", honeycomb.WithBaseURL(server.URL))
+		l, err := c.UILinks(t.Context())
+		is.NotError(t, err)
+		is.Equal(t, "https://ui.honeycomb.io", l.BaseURL)
+		is.Equal(t, "acme", l.Team)
+		is.Equal(t, "production", l.Environment)
+	})
+}
+
+func TestUILinks(t *testing.T) {
+	l := honeycomb.UILinks{BaseURL: "https://ui.honeycomb.io", Team: "acme", Environment: "production"}
+
+	t.Run("links to a query result", func(t *testing.T) {
+		is.Equal(t, "https://ui.honeycomb.io/acme/environments/production/datasets/api/result/r1", l.QueryResult("api", "r1"))
+	})
+
+	t.Run("links to an environment-wide query result", func(t *testing.T) {
+		is.Equal(t, "https://ui.honeycomb.io/acme/environments/production/result/r1", l.QueryResult("__all__", "r1"))
+	})
+
+	t.Run("links to a query in the query builder", func(t *testing.T) {
+		u, err := l.Query("api", honeycomb.QuerySpec{Calculations: []honeycomb.Calculation{{Op: "COUNT"}}, TimeRange: 3600})
+		is.NotError(t, err)
+		is.True(t, strings.HasPrefix(u, "https://ui.honeycomb.io/acme/environments/production/datasets/api?query="))
+
+		parsed, err := url.Parse(u)
+		is.NotError(t, err)
+		is.Equal(t, `{"calculations":[{"op":"COUNT"}],"time_range":3600}`, parsed.Query().Get("query"))
+	})
+
+	t.Run("links to boards, triggers, and SLOs", func(t *testing.T) {
+		is.Equal(t, "https://ui.honeycomb.io/acme/environments/production/board/b1", l.Board("b1"))
+		is.Equal(t, "https://ui.honeycomb.io/acme/environments/production/datasets/api/triggers/t1", l.Trigger("api", "t1"))
+		is.Equal(t, "https://ui.honeycomb.io/acme/environments/production/datasets/api/slos/s1", l.SLO("api", "s1"))
+	})
+
+	t.Run("links to classic environments without an environment slug", func(t *testing.T) {
+		classic := honeycomb.UILinks{BaseURL: "https://ui.honeycomb.io", Team: "acme"}
+		is.Equal(t, "https://ui.honeycomb.io/acme/datasets/api/result/r1", classic.QueryResult("api", "r1"))
+	})
+}
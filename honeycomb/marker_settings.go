@@ -0,0 +1,67 @@
+package honeycomb
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+)
+
+// MarkerSetting assigns a color to a marker type in a dataset.
+type MarkerSetting struct {
+	ID        string `json:"id,omitempty"`
+	Type      string `json:"type"`
+	Color     string `json:"color"`
+	CreatedAt string `json:"created_at,omitempty"`
+	UpdatedAt string `json:"updated_at,omitempty"`
+}
+
+// ListMarkerSettings for a dataset. Use "__all__" for environment-wide marker settings.
+func (c *Client) ListMarkerSettings(ctx context.Context, dataset string) ([]MarkerSetting, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+"/1/marker_settings/"+dataset, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := c.do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = res.Body.Close()
+	}()
+
+	var settings []MarkerSetting
+	if err := json.NewDecoder(res.Body).Decode(&settings); err != nil {
+		return nil, err
+	}
+	return settings, nil
+}
+
+// CreateMarkerSetting for a dataset. The ID is ignored.
+func (c *Client) CreateMarkerSetting(ctx context.Context, dataset string, setting MarkerSetting) (*MarkerSetting, error) {
+	setting.ID = ""
+	body, err := json.Marshal(setting)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+"/1/marker_settings/"+dataset, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := c.do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = res.Body.Close()
+	}()
+
+	var created MarkerSetting
+	if err := json.NewDecoder(res.Body).Decode(&created); err != nil {
+		return nil, err
+	}
+	return &created, nil
+}
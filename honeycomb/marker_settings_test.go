@@ -0,0 +1,53 @@
+package honeycomb_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"maragu.dev/is"
+
+	"github.com/maragudk/honeycomb-cli/honeycomb"
+)
+
+func TestClient_ListMarkerSettings(t *testing.T) {
+	t.Run("returns marker settings for a dataset", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			is.Equal(t, "/1/marker_settings/requests", r.URL.Path)
+			is.Equal(t, http.MethodGet, r.Method)
+
+			_ = json.NewEncoder(w).Encode([]honeycomb.MarkerSetting{{ID: "ms1", Type: "deploy", Color: "#F96E11"}})
+		}))
+		defer server.Close()
+
+		c := honeycomb.NewClient("test-key", honeycomb.WithBaseURL(server.URL))
+		settings, err := c.ListMarkerSettings(t.Context(), "requests")
+		is.NotError(t, err)
+		is.Equal(t, 1, len(settings))
+		is.Equal(t, "#F96E11", settings[0].Color)
+	})
+}
+
+func TestClient_CreateMarkerSetting(t *testing.T) {
+	t.Run("creates a marker setting", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			is.Equal(t, "/1/marker_settings/requests", r.URL.Path)
+			is.Equal(t, http.MethodPost, r.Method)
+
+			var setting honeycomb.MarkerSetting
+			_ = json.NewDecoder(r.Body).Decode(&setting)
+			is.Equal(t, "deploy", setting.Type)
+
+			setting.ID = "ms1"
+			w.WriteHeader(http.StatusCreated)
+			_ = json.NewEncoder(w).Encode(setting)
+		}))
+		defer server.Close()
+
+		c := honeycomb.NewClient("test-key", honeycomb.WithBaseURL(server.URL))
+		setting, err := c.CreateMarkerSetting(t.Context(), "requests", honeycomb.MarkerSetting{Type: "deploy", Color: "#F96E11"})
+		is.NotError(t, err)
+		is.Equal(t, "ms1", setting.ID)
+	})
+}
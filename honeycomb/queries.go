@@ -6,6 +6,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"sync"
 	"time"
 )
 
@@ -18,6 +19,7 @@ type QuerySpec struct {
 	TimeRange        int           `json:"time_range,omitempty"`
 	StartTime        int64         `json:"start_time,omitempty"`
 	EndTime          int64         `json:"end_time,omitempty"`
+	Granularity      int           `json:"granularity,omitempty"`
 	Orders           []Order       `json:"orders,omitempty"`
 	Limit            int           `json:"limit,omitempty"`
 }
@@ -28,6 +30,14 @@ type Calculation struct {
 	Column string `json:"column,omitempty"`
 }
 
+// Name of the calculation as used for its values in query results, such as "COUNT" or "P99(duration_ms)".
+func (c Calculation) Name() string {
+	if c.Column == "" {
+		return c.Op
+	}
+	return c.Op + "(" + c.Column + ")"
+}
+
 // Filter in a query.
 type Filter struct {
 	Column string `json:"column"`
@@ -53,15 +63,23 @@ type QueryResultRequest struct {
 	QueryID string `json:"query_id"`
 }
 
+// SeriesPoint is a time bucket in a query result's time series, one per breakdown group.
+// The bucket size is the query's granularity.
+type SeriesPoint struct {
+	Time time.Time      `json:"time"`
+	Data map[string]any `json:"data"`
+}
+
 // QueryResult from executing a query.
 type QueryResult struct {
 	ID       string `json:"id"`
 	Complete bool   `json:"complete"`
 	Data     struct {
 		Results []map[string]any `json:"results"`
-		Series  []any            `json:"series,omitempty"`
+		Series  []SeriesPoint    `json:"series,omitempty"`
 	} `json:"data"`
 	Links struct {
+		QueryURL string `json:"query_url,omitempty"`
 		GraphURL string `json:"graph_image_url,omitempty"`
 	} `json:"links,omitempty"`
 }
@@ -93,6 +111,28 @@ func (c *Client) CreateQuery(ctx context.Context, dataset string, spec QuerySpec
 	return &query, nil
 }
 
+// GetQuery by ID for a dataset.
+func (c *Client) GetQuery(ctx context.Context, dataset, id string) (*QuerySpec, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("%v/1/queries/%v/%v", c.baseURL, dataset, id), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := c.do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = res.Body.Close()
+	}()
+
+	var spec QuerySpec
+	if err := json.NewDecoder(res.Body).Decode(&spec); err != nil {
+		return nil, err
+	}
+	return &spec, nil
+}
+
 // CreateQueryResult executes a previously created query.
 func (c *Client) CreateQueryResult(ctx context.Context, dataset, queryID string) (*QueryResult, error) {
 	body, err := json.Marshal(QueryResultRequest{QueryID: queryID})
@@ -142,25 +182,145 @@ func (c *Client) GetQueryResult(ctx context.Context, dataset, resultID string) (
 	return &result, nil
 }
 
-// RunQuery creates a query, executes it, and polls until complete.
+// RunQueryOptions configure how [Client.RunQueryWithOptions] polls for query results.
+// Zero values use the defaults.
+type RunQueryOptions struct {
+	// InitialInterval before the first poll. Defaults to 250ms.
+	InitialInterval time.Duration
+	// Multiplier the interval grows by after each poll. Defaults to 1.5.
+	Multiplier float64
+	// MaxInterval between polls. Defaults to 5s.
+	MaxInterval time.Duration
+	// Timeout for the whole query, after which a [*QueryTimeoutError] is returned. Defaults to no timeout.
+	Timeout time.Duration
+	// Progress is called after the query is executed and after each incomplete poll.
+	Progress func(QueryProgress)
+}
+
+// QueryProgress of a running query, passed to [RunQueryOptions.Progress].
+type QueryProgress struct {
+	// QueryID is empty when waiting for the result of an unknown query.
+	QueryID  string
+	ResultID string
+	Polls    int
+	Elapsed  time.Duration
+}
+
+// QueryTimeoutError is returned when a query result isn't complete before the timeout.
+// The result can still be fetched later with [Client.GetQueryResult].
+type QueryTimeoutError struct {
+	Dataset  string
+	QueryID  string
+	ResultID string
+	Elapsed  time.Duration
+}
+
+func (e *QueryTimeoutError) Error() string {
+	return fmt.Sprintf("query result %v in dataset %v not complete after %v", e.ResultID, e.Dataset,
+		e.Elapsed.Round(time.Millisecond))
+}
+
+// WithRunQueryOptions sets the options used by [Client.RunQuery] and [Client.RunQueries].
+func WithRunQueryOptions(opts RunQueryOptions) Option {
+	return func(c *Client) {
+		c.runQueryOptions = opts
+	}
+}
+
+// RunQuery creates a query, executes it, and polls until complete, with the client's [RunQueryOptions].
 func (c *Client) RunQuery(ctx context.Context, dataset string, spec QuerySpec) (*QueryResult, error) {
+	return c.RunQueryWithOptions(ctx, dataset, spec, c.runQueryOptions)
+}
+
+// RunQueryWithOptions creates a query, executes it, and polls until complete, with exponentially growing intervals.
+// With a [QueryCache], a cached result is returned instead if there is one, and new results are cached.
+// Failing to cache a result doesn't fail the query.
+func (c *Client) RunQueryWithOptions(ctx context.Context, dataset string, spec QuerySpec, opts RunQueryOptions) (*QueryResult, error) {
+	if c.queryCache != nil {
+		if result, ok := c.queryCache.get(c.cacheScope(), dataset, spec); ok {
+			return result, nil
+		}
+	}
+
+	start := time.Now()
+
 	query, err := c.CreateQuery(ctx, dataset, spec)
 	if err != nil {
 		return nil, fmt.Errorf("creating query: %w", err)
 	}
 
-	result, err := c.CreateQueryResult(ctx, dataset, query.ID)
+	result, err := c.executeQuery(ctx, dataset, query.ID, opts, start)
+	if err != nil {
+		return nil, err
+	}
+
+	if c.queryCache != nil {
+		_ = c.queryCache.put(c.cacheScope(), dataset, spec, result)
+	}
+	return result, nil
+}
+
+// ExecuteQuery executes a previously created query and polls until complete, with the given options.
+func (c *Client) ExecuteQuery(ctx context.Context, dataset, queryID string, opts RunQueryOptions) (*QueryResult, error) {
+	return c.executeQuery(ctx, dataset, queryID, opts, time.Now())
+}
+
+func (c *Client) executeQuery(ctx context.Context, dataset, queryID string, opts RunQueryOptions, start time.Time) (*QueryResult, error) {
+	result, err := c.CreateQueryResult(ctx, dataset, queryID)
 	if err != nil {
 		return nil, fmt.Errorf("executing query: %w", err)
 	}
 
-	for !result.Complete {
+	return c.pollQueryResult(ctx, dataset, queryID, result, opts, start)
+}
+
+// WaitForQueryResult polls a previously executed query's result until complete, with the given options.
+func (c *Client) WaitForQueryResult(ctx context.Context, dataset, resultID string, opts RunQueryOptions) (*QueryResult, error) {
+	start := time.Now()
+
+	result, err := c.GetQueryResult(ctx, dataset, resultID)
+	if err != nil {
+		return nil, fmt.Errorf("getting query result: %w", err)
+	}
+
+	return c.pollQueryResult(ctx, dataset, "", result, opts, start)
+}
+
+func (c *Client) pollQueryResult(ctx context.Context, dataset, queryID string, result *QueryResult, opts RunQueryOptions,
+	start time.Time) (*QueryResult, error) {
+	if opts.InitialInterval <= 0 {
+		opts.InitialInterval = 250 * time.Millisecond
+	}
+	if opts.Multiplier < 1 {
+		opts.Multiplier = 1.5
+	}
+	if opts.MaxInterval <= 0 {
+		opts.MaxInterval = 5 * time.Second
+	}
+
+	interval := opts.InitialInterval
+	for polls := 0; !result.Complete; polls++ {
+		elapsed := time.Since(start)
+		if opts.Progress != nil {
+			opts.Progress(QueryProgress{QueryID: queryID, ResultID: result.ID, Polls: polls, Elapsed: elapsed})
+		}
+
+		wait := interval
+		if opts.Timeout > 0 {
+			if elapsed >= opts.Timeout {
+				return nil, &QueryTimeoutError{Dataset: dataset, QueryID: queryID, ResultID: result.ID, Elapsed: elapsed}
+			}
+			wait = min(wait, opts.Timeout-elapsed)
+		}
+
 		select {
 		case <-ctx.Done():
 			return nil, ctx.Err()
-		case <-time.After(time.Second):
+		case <-time.After(wait):
 		}
+		interval = min(time.Duration(float64(interval)*opts.Multiplier), opts.MaxInterval)
 
+		var err error
 		result, err = c.GetQueryResult(ctx, dataset, result.ID)
 		if err != nil {
 			return nil, fmt.Errorf("polling query result: %w", err)
@@ -169,3 +329,37 @@ func (c *Client) RunQuery(ctx context.Context, dataset string, spec QuerySpec) (
 
 	return result, nil
 }
+
+// DatasetQueryResult is the result of running a query in one of several datasets.
+// Either Result or Err is set.
+type DatasetQueryResult struct {
+	Dataset string
+	Result  *QueryResult
+	Err     error
+}
+
+// RunQueries runs the same query in each dataset, at most concurrency at a time.
+// Results are in the same order as the datasets. A failure in one dataset doesn't stop the others.
+func (c *Client) RunQueries(ctx context.Context, datasets []string, spec QuerySpec, concurrency int) []DatasetQueryResult {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	results := make([]DatasetQueryResult, len(datasets))
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	for i, dataset := range datasets {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			result, err := c.RunQuery(ctx, dataset, spec)
+			results[i] = DatasetQueryResult{Dataset: dataset, Result: result, Err: err}
+		}()
+	}
+	wg.Wait()
+
+	return results
+}
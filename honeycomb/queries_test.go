@@ -2,10 +2,14 @@ package honeycomb_test
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
 	"net/http/httptest"
+	"strings"
+	"sync"
 	"sync/atomic"
 	"testing"
+	"time"
 
 	"maragu.dev/is"
 
@@ -39,6 +43,27 @@ func TestClient_CreateQuery(t *testing.T) {
 	})
 }
 
+func TestClient_GetQuery(t *testing.T) {
+	t.Run("returns a query definition by ID", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			is.Equal(t, "/1/queries/requests/q1", r.URL.Path)
+			is.Equal(t, http.MethodGet, r.Method)
+
+			_ = json.NewEncoder(w).Encode(honeycomb.QuerySpec{
+				Calculations: []honeycomb.Calculation{{Op: "P99", Column: "duration_ms"}},
+				Breakdowns:   []string{"status_code"},
+			})
+		}))
+		defer server.Close()
+
+		c := honeycomb.NewClient("test-key", honeycomb.WithBaseURL(server.URL))
+		spec, err := c.GetQuery(t.Context(), "requests", "q1")
+		is.NotError(t, err)
+		is.Equal(t, "P99", spec.Calculations[0].Op)
+		is.Equal(t, "status_code", spec.Breakdowns[0])
+	})
+}
+
 func TestClient_RunQuery(t *testing.T) {
 	t.Run("creates, executes, and polls for query results", func(t *testing.T) {
 		var pollCount atomic.Int32
@@ -87,3 +112,176 @@ func TestClient_RunQuery(t *testing.T) {
 		is.Equal(t, float64(42), result.Data.Results[0]["COUNT"].(float64))
 	})
 }
+
+func TestClient_RunQueryWithOptions(t *testing.T) {
+	// newPollServer returns a result that's complete after the given number of polls, or never if it's zero.
+	newPollServer := func(t *testing.T, completeAfter int32) (*httptest.Server, *[]time.Time) {
+		t.Helper()
+		var polls atomic.Int32
+		var mu sync.Mutex
+		var pollTimes []time.Time
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch {
+			case r.Method == http.MethodPost && r.URL.Path == "/1/queries/requests":
+				_ = json.NewEncoder(w).Encode(honeycomb.QueryResponse{ID: "q1"})
+
+			case r.Method == http.MethodPost && r.URL.Path == "/1/query_results/requests":
+				_ = json.NewEncoder(w).Encode(honeycomb.QueryResult{ID: "r1"})
+
+			case r.Method == http.MethodGet && r.URL.Path == "/1/query_results/requests/r1":
+				mu.Lock()
+				pollTimes = append(pollTimes, time.Now())
+				mu.Unlock()
+				count := polls.Add(1)
+				_ = json.NewEncoder(w).Encode(honeycomb.QueryResult{ID: "r1", Complete: completeAfter > 0 && count >= completeAfter})
+			}
+		}))
+		t.Cleanup(server.Close)
+		return server, &pollTimes
+	}
+
+	t.Run("polls with growing intervals and reports progress", func(t *testing.T) {
+		server, pollTimes := newPollServer(t, 4)
+
+		var progress []honeycomb.QueryProgress
+		c := honeycomb.NewClient("test-key", honeycomb.WithBaseURL(server.URL))
+		result, err := c.RunQueryWithOptions(t.Context(), "requests", honeycomb.QuerySpec{}, honeycomb.RunQueryOptions{
+			InitialInterval: 10 * time.Millisecond,
+			Multiplier:      3,
+			MaxInterval:     time.Second,
+			Progress:        func(p honeycomb.QueryProgress) { progress = append(progress, p) },
+		})
+		is.NotError(t, err)
+		is.True(t, result.Complete)
+
+		is.Equal(t, 4, len(progress))
+		is.Equal(t, "q1", progress[0].QueryID)
+		is.Equal(t, "r1", progress[0].ResultID)
+		is.Equal(t, 3, progress[3].Polls)
+		is.True(t, progress[3].Elapsed > progress[0].Elapsed)
+
+		// Intervals are 10ms, 30ms, and 90ms
+		is.Equal(t, 4, len(*pollTimes))
+		is.True(t, (*pollTimes)[3].Sub((*pollTimes)[2]) >= 90*time.Millisecond)
+	})
+
+	t.Run("returns a timeout error with the result ID", func(t *testing.T) {
+		server, _ := newPollServer(t, 0)
+
+		c := honeycomb.NewClient("test-key", honeycomb.WithBaseURL(server.URL))
+		_, err := c.RunQueryWithOptions(t.Context(), "requests", honeycomb.QuerySpec{}, honeycomb.RunQueryOptions{
+			InitialInterval: 10 * time.Millisecond,
+			Timeout:         50 * time.Millisecond,
+		})
+
+		var timeoutErr *honeycomb.QueryTimeoutError
+		is.True(t, errors.As(err, &timeoutErr))
+		is.Equal(t, "q1", timeoutErr.QueryID)
+		is.Equal(t, "r1", timeoutErr.ResultID)
+		is.Equal(t, "requests", timeoutErr.Dataset)
+		is.True(t, timeoutErr.Elapsed >= 50*time.Millisecond)
+		is.True(t, strings.Contains(err.Error(), "query result r1 in dataset requests not complete"))
+	})
+
+	t.Run("executes a previously created query", func(t *testing.T) {
+		server, _ := newPollServer(t, 2)
+
+		c := honeycomb.NewClient("test-key", honeycomb.WithBaseURL(server.URL))
+		result, err := c.ExecuteQuery(t.Context(), "requests", "q1", honeycomb.RunQueryOptions{InitialInterval: time.Millisecond})
+		is.NotError(t, err)
+		is.True(t, result.Complete)
+	})
+
+	t.Run("waits for the result of a previously executed query", func(t *testing.T) {
+		server, pollTimes := newPollServer(t, 3)
+
+		c := honeycomb.NewClient("test-key", honeycomb.WithBaseURL(server.URL))
+		result, err := c.WaitForQueryResult(t.Context(), "requests", "r1", honeycomb.RunQueryOptions{InitialInterval: time.Millisecond})
+		is.NotError(t, err)
+		is.True(t, result.Complete)
+		is.Equal(t, 3, len(*pollTimes))
+	})
+
+	t.Run("uses the client's options in RunQuery", func(t *testing.T) {
+		server, _ := newPollServer(t, 0)
+
+		c := honeycomb.NewClient("test-key", honeycomb.WithBaseURL(server.URL),
+			honeycomb.WithRunQueryOptions(honeycomb.RunQueryOptions{InitialInterval: time.Millisecond, Timeout: 20 * time.Millisecond}))
+		_, err := c.RunQuery(t.Context(), "requests", honeycomb.QuerySpec{})
+
+		var timeoutErr *honeycomb.QueryTimeoutError
+		is.True(t, errors.As(err, &timeoutErr))
+	})
+}
+
+func TestClient_RunQueries(t *testing.T) {
+	t.Run("runs the query in each dataset with bounded concurrency and keeps going on errors", func(t *testing.T) {
+		var running, maxRunning atomic.Int32
+
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			dataset := r.URL.Path[strings.LastIndex(r.URL.Path, "/")+1:]
+			switch {
+			case dataset == "broken":
+				w.WriteHeader(http.StatusInternalServerError)
+				_, _ = w.Write([]byte(`{"error": "boom"}`))
+
+			case strings.HasPrefix(r.URL.Path, "/1/queries/"):
+				n := running.Add(1)
+				defer running.Add(-1)
+				for {
+					m := maxRunning.Load()
+					if n <= m || maxRunning.CompareAndSwap(m, n) {
+						break
+					}
+				}
+				time.Sleep(20 * time.Millisecond)
+				_ = json.NewEncoder(w).Encode(honeycomb.QueryResponse{ID: "q1"})
+
+			case strings.HasPrefix(r.URL.Path, "/1/query_results/"):
+				result := honeycomb.QueryResult{ID: "r1", Complete: true}
+				result.Data.Results = []map[string]any{{"COUNT": float64(len(dataset))}}
+				_ = json.NewEncoder(w).Encode(result)
+			}
+		}))
+		defer server.Close()
+
+		c := honeycomb.NewClient("test-key", honeycomb.WithBaseURL(server.URL))
+		datasets := []string{"a", "bb", "broken", "cccc", "ddddd"}
+		results := c.RunQueries(t.Context(), datasets, honeycomb.QuerySpec{Calculations: []honeycomb.Calculation{{Op: "COUNT"}}}, 2)
+
+		is.Equal(t, 5, len(results))
+		is.True(t, maxRunning.Load() <= 2)
+		for i, r := range results {
+			is.Equal(t, datasets[i], r.Dataset)
+			if r.Dataset == "broken" {
+				is.True(t, r.Err != nil)
+				continue
+			}
+			is.NotError(t, r.Err)
+			is.Equal(t, float64(len(r.Dataset)), r.Result.Data.Results[0]["COUNT"].(float64))
+		}
+	})
+}
+
+func TestCalculation_Name(t *testing.T) {
+	t.Run("is the op without a column", func(t *testing.T) {
+		is.Equal(t, "COUNT", honeycomb.Calculation{Op: "COUNT"}.Name())
+	})
+
+	t.Run("includes the column", func(t *testing.T) {
+		is.Equal(t, "P99(duration_ms)", honeycomb.Calculation{Op: "P99", Column: "duration_ms"}.Name())
+	})
+}
+
+func TestQueryResult(t *testing.T) {
+	t.Run("decodes time series", func(t *testing.T) {
+		var result honeycomb.QueryResult
+		err := json.Unmarshal([]byte(`{"id": "r1", "complete": true, "data": {"series": [
+			{"time": "2026-10-01T00:15:00Z", "data": {"COUNT": 12, "service": "api"}}
+		]}}`), &result)
+		is.NotError(t, err)
+		is.Equal(t, 1, len(result.Data.Series))
+		is.Equal(t, 15, result.Data.Series[0].Time.Minute())
+		is.Equal(t, 12.0, result.Data.Series[0].Data["COUNT"])
+	})
+}
@@ -0,0 +1,124 @@
+package honeycomb
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+)
+
+// QueryCache stores completed query results on disk, so that running an identical query again within the TTL
+// doesn't hit the API. Entries are keyed by the API URL and key, the dataset, and the canonical query spec,
+// see [CanonicalQuerySpec].
+type QueryCache struct {
+	dir string
+	ttl time.Duration
+}
+
+// NewQueryCache storing results in dir for the given TTL. The directory is created when needed.
+func NewQueryCache(dir string, ttl time.Duration) *QueryCache {
+	return &QueryCache{dir: dir, ttl: ttl}
+}
+
+// WithQueryCache makes [Client.RunQuery], [Client.RunQueryWithOptions], and [Client.RunQueries] use the cache.
+// A nil cache turns caching off.
+func WithQueryCache(cache *QueryCache) Option {
+	return func(c *Client) {
+		c.queryCache = cache
+	}
+}
+
+// Clear removes all cached results.
+func (q *QueryCache) Clear() error {
+	return os.RemoveAll(q.dir)
+}
+
+// queryCacheEntry is the file format of a cached result.
+type queryCacheEntry struct {
+	Created time.Time   `json:"created"`
+	Dataset string      `json:"dataset"`
+	Spec    QuerySpec   `json:"spec"`
+	Result  QueryResult `json:"result"`
+}
+
+// get the cached result for the query, if there is one that hasn't expired. Expired entries are removed.
+func (q *QueryCache) get(scope, dataset string, spec QuerySpec) (*QueryResult, bool) {
+	path := q.path(scope, dataset, spec)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, false
+	}
+
+	var entry queryCacheEntry
+	if err := json.Unmarshal(data, &entry); err != nil || time.Since(entry.Created) > q.ttl {
+		_ = os.Remove(path)
+		return nil, false
+	}
+	return &entry.Result, true
+}
+
+// put the result for the query in the cache, replacing the file atomically so concurrent readers never see
+// a partial entry.
+func (q *QueryCache) put(scope, dataset string, spec QuerySpec, result *QueryResult) error {
+	data, err := json.Marshal(queryCacheEntry{Created: time.Now(), Dataset: dataset, Spec: spec, Result: *result})
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(q.dir, 0o700); err != nil {
+		return err
+	}
+	f, err := os.CreateTemp(q.dir, "*.tmp")
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(data); err != nil {
+		_ = f.Close()
+		_ = os.Remove(f.Name())
+		return err
+	}
+	if err := f.Close(); err != nil {
+		_ = os.Remove(f.Name())
+		return err
+	}
+	return os.Rename(f.Name(), q.path(scope, dataset, spec))
+}
+
+func (q *QueryCache) path(scope, dataset string, spec QuerySpec) string {
+	key, _ := json.Marshal(struct {
+		Scope   string    `json:"scope"`
+		Dataset string    `json:"dataset"`
+		Spec    QuerySpec `json:"spec"`
+	}{scope, dataset, CanonicalQuerySpec(spec)})
+	sum := sha256.Sum256(key)
+	return filepath.Join(q.dir, hex.EncodeToString(sum[:])+".json")
+}
+
+// CanonicalQuerySpec returns the spec with equivalent queries made identical: absolute start and end times are
+// rounded down to the granularity, or to the minute without one, and filters are sorted.
+func CanonicalQuerySpec(spec QuerySpec) QuerySpec {
+	granularity := int64(spec.Granularity)
+	if granularity <= 0 {
+		granularity = 60
+	}
+	spec.StartTime -= spec.StartTime % granularity
+	spec.EndTime -= spec.EndTime % granularity
+
+	spec.Filters = slices.Clone(spec.Filters)
+	slices.SortStableFunc(spec.Filters, func(a, b Filter) int {
+		aJSON, _ := json.Marshal(a)
+		bJSON, _ := json.Marshal(b)
+		return strings.Compare(string(aJSON), string(bJSON))
+	})
+	return spec
+}
+
+// cacheScope separates cached results per API and API key, without storing the key itself.
+func (c *Client) cacheScope() string {
+	sum := sha256.Sum256([]byte(c.baseURL + "\x00" + c.apiKey))
+	return hex.EncodeToString(sum[:])
+}
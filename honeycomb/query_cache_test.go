@@ -0,0 +1,188 @@
+package honeycomb_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"maragu.dev/is"
+
+	"github.com/maragudk/honeycomb-cli/honeycomb"
+)
+
+func TestClient_RunQuery_QueryCache(t *testing.T) {
+	spec := honeycomb.QuerySpec{Calculations: []honeycomb.Calculation{{Op: "COUNT"}}, TimeRange: 7200}
+
+	t.Run("returns cached results for identical queries", func(t *testing.T) {
+		var created atomic.Int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch {
+			case r.Method == http.MethodPost && r.URL.Path == "/1/queries/requests":
+				created.Add(1)
+				_ = json.NewEncoder(w).Encode(honeycomb.QueryResponse{ID: "q1"})
+			case r.Method == http.MethodPost && r.URL.Path == "/1/query_results/requests":
+				result := honeycomb.QueryResult{ID: "r1", Complete: true}
+				result.Data.Results = []map[string]any{{"COUNT": float64(created.Load())}}
+				_ = json.NewEncoder(w).Encode(result)
+			default:
+				http.NotFound(w, r)
+			}
+		}))
+		defer server.Close()
+
+		cache := honeycomb.NewQueryCache(t.TempDir(), time.Minute)
+		c := honeycomb.NewClient("test-key", honeycomb.WithBaseURL(server.URL), honeycomb.WithQueryCache(cache))
+
+		first, err := c.RunQuery(t.Context(), "requests", spec)
+		is.NotError(t, err)
+		second, err := c.RunQuery(t.Context(), "requests", spec)
+		is.NotError(t, err)
+
+		is.Equal(t, int32(1), created.Load())
+		is.Equal(t, "r1", second.ID)
+		is.Equal(t, first.Data.Results[0]["COUNT"], second.Data.Results[0]["COUNT"])
+	})
+
+	t.Run("runs the query again after the TTL", func(t *testing.T) {
+		var created atomic.Int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch {
+			case r.Method == http.MethodPost && r.URL.Path == "/1/queries/requests":
+				created.Add(1)
+				_ = json.NewEncoder(w).Encode(honeycomb.QueryResponse{ID: "q1"})
+			case r.Method == http.MethodPost && r.URL.Path == "/1/query_results/requests":
+				result := honeycomb.QueryResult{ID: "r1", Complete: true}
+				result.Data.Results = []map[string]any{{"COUNT": float64(created.Load())}}
+				_ = json.NewEncoder(w).Encode(result)
+			default:
+				http.NotFound(w, r)
+			}
+		}))
+		defer server.Close()
+
+		cache := honeycomb.NewQueryCache(t.TempDir(), 0)
+		c := honeycomb.NewClient("test-key", honeycomb.WithBaseURL(server.URL), honeycomb.WithQueryCache(cache))
+
+		_, err := c.RunQuery(t.Context(), "requests", spec)
+		is.NotError(t, err)
+		_, err = c.RunQuery(t.Context(), "requests", spec)
+		is.NotError(t, err)
+
+		is.Equal(t, int32(2), created.Load())
+	})
+
+	t.Run("doesn't share results between API keys", func(t *testing.T) {
+		var created atomic.Int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch {
+			case r.Method == http.MethodPost && r.URL.Path == "/1/queries/requests":
+				created.Add(1)
+				_ = json.NewEncoder(w).Encode(honeycomb.QueryResponse{ID: "q1"})
+			case r.Method == http.MethodPost && r.URL.Path == "/1/query_results/requests":
+				result := honeycomb.QueryResult{ID: "r1", Complete: true}
+				result.Data.Results = []map[string]any{{"COUNT": float64(created.Load())}}
+				_ = json.NewEncoder(w).Encode(result)
+			default:
+				http.NotFound(w, r)
+			}
+		}))
+		defer server.Close()
+
+		cache := honeycomb.NewQueryCache(t.TempDir(), time.Minute)
+
+		for _, key := range []string{"key-1", "key-2"} {
+			c := honeycomb.NewClient(key, honeycomb.WithBaseURL(server.URL), honeycomb.WithQueryCache(cache))
+			_, err := c.RunQuery(t.Context(), "requests", spec)
+			is.NotError(t, err)
+		}
+
+		is.Equal(t, int32(2), created.Load())
+	})
+
+	t.Run("shares results for absolute time ranges within the same granularity", func(t *testing.T) {
+		var created atomic.Int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch {
+			case r.Method == http.MethodPost && r.URL.Path == "/1/queries/requests":
+				created.Add(1)
+				_ = json.NewEncoder(w).Encode(honeycomb.QueryResponse{ID: "q1"})
+			case r.Method == http.MethodPost && r.URL.Path == "/1/query_results/requests":
+				result := honeycomb.QueryResult{ID: "r1", Complete: true}
+				result.Data.Results = []map[string]any{{"COUNT": float64(created.Load())}}
+				_ = json.NewEncoder(w).Encode(result)
+			default:
+				http.NotFound(w, r)
+			}
+		}))
+		defer server.Close()
+
+		cache := honeycomb.NewQueryCache(t.TempDir(), time.Minute)
+		c := honeycomb.NewClient("test-key", honeycomb.WithBaseURL(server.URL), honeycomb.WithQueryCache(cache))
+
+		for _, offset := range []int64{0, 30, 59} {
+			_, err := c.RunQuery(t.Context(), "requests", honeycomb.QuerySpec{StartTime: 1_700_000_040 + offset, EndTime: 1_700_003_640 + offset})
+			is.NotError(t, err)
+		}
+		is.Equal(t, int32(1), created.Load())
+
+		_, err := c.RunQuery(t.Context(), "requests", honeycomb.QuerySpec{StartTime: 1_700_000_100, EndTime: 1_700_003_700})
+		is.NotError(t, err)
+		is.Equal(t, int32(2), created.Load())
+	})
+
+	t.Run("runs queries again after the cache is cleared", func(t *testing.T) {
+		var created atomic.Int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch {
+			case r.Method == http.MethodPost && r.URL.Path == "/1/queries/requests":
+				created.Add(1)
+				_ = json.NewEncoder(w).Encode(honeycomb.QueryResponse{ID: "q1"})
+			case r.Method == http.MethodPost && r.URL.Path == "/1/query_results/requests":
+				result := honeycomb.QueryResult{ID: "r1", Complete: true}
+				result.Data.Results = []map[string]any{{"COUNT": float64(created.Load())}}
+				_ = json.NewEncoder(w).Encode(result)
+			default:
+				http.NotFound(w, r)
+			}
+		}))
+		defer server.Close()
+
+		cache := honeycomb.NewQueryCache(t.TempDir(), time.Minute)
+		c := honeycomb.NewClient("test-key", honeycomb.WithBaseURL(server.URL), honeycomb.WithQueryCache(cache))
+
+		_, err := c.RunQuery(t.Context(), "requests", spec)
+		is.NotError(t, err)
+		is.NotError(t, cache.Clear())
+		_, err = c.RunQuery(t.Context(), "requests", spec)
+		is.NotError(t, err)
+
+		is.Equal(t, int32(2), created.Load())
+	})
+}
+
+func TestCanonicalQuerySpec(t *testing.T) {
+	t.Run("rounds absolute times down to the granularity", func(t *testing.T) {
+		spec := honeycomb.CanonicalQuerySpec(honeycomb.QuerySpec{StartTime: 1_700_000_299, EndTime: 1_700_003_899, Granularity: 300})
+		is.Equal(t, int64(1_700_000_100), spec.StartTime)
+		is.Equal(t, int64(1_700_003_700), spec.EndTime)
+	})
+
+	t.Run("rounds absolute times down to the minute without a granularity", func(t *testing.T) {
+		spec := honeycomb.CanonicalQuerySpec(honeycomb.QuerySpec{StartTime: 1_700_000_059})
+		is.Equal(t, int64(1_700_000_040), spec.StartTime)
+		is.Equal(t, int64(0), spec.EndTime)
+	})
+
+	t.Run("sorts filters without changing the original spec", func(t *testing.T) {
+		original := honeycomb.QuerySpec{Filters: []honeycomb.Filter{
+			{Column: "service", Op: "=", Value: "api"},
+			{Column: "duration_ms", Op: ">", Value: 100},
+		}}
+		spec := honeycomb.CanonicalQuerySpec(original)
+		is.Equal(t, "duration_ms", spec.Filters[0].Column)
+		is.Equal(t, "service", original.Filters[0].Column)
+	})
+}
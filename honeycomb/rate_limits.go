@@ -0,0 +1,186 @@
+package honeycomb
+
+import (
+	"context"
+	"math"
+	"net/http"
+	"slices"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+// EndpointClass groups API endpoints that share a [RateLimit].
+type EndpointClass string
+
+const (
+	// EndpointRead is every GET request, including polling for query results.
+	EndpointRead EndpointClass = "read"
+	// EndpointWrite is every other request, except for running queries.
+	EndpointWrite EndpointClass = "write"
+	// EndpointQuery is creating queries and query results, which the API limits the most.
+	EndpointQuery EndpointClass = "query"
+)
+
+// endpointClass of a request.
+func endpointClass(req *http.Request) EndpointClass {
+	switch {
+	case req.Method == http.MethodGet:
+		return EndpointRead
+	case req.Method == http.MethodPost &&
+		(strings.HasPrefix(req.URL.Path, "/1/queries/") || strings.HasPrefix(req.URL.Path, "/1/query_results/")):
+		return EndpointQuery
+	default:
+		return EndpointWrite
+	}
+}
+
+// RateLimit for a class of endpoints. Zero values mean no limit.
+type RateLimit struct {
+	// RequestsPerSecond on average.
+	RequestsPerSecond float64
+	// Burst of requests allowed at once. Defaults to RequestsPerSecond rounded up, and at least 1.
+	Burst int
+	// MaxInFlight requests at the same time. A request is in flight until its response body is closed.
+	MaxInFlight int
+}
+
+// RateLimits per endpoint class. Classes without a limit of their own use the Default limit.
+type RateLimits struct {
+	Default RateLimit
+	Classes map[EndpointClass]RateLimit
+}
+
+// RateLimiter holds requests to the API back to stay within [RateLimits].
+// It can be shared between clients, so that they stay within the limits together.
+type RateLimiter struct {
+	classes map[EndpointClass]*classLimiter
+}
+
+// NewRateLimiter with the given limits.
+func NewRateLimiter(limits RateLimits) *RateLimiter {
+	l := &RateLimiter{classes: map[EndpointClass]*classLimiter{}}
+	for _, class := range []EndpointClass{EndpointRead, EndpointWrite, EndpointQuery} {
+		limit, ok := limits.Classes[class]
+		if !ok {
+			limit = limits.Default
+		}
+		l.classes[class] = newClassLimiter(limit)
+	}
+	return l
+}
+
+// WithRateLimiter makes every request from the client wait for the rate limiter.
+func WithRateLimiter(l *RateLimiter) Option {
+	return func(c *Client) {
+		c.rateLimiter = l
+	}
+}
+
+// RateLimitState of an endpoint class, for metrics.
+type RateLimitState struct {
+	Class EndpointClass
+	Limit RateLimit
+	// Tokens available for requests right now, or zero without a RequestsPerSecond limit.
+	// Negative when requests have reserved tokens ahead of time.
+	Tokens float64
+	// InFlight requests right now.
+	InFlight int
+	// Waiting requests right now.
+	Waiting int
+	// Requests made so far.
+	Requests int64
+	// Waited in total by all requests so far.
+	Waited time.Duration
+}
+
+// State of each endpoint class, sorted by class.
+func (l *RateLimiter) State() []RateLimitState {
+	var states []RateLimitState
+	for class, cl := range l.classes {
+		s := RateLimitState{
+			Class:    class,
+			Limit:    cl.limit,
+			InFlight: int(cl.inFlight.Load()),
+			Waiting:  int(cl.waiting.Load()),
+			Requests: cl.requests.Load(),
+			Waited:   time.Duration(cl.waited.Load()),
+		}
+		if cl.tokens != nil {
+			s.Tokens = cl.tokens.Tokens()
+		}
+		states = append(states, s)
+	}
+	slices.SortFunc(states, func(a, b RateLimitState) int {
+		return strings.Compare(string(a.Class), string(b.Class))
+	})
+	return states
+}
+
+// wait until the request is allowed, and return a function to call once when it's done.
+func (l *RateLimiter) wait(ctx context.Context, req *http.Request) (func(), error) {
+	return l.classes[endpointClass(req)].wait(ctx)
+}
+
+// classLimiter combines a token bucket and a semaphore for an endpoint class.
+type classLimiter struct {
+	limit    RateLimit
+	tokens   *rate.Limiter
+	slots    chan struct{}
+	inFlight atomic.Int64
+	waiting  atomic.Int64
+	requests atomic.Int64
+	waited   atomic.Int64
+}
+
+func newClassLimiter(limit RateLimit) *classLimiter {
+	cl := &classLimiter{limit: limit}
+	if limit.RequestsPerSecond > 0 {
+		burst := limit.Burst
+		if burst <= 0 {
+			burst = max(1, int(math.Ceil(limit.RequestsPerSecond)))
+		}
+		cl.tokens = rate.NewLimiter(rate.Limit(limit.RequestsPerSecond), burst)
+	}
+	if limit.MaxInFlight > 0 {
+		cl.slots = make(chan struct{}, limit.MaxInFlight)
+	}
+	return cl
+}
+
+func (cl *classLimiter) wait(ctx context.Context) (func(), error) {
+	start := time.Now()
+	cl.waiting.Add(1)
+	defer func() {
+		cl.waiting.Add(-1)
+		cl.waited.Add(int64(time.Since(start)))
+	}()
+
+	// Wait for a slot before a token, so tokens aren't used up by requests that can't be sent yet
+	if cl.slots != nil {
+		select {
+		case cl.slots <- struct{}{}:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+	if cl.tokens != nil {
+		if err := cl.tokens.Wait(ctx); err != nil {
+			if cl.slots != nil {
+				<-cl.slots
+			}
+			return nil, err
+		}
+	}
+
+	cl.requests.Add(1)
+	cl.inFlight.Add(1)
+	return func() {
+		cl.inFlight.Add(-1)
+		if cl.slots != nil {
+			<-cl.slots
+		}
+	}, nil
+}
@@ -0,0 +1,159 @@
+package honeycomb_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"maragu.dev/is"
+
+	"github.com/maragudk/honeycomb-cli/honeycomb"
+)
+
+func TestClient_RateLimiter(t *testing.T) {
+	t.Run("limits requests in flight", func(t *testing.T) {
+		var inFlight, maxInFlight atomic.Int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			n := inFlight.Add(1)
+			defer inFlight.Add(-1)
+			for {
+				m := maxInFlight.Load()
+				if n <= m || maxInFlight.CompareAndSwap(m, n) {
+					break
+				}
+			}
+			time.Sleep(20 * time.Millisecond)
+			_ = json.NewEncoder(w).Encode([]honeycomb.Dataset{})
+		}))
+		defer server.Close()
+
+		l := honeycomb.NewRateLimiter(honeycomb.RateLimits{Default: honeycomb.RateLimit{MaxInFlight: 2}})
+		c := honeycomb.NewClient("test-key", honeycomb.WithBaseURL(server.URL), honeycomb.WithRateLimiter(l))
+
+		var wg sync.WaitGroup
+		for range 6 {
+			wg.Go(func() {
+				_, err := c.ListDatasets(t.Context())
+				is.NotError(t, err)
+			})
+		}
+		wg.Wait()
+
+		is.Equal(t, int32(2), maxInFlight.Load())
+		state := l.State()
+		is.Equal(t, honeycomb.EndpointRead, state[1].Class)
+		is.Equal(t, int64(6), state[1].Requests)
+		is.Equal(t, 0, state[1].InFlight)
+		is.Equal(t, 0, state[1].Waiting)
+		is.True(t, state[1].Waited > 0)
+	})
+
+	t.Run("limits requests per second", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_ = json.NewEncoder(w).Encode([]honeycomb.Dataset{})
+		}))
+		defer server.Close()
+
+		l := honeycomb.NewRateLimiter(honeycomb.RateLimits{Default: honeycomb.RateLimit{RequestsPerSecond: 20, Burst: 1}})
+		c := honeycomb.NewClient("test-key", honeycomb.WithBaseURL(server.URL), honeycomb.WithRateLimiter(l))
+
+		start := time.Now()
+		for range 5 {
+			_, err := c.ListDatasets(t.Context())
+			is.NotError(t, err)
+		}
+		is.True(t, time.Since(start) >= 150*time.Millisecond)
+	})
+
+	t.Run("limits endpoint classes separately", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch {
+			case r.Method == http.MethodPost && r.URL.Path == "/1/queries/requests":
+				_ = json.NewEncoder(w).Encode(honeycomb.QueryResponse{ID: "q1"})
+			case r.Method == http.MethodPost && r.URL.Path == "/1/query_results/requests":
+				_ = json.NewEncoder(w).Encode(honeycomb.QueryResult{ID: "r1"})
+			case r.Method == http.MethodGet && r.URL.Path == "/1/query_results/requests/r1":
+				_ = json.NewEncoder(w).Encode(honeycomb.QueryResult{ID: "r1", Complete: true})
+			default:
+				http.NotFound(w, r)
+			}
+		}))
+		defer server.Close()
+
+		l := honeycomb.NewRateLimiter(honeycomb.RateLimits{
+			Default: honeycomb.RateLimit{RequestsPerSecond: 100},
+			Classes: map[honeycomb.EndpointClass]honeycomb.RateLimit{
+				honeycomb.EndpointQuery: {RequestsPerSecond: 1, Burst: 2, MaxInFlight: 1},
+			},
+		})
+		c := honeycomb.NewClient("test-key", honeycomb.WithBaseURL(server.URL), honeycomb.WithRateLimiter(l),
+			honeycomb.WithRunQueryOptions(honeycomb.RunQueryOptions{InitialInterval: time.Millisecond}))
+
+		_, err := c.RunQuery(t.Context(), "requests", honeycomb.QuerySpec{})
+		is.NotError(t, err)
+
+		state := l.State()
+		is.Equal(t, 3, len(state))
+
+		is.Equal(t, honeycomb.EndpointQuery, state[0].Class)
+		is.Equal(t, 1.0, state[0].Limit.RequestsPerSecond)
+		is.Equal(t, int64(2), state[0].Requests)
+		is.True(t, state[0].Tokens < 1)
+
+		is.Equal(t, honeycomb.EndpointRead, state[1].Class)
+		is.Equal(t, 100.0, state[1].Limit.RequestsPerSecond)
+		is.Equal(t, int64(1), state[1].Requests)
+
+		is.Equal(t, honeycomb.EndpointWrite, state[2].Class)
+		is.Equal(t, int64(0), state[2].Requests)
+	})
+
+	t.Run("frees the slot of a failed request", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			http.Error(w, `{"error": "not found"}`, http.StatusNotFound)
+		}))
+		defer server.Close()
+
+		l := honeycomb.NewRateLimiter(honeycomb.RateLimits{Default: honeycomb.RateLimit{MaxInFlight: 1}})
+		c := honeycomb.NewClient("test-key", honeycomb.WithBaseURL(server.URL), honeycomb.WithRateLimiter(l))
+
+		ctx, cancel := context.WithTimeout(t.Context(), time.Second)
+		defer cancel()
+		for range 2 {
+			_, err := c.ListDatasets(ctx)
+			var apiErr *honeycomb.APIError
+			is.True(t, errors.As(err, &apiErr))
+		}
+	})
+
+	t.Run("stops waiting when the context is done", func(t *testing.T) {
+		release := make(chan struct{})
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			<-release
+			_ = json.NewEncoder(w).Encode([]honeycomb.Dataset{})
+		}))
+		defer server.Close()
+		defer close(release)
+
+		l := honeycomb.NewRateLimiter(honeycomb.RateLimits{Default: honeycomb.RateLimit{MaxInFlight: 1}})
+		c := honeycomb.NewClient("test-key", honeycomb.WithBaseURL(server.URL), honeycomb.WithRateLimiter(l))
+
+		go func() {
+			_, _ = c.ListDatasets(t.Context())
+		}()
+		for l.State()[1].InFlight == 0 {
+			time.Sleep(time.Millisecond)
+		}
+
+		ctx, cancel := context.WithTimeout(t.Context(), 20*time.Millisecond)
+		defer cancel()
+		_, err := c.ListDatasets(ctx)
+		is.True(t, errors.Is(err, context.DeadlineExceeded))
+	})
+}
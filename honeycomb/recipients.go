@@ -0,0 +1,161 @@
+package honeycomb
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+)
+
+// Recipient is a notification target for triggers and burn alerts, such as an email address,
+// Slack channel, PagerDuty service, webhook, or Microsoft Teams channel.
+type Recipient struct {
+	ID        string           `json:"id,omitempty"`
+	Type      string           `json:"type"`
+	Details   RecipientDetails `json:"details"`
+	CreatedAt string           `json:"created_at,omitempty"`
+	UpdatedAt string           `json:"updated_at,omitempty"`
+}
+
+// RecipientDetails for a [Recipient]. Which fields are used depends on the recipient type.
+type RecipientDetails struct {
+	EmailAddress             string `json:"email_address,omitempty"`
+	SlackChannel             string `json:"slack_channel,omitempty"`
+	PagerDutyIntegrationKey  string `json:"pagerduty_integration_key,omitempty"`
+	PagerDutyIntegrationName string `json:"pagerduty_integration_name,omitempty"`
+	WebhookName              string `json:"webhook_name,omitempty"`
+	WebhookURL               string `json:"webhook_url,omitempty"`
+	WebhookSecret            string `json:"webhook_secret,omitempty"`
+}
+
+// Target returns a human-readable description of where the recipient's notifications go.
+func (r *Recipient) Target() string {
+	d := r.Details
+	for _, s := range []string{d.EmailAddress, d.SlackChannel, d.PagerDutyIntegrationName, d.WebhookName, d.WebhookURL} {
+		if s != "" {
+			return s
+		}
+	}
+	return ""
+}
+
+// NotificationRecipient is a reference to a [Recipient] from a trigger or burn alert.
+type NotificationRecipient struct {
+	ID     string `json:"id,omitempty"`
+	Type   string `json:"type,omitempty"`
+	Target string `json:"target,omitempty"`
+}
+
+// ListRecipients in the team.
+func (c *Client) ListRecipients(ctx context.Context) ([]Recipient, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+"/1/recipients", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := c.do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = res.Body.Close()
+	}()
+
+	var recipients []Recipient
+	if err := json.NewDecoder(res.Body).Decode(&recipients); err != nil {
+		return nil, err
+	}
+	return recipients, nil
+}
+
+// CreateRecipient from the given recipient. The ID is ignored.
+func (c *Client) CreateRecipient(ctx context.Context, recipient Recipient) (*Recipient, error) {
+	recipient.ID = ""
+	body, err := json.Marshal(recipient)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+"/1/recipients", bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := c.do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = res.Body.Close()
+	}()
+
+	var created Recipient
+	if err := json.NewDecoder(res.Body).Decode(&created); err != nil {
+		return nil, err
+	}
+	return &created, nil
+}
+
+// GetRecipient by ID.
+func (c *Client) GetRecipient(ctx context.Context, id string) (*Recipient, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+"/1/recipients/"+id, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := c.do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = res.Body.Close()
+	}()
+
+	var recipient Recipient
+	if err := json.NewDecoder(res.Body).Decode(&recipient); err != nil {
+		return nil, err
+	}
+	return &recipient, nil
+}
+
+// UpdateRecipient replaces the recipient with the given recipient's ID.
+func (c *Client) UpdateRecipient(ctx context.Context, recipient Recipient) (*Recipient, error) {
+	body, err := json.Marshal(recipient)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, c.baseURL+"/1/recipients/"+recipient.ID, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := c.do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = res.Body.Close()
+	}()
+
+	var updated Recipient
+	if err := json.NewDecoder(res.Body).Decode(&updated); err != nil {
+		return nil, err
+	}
+	return &updated, nil
+}
+
+// DeleteRecipient by ID.
+func (c *Client) DeleteRecipient(ctx context.Context, id string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodDelete, c.baseURL+"/1/recipients/"+id, nil)
+	if err != nil {
+		return err
+	}
+
+	res, err := c.do(req)
+	if err != nil {
+		return err
+	}
+	_ = res.Body.Close()
+	return nil
+}
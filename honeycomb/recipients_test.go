@@ -0,0 +1,115 @@
+package honeycomb_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"maragu.dev/is"
+
+	"github.com/maragudk/honeycomb-cli/honeycomb"
+)
+
+func TestClient_ListRecipients(t *testing.T) {
+	t.Run("returns recipients", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			is.Equal(t, "/1/recipients", r.URL.Path)
+			is.Equal(t, http.MethodGet, r.Method)
+
+			_, _ = w.Write([]byte(`[{"id": "r1", "type": "email", "details": {"email_address": "oncall@example.com"}}]`))
+		}))
+		defer server.Close()
+
+		c := honeycomb.NewClient("test-key", honeycomb.WithBaseURL(server.URL))
+		recipients, err := c.ListRecipients(t.Context())
+		is.NotError(t, err)
+		is.Equal(t, 1, len(recipients))
+		is.Equal(t, "oncall@example.com", recipients[0].Target())
+	})
+}
+
+func TestClient_CreateRecipient(t *testing.T) {
+	t.Run("creates a recipient", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			is.Equal(t, "/1/recipients", r.URL.Path)
+			is.Equal(t, http.MethodPost, r.Method)
+
+			var recipient honeycomb.Recipient
+			_ = json.NewDecoder(r.Body).Decode(&recipient)
+			is.Equal(t, "slack", recipient.Type)
+			is.Equal(t, "#alerts", recipient.Details.SlackChannel)
+
+			recipient.ID = "r1"
+			w.WriteHeader(http.StatusCreated)
+			_ = json.NewEncoder(w).Encode(recipient)
+		}))
+		defer server.Close()
+
+		c := honeycomb.NewClient("test-key", honeycomb.WithBaseURL(server.URL))
+		recipient, err := c.CreateRecipient(t.Context(), honeycomb.Recipient{
+			Type:    "slack",
+			Details: honeycomb.RecipientDetails{SlackChannel: "#alerts"},
+		})
+		is.NotError(t, err)
+		is.Equal(t, "r1", recipient.ID)
+	})
+}
+
+func TestClient_GetRecipient(t *testing.T) {
+	t.Run("returns a recipient", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			is.Equal(t, "/1/recipients/r1", r.URL.Path)
+			is.Equal(t, http.MethodGet, r.Method)
+
+			_, _ = w.Write([]byte(`{"id": "r1", "type": "pagerduty", "details": {"pagerduty_integration_key": "abc", "pagerduty_integration_name": "Payments"}}`))
+		}))
+		defer server.Close()
+
+		c := honeycomb.NewClient("test-key", honeycomb.WithBaseURL(server.URL))
+		recipient, err := c.GetRecipient(t.Context(), "r1")
+		is.NotError(t, err)
+		is.Equal(t, "pagerduty", recipient.Type)
+		is.Equal(t, "Payments", recipient.Target())
+	})
+}
+
+func TestClient_UpdateRecipient(t *testing.T) {
+	t.Run("updates a recipient", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			is.Equal(t, "/1/recipients/r1", r.URL.Path)
+			is.Equal(t, http.MethodPut, r.Method)
+
+			var recipient honeycomb.Recipient
+			_ = json.NewDecoder(r.Body).Decode(&recipient)
+			is.Equal(t, "https://example.com/new", recipient.Details.WebhookURL)
+
+			_ = json.NewEncoder(w).Encode(recipient)
+		}))
+		defer server.Close()
+
+		c := honeycomb.NewClient("test-key", honeycomb.WithBaseURL(server.URL))
+		recipient, err := c.UpdateRecipient(t.Context(), honeycomb.Recipient{
+			ID:      "r1",
+			Type:    "webhook",
+			Details: honeycomb.RecipientDetails{WebhookName: "Automation", WebhookURL: "https://example.com/new"},
+		})
+		is.NotError(t, err)
+		is.Equal(t, "r1", recipient.ID)
+	})
+}
+
+func TestClient_DeleteRecipient(t *testing.T) {
+	t.Run("deletes a recipient", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			is.Equal(t, "/1/recipients/r1", r.URL.Path)
+			is.Equal(t, http.MethodDelete, r.Method)
+			w.WriteHeader(http.StatusNoContent)
+		}))
+		defer server.Close()
+
+		c := honeycomb.NewClient("test-key", honeycomb.WithBaseURL(server.URL))
+		err := c.DeleteRecipient(t.Context(), "r1")
+		is.NotError(t, err)
+	})
+}
@@ -1,6 +1,7 @@
 package honeycomb
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
@@ -9,7 +10,7 @@ import (
 
 // SLO in Honeycomb.
 type SLO struct {
-	ID              string  `json:"id"`
+	ID              string  `json:"id,omitempty"`
 	Name            string  `json:"name"`
 	Description     string  `json:"description,omitempty"`
 	SLI             SLI     `json:"sli"`
@@ -72,3 +73,73 @@ func (c *Client) GetSLO(ctx context.Context, dataset, id string) (*SLO, error) {
 	}
 	return &slo, nil
 }
+
+// CreateSLO for a dataset. The ID is ignored.
+func (c *Client) CreateSLO(ctx context.Context, dataset string, slo SLO) (*SLO, error) {
+	slo.ID = ""
+	body, err := json.Marshal(slo)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+"/1/slos/"+dataset, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := c.do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = res.Body.Close()
+	}()
+
+	var created SLO
+	if err := json.NewDecoder(res.Body).Decode(&created); err != nil {
+		return nil, err
+	}
+	return &created, nil
+}
+
+// UpdateSLO replaces the SLO with the given SLO's ID.
+func (c *Client) UpdateSLO(ctx context.Context, dataset string, slo SLO) (*SLO, error) {
+	body, err := json.Marshal(slo)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, fmt.Sprintf("%v/1/slos/%v/%v", c.baseURL, dataset, slo.ID), bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := c.do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = res.Body.Close()
+	}()
+
+	var updated SLO
+	if err := json.NewDecoder(res.Body).Decode(&updated); err != nil {
+		return nil, err
+	}
+	return &updated, nil
+}
+
+// DeleteSLO by ID for a dataset.
+func (c *Client) DeleteSLO(ctx context.Context, dataset, id string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodDelete, fmt.Sprintf("%v/1/slos/%v/%v", c.baseURL, dataset, id), nil)
+	if err != nil {
+		return err
+	}
+
+	res, err := c.do(req)
+	if err != nil {
+		return err
+	}
+	_ = res.Body.Close()
+	return nil
+}
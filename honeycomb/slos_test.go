@@ -62,3 +62,66 @@ func TestClient_GetSLO(t *testing.T) {
 		is.Equal(t, 99.5, slo.TargetPercent())
 	})
 }
+
+func TestClient_CreateSLO(t *testing.T) {
+	t.Run("creates an SLO", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			is.Equal(t, "/1/slos/requests", r.URL.Path)
+			is.Equal(t, http.MethodPost, r.Method)
+
+			var slo honeycomb.SLO
+			_ = json.NewDecoder(r.Body).Decode(&slo)
+			is.Equal(t, "", slo.ID)
+			is.Equal(t, "sli_ok", slo.SLI.Alias)
+
+			slo.ID = "s1"
+			w.WriteHeader(http.StatusCreated)
+			_ = json.NewEncoder(w).Encode(slo)
+		}))
+		defer server.Close()
+
+		c := honeycomb.NewClient("test-key", honeycomb.WithBaseURL(server.URL))
+		slo, err := c.CreateSLO(t.Context(), "requests", honeycomb.SLO{
+			Name:             "Availability",
+			SLI:              honeycomb.SLI{Alias: "sli_ok"},
+			TargetPerMillion: 999000,
+			TimePeriodDays:   30,
+		})
+		is.NotError(t, err)
+		is.Equal(t, "s1", slo.ID)
+	})
+}
+
+func TestClient_UpdateSLO(t *testing.T) {
+	t.Run("updates an SLO", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			is.Equal(t, "/1/slos/requests/s1", r.URL.Path)
+			is.Equal(t, http.MethodPut, r.Method)
+
+			var slo honeycomb.SLO
+			_ = json.NewDecoder(r.Body).Decode(&slo)
+			_ = json.NewEncoder(w).Encode(slo)
+		}))
+		defer server.Close()
+
+		c := honeycomb.NewClient("test-key", honeycomb.WithBaseURL(server.URL))
+		slo, err := c.UpdateSLO(t.Context(), "requests", honeycomb.SLO{ID: "s1", Name: "Availability", TimePeriodDays: 7})
+		is.NotError(t, err)
+		is.Equal(t, 7, slo.TimePeriodDays)
+	})
+}
+
+func TestClient_DeleteSLO(t *testing.T) {
+	t.Run("deletes an SLO", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			is.Equal(t, "/1/slos/requests/s1", r.URL.Path)
+			is.Equal(t, http.MethodDelete, r.Method)
+			w.WriteHeader(http.StatusNoContent)
+		}))
+		defer server.Close()
+
+		c := honeycomb.NewClient("test-key", honeycomb.WithBaseURL(server.URL))
+		err := c.DeleteSLO(t.Context(), "requests", "s1")
+		is.NotError(t, err)
+	})
+}
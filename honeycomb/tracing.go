@@ -0,0 +1,99 @@
+package honeycomb
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.41.0"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+const tracerName = "github.com/maragudk/honeycomb-cli/honeycomb"
+
+// rateLimitWaitKey is the time a request waited for the [RateLimiter], in milliseconds.
+const rateLimitWaitKey = attribute.Key("honeycomb.rate_limit.wait_ms")
+
+// WithTracerProvider traces every API request with a client span from the provider.
+// Without it, requests aren't traced.
+func WithTracerProvider(tp trace.TracerProvider) Option {
+	return func(c *Client) {
+		c.tracer = tp.Tracer(tracerName)
+	}
+}
+
+var noopTracer = noop.NewTracerProvider().Tracer(tracerName)
+
+type attemptContextKey struct{}
+
+// WithAttempt marks requests made with the context as the nth attempt at sending the same request,
+// counting from 0. Spans of resent requests get the number of earlier attempts as their resend count.
+func WithAttempt(ctx context.Context, n int) context.Context {
+	return context.WithValue(ctx, attemptContextKey{}, n)
+}
+
+// startSpan for an API request, named after its method and route template, like "GET /1/triggers/{dataset}/{id}".
+func (c *Client) startSpan(req *http.Request) (*http.Request, trace.Span) {
+	name := req.Method
+	attrs := []attribute.KeyValue{
+		semconv.HTTPRequestMethodKey.String(req.Method),
+		semconv.ServerAddress(req.URL.Hostname()),
+	}
+	if n, _ := req.Context().Value(attemptContextKey{}).(int); n > 0 {
+		attrs = append(attrs, semconv.HTTPRequestResendCount(n))
+	}
+	if route := routeTemplate(req.URL.Path); route != "" {
+		name += " " + route
+		attrs = append(attrs, semconv.URLTemplate(route))
+	}
+
+	ctx, span := c.tracer.Start(req.Context(), name, trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(attrs...))
+	return req.WithContext(ctx), span
+}
+
+// endSpan with the response status code, or the error if there's no response.
+func endSpan(span trace.Span, statusCode int, err error) {
+	switch {
+	case err != nil:
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		span.SetAttributes(semconv.ErrorTypeKey.String(fmt.Sprintf("%T", err)))
+	case statusCode >= 400:
+		span.SetStatus(codes.Error, http.StatusText(statusCode))
+		span.SetAttributes(semconv.ErrorTypeKey.String(strconv.Itoa(statusCode)))
+	}
+	if statusCode > 0 {
+		span.SetAttributes(semconv.HTTPResponseStatusCode(statusCode))
+	}
+	span.End()
+}
+
+// routeTemplate of an API path, like "/1/triggers/{dataset}/{id}" for "/1/triggers/requests/abc123",
+// so that spans for the same endpoint can be grouped. Paths outside the API are empty.
+func routeTemplate(path string) string {
+	parts := strings.Split(strings.Trim(path, "/"), "/")
+	if len(parts) < 2 || parts[0] != "1" {
+		return ""
+	}
+
+	params := []string{"{dataset}", "{id}"}
+	switch parts[1] {
+	case "auth", "boards", "recipients":
+		params = []string{"{id}"}
+	case "datasets":
+		params = []string{"{dataset}"}
+	}
+	for i := 2; i < len(parts); i++ {
+		if i-2 < len(params) {
+			parts[i] = params[i-2]
+		} else {
+			parts[i] = "{param}"
+		}
+	}
+	return "/" + strings.Join(parts, "/")
+}
@@ -0,0 +1,127 @@
+package honeycomb_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+	"maragu.dev/is"
+
+	"github.com/maragudk/honeycomb-cli/honeycomb"
+)
+
+func newTracedClient(t *testing.T, baseURL string, opts ...honeycomb.Option) (*honeycomb.Client, *tracetest.InMemoryExporter) {
+	t.Helper()
+	exporter := tracetest.NewInMemoryExporter()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	opts = append([]honeycomb.Option{honeycomb.WithBaseURL(baseURL), honeycomb.WithTracerProvider(tp)}, opts...)
+	return honeycomb.NewClient("test-key", opts...), exporter
+}
+
+func spanAttributes(span tracetest.SpanStub) map[attribute.Key]attribute.Value {
+	attrs := map[attribute.Key]attribute.Value{}
+	for _, kv := range span.Attributes {
+		attrs[kv.Key] = kv.Value
+	}
+	return attrs
+}
+
+func TestClient_Tracing(t *testing.T) {
+	t.Run("traces each request with its method, route, and status", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_ = json.NewEncoder(w).Encode(honeycomb.Trigger{ID: "t1"})
+		}))
+		defer server.Close()
+
+		c, exporter := newTracedClient(t, server.URL)
+		_, err := c.GetTrigger(t.Context(), "requests", "t1")
+		is.NotError(t, err)
+
+		spans := exporter.GetSpans()
+		is.Equal(t, 1, len(spans))
+		is.Equal(t, "GET /1/triggers/{dataset}/{id}", spans[0].Name)
+		is.Equal(t, trace.SpanKindClient, spans[0].SpanKind)
+		is.Equal(t, codes.Unset, spans[0].Status.Code)
+
+		attrs := spanAttributes(spans[0])
+		is.Equal(t, "GET", attrs["http.request.method"].AsString())
+		is.Equal(t, "/1/triggers/{dataset}/{id}", attrs["url.template"].AsString())
+		is.Equal(t, int64(200), attrs["http.response.status_code"].AsInt64())
+		is.Equal(t, "127.0.0.1", attrs["server.address"].AsString())
+		_, ok := attrs["http.request.resend_count"]
+		is.True(t, !ok)
+	})
+
+	t.Run("sets the resend count on resent requests", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_ = json.NewEncoder(w).Encode(honeycomb.Trigger{ID: "t1"})
+		}))
+		defer server.Close()
+
+		c, exporter := newTracedClient(t, server.URL)
+		_, err := c.GetTrigger(honeycomb.WithAttempt(t.Context(), 2), "requests", "t1")
+		is.NotError(t, err)
+
+		spans := exporter.GetSpans()
+		is.Equal(t, 1, len(spans))
+		is.Equal(t, int64(2), spanAttributes(spans[0])["http.request.resend_count"].AsInt64())
+	})
+
+	t.Run("marks failed requests as errors", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			http.Error(w, `{"error": "not found"}`, http.StatusNotFound)
+		}))
+		defer server.Close()
+
+		c, exporter := newTracedClient(t, server.URL)
+		_, err := c.GetBoard(t.Context(), "b1")
+		is.True(t, err != nil)
+
+		spans := exporter.GetSpans()
+		is.Equal(t, 1, len(spans))
+		is.Equal(t, "GET /1/boards/{id}", spans[0].Name)
+		is.Equal(t, codes.Error, spans[0].Status.Code)
+
+		attrs := spanAttributes(spans[0])
+		is.Equal(t, int64(404), attrs["http.response.status_code"].AsInt64())
+		is.Equal(t, "404", attrs["error.type"].AsString())
+	})
+
+	t.Run("traces every request of a query under the caller's span", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch {
+			case r.Method == http.MethodPost && r.URL.Path == "/1/queries/requests":
+				_ = json.NewEncoder(w).Encode(honeycomb.QueryResponse{ID: "q1"})
+			case r.Method == http.MethodPost && r.URL.Path == "/1/query_results/requests":
+				_ = json.NewEncoder(w).Encode(honeycomb.QueryResult{ID: "r1", Complete: true})
+			}
+		}))
+		defer server.Close()
+
+		l := honeycomb.NewRateLimiter(honeycomb.RateLimits{Default: honeycomb.RateLimit{RequestsPerSecond: 100}})
+		c, exporter := newTracedClient(t, server.URL, honeycomb.WithRateLimiter(l))
+
+		tp := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+		ctx, parent := tp.Tracer("test").Start(t.Context(), "parent")
+		_, err := c.RunQuery(ctx, "requests", honeycomb.QuerySpec{})
+		is.NotError(t, err)
+		parent.End()
+
+		spans := exporter.GetSpans()
+		is.Equal(t, 3, len(spans))
+		is.Equal(t, "POST /1/queries/{dataset}", spans[0].Name)
+		is.Equal(t, "POST /1/query_results/{dataset}", spans[1].Name)
+		for _, span := range spans[:2] {
+			is.Equal(t, parent.SpanContext().SpanID(), span.Parent.SpanID())
+			is.Equal(t, parent.SpanContext().TraceID(), span.SpanContext.TraceID())
+			_, ok := spanAttributes(span)["honeycomb.rate_limit.wait_ms"]
+			is.True(t, ok)
+		}
+	})
+}
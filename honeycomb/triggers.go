@@ -1,6 +1,7 @@
 package honeycomb
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
@@ -9,14 +10,18 @@ import (
 
 // Trigger (alert) in Honeycomb.
 type Trigger struct {
-	ID          string           `json:"id"`
-	Name        string           `json:"name"`
-	Description string           `json:"description,omitempty"`
-	Disabled    bool             `json:"disabled"`
-	Frequency   int              `json:"frequency,omitempty"`
-	Threshold   TriggerThreshold `json:"threshold"`
-	CreatedAt   string           `json:"created_at,omitempty"`
-	UpdatedAt   string           `json:"updated_at,omitempty"`
+	ID          string                  `json:"id,omitempty"`
+	Name        string                  `json:"name"`
+	Description string                  `json:"description,omitempty"`
+	Disabled    bool                    `json:"disabled"`
+	Frequency   int                     `json:"frequency,omitempty"`
+	AlertType   string                  `json:"alert_type,omitempty"`
+	Threshold   TriggerThreshold        `json:"threshold"`
+	Query       *QuerySpec              `json:"query,omitempty"`
+	QueryID     string                  `json:"query_id,omitempty"`
+	Recipients  []NotificationRecipient `json:"recipients,omitempty"`
+	CreatedAt   string                  `json:"created_at,omitempty"`
+	UpdatedAt   string                  `json:"updated_at,omitempty"`
 }
 
 // TriggerThreshold defines when a trigger fires.
@@ -25,6 +30,22 @@ type TriggerThreshold struct {
 	Value float64 `json:"value"`
 }
 
+// Exceeded reports whether the value crosses the threshold, which is when the trigger fires.
+func (t TriggerThreshold) Exceeded(value float64) bool {
+	switch t.Op {
+	case ">":
+		return value > t.Value
+	case ">=":
+		return value >= t.Value
+	case "<":
+		return value < t.Value
+	case "<=":
+		return value <= t.Value
+	default:
+		return false
+	}
+}
+
 // ListTriggers for a dataset.
 func (c *Client) ListTriggers(ctx context.Context, dataset string) ([]Trigger, error) {
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+"/1/triggers/"+dataset, nil)
@@ -68,3 +89,73 @@ func (c *Client) GetTrigger(ctx context.Context, dataset, id string) (*Trigger,
 	}
 	return &trigger, nil
 }
+
+// CreateTrigger for a dataset. The ID is ignored.
+func (c *Client) CreateTrigger(ctx context.Context, dataset string, trigger Trigger) (*Trigger, error) {
+	trigger.ID = ""
+	body, err := json.Marshal(trigger)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+"/1/triggers/"+dataset, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := c.do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = res.Body.Close()
+	}()
+
+	var created Trigger
+	if err := json.NewDecoder(res.Body).Decode(&created); err != nil {
+		return nil, err
+	}
+	return &created, nil
+}
+
+// UpdateTrigger replaces the trigger with the given trigger's ID.
+func (c *Client) UpdateTrigger(ctx context.Context, dataset string, trigger Trigger) (*Trigger, error) {
+	body, err := json.Marshal(trigger)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, fmt.Sprintf("%v/1/triggers/%v/%v", c.baseURL, dataset, trigger.ID), bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := c.do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = res.Body.Close()
+	}()
+
+	var updated Trigger
+	if err := json.NewDecoder(res.Body).Decode(&updated); err != nil {
+		return nil, err
+	}
+	return &updated, nil
+}
+
+// DeleteTrigger by ID for a dataset.
+func (c *Client) DeleteTrigger(ctx context.Context, dataset, id string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodDelete, fmt.Sprintf("%v/1/triggers/%v/%v", c.baseURL, dataset, id), nil)
+	if err != nil {
+		return err
+	}
+
+	res, err := c.do(req)
+	if err != nil {
+		return err
+	}
+	_ = res.Body.Close()
+	return nil
+}
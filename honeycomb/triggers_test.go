@@ -2,6 +2,7 @@ package honeycomb_test
 
 import (
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -73,3 +74,90 @@ func TestClient_GetTrigger(t *testing.T) {
 		is.Equal(t, 300, trigger.Frequency)
 	})
 }
+
+func TestClient_CreateTrigger(t *testing.T) {
+	t.Run("creates a trigger with an inline query", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			is.Equal(t, "/1/triggers/requests", r.URL.Path)
+			is.Equal(t, http.MethodPost, r.Method)
+
+			var trigger honeycomb.Trigger
+			_ = json.NewDecoder(r.Body).Decode(&trigger)
+			is.Equal(t, "", trigger.ID)
+			is.Equal(t, "COUNT", trigger.Query.Calculations[0].Op)
+			is.Equal(t, "r1", trigger.Recipients[0].ID)
+
+			trigger.ID = "t1"
+			w.WriteHeader(http.StatusCreated)
+			_ = json.NewEncoder(w).Encode(trigger)
+		}))
+		defer server.Close()
+
+		c := honeycomb.NewClient("test-key", honeycomb.WithBaseURL(server.URL))
+		trigger, err := c.CreateTrigger(t.Context(), "requests", honeycomb.Trigger{
+			Name:       "Errors",
+			Threshold:  honeycomb.TriggerThreshold{Op: ">", Value: 10},
+			Query:      &honeycomb.QuerySpec{Calculations: []honeycomb.Calculation{{Op: "COUNT"}}},
+			Recipients: []honeycomb.NotificationRecipient{{ID: "r1"}},
+		})
+		is.NotError(t, err)
+		is.Equal(t, "t1", trigger.ID)
+	})
+}
+
+func TestClient_UpdateTrigger(t *testing.T) {
+	t.Run("updates a trigger", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			is.Equal(t, "/1/triggers/requests/t1", r.URL.Path)
+			is.Equal(t, http.MethodPut, r.Method)
+
+			var trigger honeycomb.Trigger
+			_ = json.NewDecoder(r.Body).Decode(&trigger)
+			_ = json.NewEncoder(w).Encode(trigger)
+		}))
+		defer server.Close()
+
+		c := honeycomb.NewClient("test-key", honeycomb.WithBaseURL(server.URL))
+		trigger, err := c.UpdateTrigger(t.Context(), "requests", honeycomb.Trigger{ID: "t1", Name: "Errors", Disabled: true})
+		is.NotError(t, err)
+		is.True(t, trigger.Disabled)
+	})
+}
+
+func TestClient_DeleteTrigger(t *testing.T) {
+	t.Run("deletes a trigger", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			is.Equal(t, "/1/triggers/requests/t1", r.URL.Path)
+			is.Equal(t, http.MethodDelete, r.Method)
+			w.WriteHeader(http.StatusNoContent)
+		}))
+		defer server.Close()
+
+		c := honeycomb.NewClient("test-key", honeycomb.WithBaseURL(server.URL))
+		err := c.DeleteTrigger(t.Context(), "requests", "t1")
+		is.NotError(t, err)
+	})
+}
+
+func TestTriggerThreshold_Exceeded(t *testing.T) {
+	tests := []struct {
+		op    string
+		value float64
+		want  bool
+	}{
+		{">", 11, true},
+		{">", 10, false},
+		{">=", 10, true},
+		{"<", 9, true},
+		{"<", 10, false},
+		{"<=", 10, true},
+		{"=", 10, false},
+	}
+
+	for _, test := range tests {
+		t.Run(fmt.Sprintf("%v %v", test.op, test.value), func(t *testing.T) {
+			threshold := honeycomb.TriggerThreshold{Op: test.op, Value: 10}
+			is.Equal(t, test.want, threshold.Exceeded(test.value))
+		})
+	}
+}
@@ -0,0 +1,99 @@
+package honeycomb
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// Webhook notification kinds.
+const (
+	WebhookKindTrigger   = "trigger"
+	WebhookKindBurnAlert = "burn_alert"
+)
+
+// WebhookNotification is a notification sent to a webhook [Recipient]. Exactly one of Trigger and BurnAlert is set.
+type WebhookNotification struct {
+	Kind      string                 `json:"kind"`
+	Trigger   *TriggerNotification   `json:"trigger,omitempty"`
+	BurnAlert *BurnAlertNotification `json:"burn_alert,omitempty"`
+}
+
+// TriggerNotification is sent when a trigger fires or resolves.
+type TriggerNotification struct {
+	Version               string               `json:"version,omitempty"`
+	ID                    string               `json:"id"`
+	Name                  string               `json:"name"`
+	Status                string               `json:"status"`
+	Summary               string               `json:"summary,omitempty"`
+	Description           string               `json:"description,omitempty"`
+	Operator              string               `json:"operator,omitempty"`
+	Threshold             float64              `json:"threshold"`
+	ResultURL             string               `json:"result_url,omitempty"`
+	ResultGroups          []TriggerResultGroup `json:"result_groups,omitempty"`
+	ResultGroupsTriggered []TriggerResultGroup `json:"result_groups_triggered,omitempty"`
+	IsTest                bool                 `json:"is_test,omitempty"`
+}
+
+// TriggerResultGroup is the result for one group of a trigger query's breakdowns.
+type TriggerResultGroup struct {
+	Group  map[string]any `json:"Group"`
+	Result float64        `json:"Result"`
+}
+
+// Fired reports whether the trigger fired, as opposed to resolved.
+func (n *TriggerNotification) Fired() bool {
+	return n.Status == "TRIGGERED"
+}
+
+// BurnAlertNotification is sent when an SLO's burn alert fires or resolves.
+type BurnAlertNotification struct {
+	Version                               string  `json:"version,omitempty"`
+	ID                                    string  `json:"id"`
+	Name                                  string  `json:"name"`
+	AlertType                             string  `json:"type"`
+	Status                                string  `json:"status"`
+	Summary                               string  `json:"summary,omitempty"`
+	Description                           string  `json:"description,omitempty"`
+	ExhaustionMinutes                     int     `json:"exhaustion_minutes,omitempty"`
+	BudgetRateWindowMinutes               int     `json:"budget_rate_window_minutes,omitempty"`
+	BudgetRateDecreaseThresholdPerMillion int     `json:"budget_rate_decrease_threshold_per_million,omitempty"`
+	BudgetRemaining                       float64 `json:"budget_remaining,omitempty"`
+	ResultURL                             string  `json:"result_url,omitempty"`
+	IsTest                                bool    `json:"is_test,omitempty"`
+}
+
+// Fired reports whether the burn alert fired, as opposed to resolved.
+func (n *BurnAlertNotification) Fired() bool {
+	return n.Status == "TRIGGERED"
+}
+
+// webhookPayload has the fields needed to tell notification kinds apart.
+type webhookPayload struct {
+	Type string `json:"type"`
+}
+
+// ParseWebhookNotification from a webhook request body.
+func ParseWebhookNotification(body []byte) (*WebhookNotification, error) {
+	var p webhookPayload
+	if err := json.Unmarshal(body, &p); err != nil {
+		return nil, fmt.Errorf("parsing webhook payload: %w", err)
+	}
+
+	switch p.Type {
+	case "exhaustion_time", "budget_rate":
+		var n BurnAlertNotification
+		if err := json.Unmarshal(body, &n); err != nil {
+			return nil, fmt.Errorf("parsing burn alert notification: %w", err)
+		}
+		return &WebhookNotification{Kind: WebhookKindBurnAlert, BurnAlert: &n}, nil
+	default:
+		var n TriggerNotification
+		if err := json.Unmarshal(body, &n); err != nil {
+			return nil, fmt.Errorf("parsing trigger notification: %w", err)
+		}
+		if n.ID == "" || n.Status == "" {
+			return nil, fmt.Errorf("parsing trigger notification: missing id or status")
+		}
+		return &WebhookNotification{Kind: WebhookKindTrigger, Trigger: &n}, nil
+	}
+}
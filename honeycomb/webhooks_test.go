@@ -0,0 +1,60 @@
+package honeycomb_test
+
+import (
+	"testing"
+
+	"maragu.dev/is"
+
+	"github.com/maragudk/honeycomb-cli/honeycomb"
+)
+
+func TestParseWebhookNotification(t *testing.T) {
+	t.Run("parses a fired trigger", func(t *testing.T) {
+		body := `{
+			"version": "v0.1.0",
+			"shared_secret": "s3cret",
+			"id": "t1",
+			"name": "High error rate",
+			"status": "TRIGGERED",
+			"summary": "Triggered: High error rate",
+			"operator": "greater than",
+			"threshold": 10,
+			"result_url": "https://ui.honeycomb.io/result",
+			"result_groups_triggered": [{"Group": {"service": "api"}, "Result": 42}]
+		}`
+
+		n, err := honeycomb.ParseWebhookNotification([]byte(body))
+		is.NotError(t, err)
+		is.Equal(t, honeycomb.WebhookKindTrigger, n.Kind)
+		is.True(t, n.BurnAlert == nil)
+		is.True(t, n.Trigger.Fired())
+		is.Equal(t, 10.0, n.Trigger.Threshold)
+		is.Equal(t, 42.0, n.Trigger.ResultGroupsTriggered[0].Result)
+	})
+
+	t.Run("parses a resolved trigger", func(t *testing.T) {
+		n, err := honeycomb.ParseWebhookNotification([]byte(`{"id": "t1", "name": "High error rate", "status": "OK"}`))
+		is.NotError(t, err)
+		is.True(t, !n.Trigger.Fired())
+	})
+
+	t.Run("parses a burn alert", func(t *testing.T) {
+		body := `{"id": "s1", "name": "Availability", "type": "exhaustion_time", "status": "TRIGGERED", "exhaustion_minutes": 240}`
+
+		n, err := honeycomb.ParseWebhookNotification([]byte(body))
+		is.NotError(t, err)
+		is.Equal(t, honeycomb.WebhookKindBurnAlert, n.Kind)
+		is.True(t, n.BurnAlert.Fired())
+		is.Equal(t, 240, n.BurnAlert.ExhaustionMinutes)
+	})
+
+	t.Run("errors on invalid JSON", func(t *testing.T) {
+		_, err := honeycomb.ParseWebhookNotification([]byte(`nope`))
+		is.True(t, err != nil)
+	})
+
+	t.Run("errors on a payload without id or status", func(t *testing.T) {
+		_, err := honeycomb.ParseWebhookNotification([]byte(`{"name": "x"}`))
+		is.True(t, err != nil)
+	})
+}